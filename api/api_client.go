@@ -1,9 +1,9 @@
 package api
 
 import (
-	"fmt"
+	"context"
 	"github.com/baotingfang/go-pivnet-client/gp"
-	"github.com/baotingfang/go-pivnet-client/vlog"
+	"github.com/baotingfang/go-pivnet-client/utils"
 	"github.com/baotingfang/go-pivnet-client/wrapper"
 	semver "github.com/cppforlife/go-semi-semantic/version"
 	"github.com/pivotal-cf/go-pivnet/v4"
@@ -11,23 +11,41 @@ import (
 	"strconv"
 )
 
+// FileTransferInProgress is the file transfer status of a product file
+// while pivnet is still copying it from s3
+const FileTransferInProgress = "in_progress"
+
 //go:generate counterfeiter . AccessClient
 
 type AccessClient interface {
-	GetAllReleases() ([]pivnet.Release, error)
-	GetLatestPublicReleaseByReleaseType(gpdbMajorVersion int, releaseType pivnet.ReleaseType) (release pivnet.Release, err error)
-	CreateRelease(releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error)
-	CreateFileGroup(groupName string) (pivnet.FileGroup, error)
-	CreateFederationToken() (pivnet.FederationToken, error)
-	CreateProductFile(productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)
-	DeleteProductFile(productFileId int) (pivnet.ProductFile, error)
-	AddProductFileToFileGroup(productFileId, fileGroupId int) error
-	AddProductFileToRelease(productFileId, releaseId int) error
-	AddFileGroupToRelease(fileGroupId, releaseId int) error
-	UpdateRelease(release pivnet.Release) (pivnet.Release, error)
-	FileTransferStatusInProgress(productFileId int) bool
+	GetAllReleases(ctx context.Context) ([]pivnet.Release, error)
+	GetReleaseByVersion(ctx context.Context, version string) (pivnet.Release, error)
+	GetLatestPublicReleaseByReleaseType(ctx context.Context, gpdbMajorVersion int, releaseType pivnet.ReleaseType) (release pivnet.Release, err error)
+	CreateRelease(ctx context.Context, releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error)
+	DeleteRelease(ctx context.Context, release pivnet.Release) error
+	GetAllFileGroups(ctx context.Context) ([]pivnet.FileGroup, error)
+	GetFileGroupsForRelease(ctx context.Context, releaseId int) ([]pivnet.FileGroup, error)
+	CreateFileGroup(ctx context.Context, groupName string) (pivnet.FileGroup, error)
+	DeleteFileGroup(ctx context.Context, fileGroupId int) (pivnet.FileGroup, error)
+	CreateFederationToken(ctx context.Context) (pivnet.FederationToken, error)
+	GetAllProductFiles(ctx context.Context) ([]pivnet.ProductFile, error)
+	GetProductFile(ctx context.Context, productFileId int) (pivnet.ProductFile, error)
+	GetProductFilesForRelease(ctx context.Context, releaseId int) ([]pivnet.ProductFile, error)
+	CreateProductFile(ctx context.Context, productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)
+	DeleteProductFile(ctx context.Context, productFileId int) (pivnet.ProductFile, error)
+	AddProductFileToFileGroup(ctx context.Context, productFileId, fileGroupId int) error
+	AddProductFileToRelease(ctx context.Context, productFileId, releaseId int) error
+	AddFileGroupToRelease(ctx context.Context, fileGroupId, releaseId int) error
+	UpdateRelease(ctx context.Context, release pivnet.Release) (pivnet.Release, error)
+	FileTransferStatusInProgress(ctx context.Context, productFileId int) (bool, error)
+	GetAllUserGroups(ctx context.Context) ([]pivnet.UserGroup, error)
+	GetUserGroupsForRelease(ctx context.Context, releaseId int) ([]pivnet.UserGroup, error)
+	AddUserGroupToRelease(ctx context.Context, userGroupId, releaseId int) error
 }
 
+// NotFoundError is returned when the object can not be found on pivnet
+type NotFoundError = utils.NotFoundError
+
 type Client struct {
 	ProductSlug   string
 	UaaFreshToken string
@@ -42,48 +60,103 @@ func NewApiClient(context gp.Context) AccessClient {
 	}
 }
 
-func (c Client) CreateRelease(releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error) {
-	return c.pivnetClient.CreateRelease(releaseConfig)
+func (c Client) CreateRelease(ctx context.Context, releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error) {
+	return c.pivnetClient.CreateRelease(ctx, releaseConfig)
+}
+
+func (c Client) DeleteRelease(ctx context.Context, release pivnet.Release) error {
+	return c.pivnetClient.DeleteRelease(ctx, c.ProductSlug, release)
+}
+
+func (c Client) GetAllFileGroups(ctx context.Context) ([]pivnet.FileGroup, error) {
+	return c.pivnetClient.GetAllFileGroups(ctx, c.ProductSlug)
+}
+
+func (c Client) GetFileGroupsForRelease(ctx context.Context, releaseId int) ([]pivnet.FileGroup, error) {
+	return c.pivnetClient.GetFileGroupsForRelease(ctx, c.ProductSlug, releaseId)
+}
+
+func (c Client) CreateFileGroup(ctx context.Context, groupName string) (pivnet.FileGroup, error) {
+	return c.pivnetClient.CreateFileGroup(ctx, c.ProductSlug, groupName)
+}
+
+func (c Client) DeleteFileGroup(ctx context.Context, fileGroupId int) (pivnet.FileGroup, error) {
+	return c.pivnetClient.DeleteFileGroup(ctx, c.ProductSlug, fileGroupId)
+}
+
+func (c Client) CreateFederationToken(ctx context.Context) (pivnet.FederationToken, error) {
+	return c.pivnetClient.CreateFederationToken(ctx, c.ProductSlug)
+}
+
+func (c Client) GetAllProductFiles(ctx context.Context) ([]pivnet.ProductFile, error) {
+	return c.pivnetClient.GetAllProductFiles(ctx, c.ProductSlug)
 }
 
-func (c Client) CreateFileGroup(groupName string) (pivnet.FileGroup, error) {
-	return c.pivnetClient.CreateFileGroup(c.ProductSlug, groupName)
+func (c Client) GetProductFile(ctx context.Context, productFileId int) (pivnet.ProductFile, error) {
+	return c.pivnetClient.GetProductFile(ctx, c.ProductSlug, productFileId)
 }
 
-func (c Client) CreateFederationToken() (pivnet.FederationToken, error) {
-	return c.pivnetClient.CreateFederationToken(c.ProductSlug)
+func (c Client) GetProductFilesForRelease(ctx context.Context, releaseId int) ([]pivnet.ProductFile, error) {
+	return c.pivnetClient.GetProductFilesForRelease(ctx, c.ProductSlug, releaseId)
 }
 
-func (c Client) CreateProductFile(productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
-	return c.pivnetClient.CreateProductFile(productFileConfig)
+func (c Client) CreateProductFile(ctx context.Context, productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+	return c.pivnetClient.CreateProductFile(ctx, productFileConfig)
 }
 
-func (c Client) DeleteProductFile(productFileId int) (pivnet.ProductFile, error) {
-	return c.pivnetClient.DeleteProductFile(c.ProductSlug, productFileId)
+func (c Client) DeleteProductFile(ctx context.Context, productFileId int) (pivnet.ProductFile, error) {
+	return c.pivnetClient.DeleteProductFile(ctx, c.ProductSlug, productFileId)
 }
 
-func (c Client) AddProductFileToFileGroup(productFileId, fileGroupId int) error {
-	return c.pivnetClient.AddProductFileToFileGroup(c.ProductSlug, productFileId, fileGroupId)
+func (c Client) AddProductFileToFileGroup(ctx context.Context, productFileId, fileGroupId int) error {
+	return c.pivnetClient.AddProductFileToFileGroup(ctx, c.ProductSlug, productFileId, fileGroupId)
 }
 
-func (c Client) AddProductFileToRelease(productFileId, releaseId int) error {
-	return c.pivnetClient.AddProductFileToRelease(c.ProductSlug, productFileId, releaseId)
+func (c Client) AddProductFileToRelease(ctx context.Context, productFileId, releaseId int) error {
+	return c.pivnetClient.AddProductFileToRelease(ctx, c.ProductSlug, productFileId, releaseId)
 }
 
-func (c Client) AddFileGroupToRelease(fileGroupId, releaseId int) error {
-	return c.pivnetClient.AddFileGroupToRelease(c.ProductSlug, fileGroupId, releaseId)
+func (c Client) AddFileGroupToRelease(ctx context.Context, fileGroupId, releaseId int) error {
+	return c.pivnetClient.AddFileGroupToRelease(ctx, c.ProductSlug, fileGroupId, releaseId)
 }
 
-func (c Client) UpdateRelease(release pivnet.Release) (pivnet.Release, error) {
-	return c.pivnetClient.UpdateRelease(c.ProductSlug, release)
+func (c Client) UpdateRelease(ctx context.Context, release pivnet.Release) (pivnet.Release, error) {
+	return c.pivnetClient.UpdateRelease(ctx, c.ProductSlug, release)
 }
 
-func (c Client) GetAllReleases() ([]pivnet.Release, error) {
-	return c.pivnetClient.GetAllReleases(c.ProductSlug)
+func (c Client) GetAllUserGroups(ctx context.Context) ([]pivnet.UserGroup, error) {
+	return c.pivnetClient.GetAllUserGroups(ctx)
+}
+
+func (c Client) GetUserGroupsForRelease(ctx context.Context, releaseId int) ([]pivnet.UserGroup, error) {
+	return c.pivnetClient.GetUserGroupsForRelease(ctx, c.ProductSlug, releaseId)
+}
+
+func (c Client) AddUserGroupToRelease(ctx context.Context, userGroupId, releaseId int) error {
+	return c.pivnetClient.AddUserGroupToRelease(ctx, c.ProductSlug, userGroupId, releaseId)
+}
+
+func (c Client) GetAllReleases(ctx context.Context) ([]pivnet.Release, error) {
+	return c.pivnetClient.GetAllReleases(ctx, c.ProductSlug)
+}
+
+func (c Client) GetReleaseByVersion(ctx context.Context, version string) (pivnet.Release, error) {
+	allReleases, err := c.GetAllReleases(ctx)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	for _, release := range allReleases {
+		if release.Version == version {
+			return release, nil
+		}
+	}
+
+	return pivnet.Release{}, utils.NewNotFoundError("can not find release. version: %s", version)
 }
 
-func (c Client) GetLatestPublicReleaseByReleaseType(gpdbMajorVersion int, releaseType pivnet.ReleaseType) (release pivnet.Release, err error) {
-	allReleases, err := c.GetAllReleases()
+func (c Client) GetLatestPublicReleaseByReleaseType(ctx context.Context, gpdbMajorVersion int, releaseType pivnet.ReleaseType) (release pivnet.Release, err error) {
+	allReleases, err := c.GetAllReleases(ctx)
 	if err != nil {
 		return pivnet.Release{}, err
 	}
@@ -118,15 +191,14 @@ func (c Client) GetLatestPublicReleaseByReleaseType(gpdbMajorVersion int, releas
 	}
 
 	return pivnet.Release{},
-		fmt.Errorf("can not found previous release. major version: %d, release type: %s",
+		utils.NewNotFoundError("can not found previous release. major version: %d, release type: %s",
 			gpdbMajorVersion, releaseType)
 }
 
-func (c Client) FileTransferStatusInProgress(productFileId int) bool {
-	pf, err := c.pivnetClient.GetProductFile(c.ProductSlug, productFileId)
+func (c Client) FileTransferStatusInProgress(ctx context.Context, productFileId int) (bool, error) {
+	pf, err := c.pivnetClient.GetProductFile(ctx, c.ProductSlug, productFileId)
 	if err != nil {
-		vlog.Error("can not find product file. id=%d", productFileId)
-		vlog.Fatal(err.Error())
+		return false, utils.NewRemoteApiError(err, "can not find product file. id=%d", productFileId)
 	}
-	return pf.FileTransferStatus == "in_progress"
+	return pf.FileTransferStatus == FileTransferInProgress, nil
 }
@@ -30,7 +30,7 @@ var _ = Describe("ApiClient", func() {
 			fakePivnetClient.GetAllReleasesReturns([]pivnet.Release{}, errors.New("failed get all releases"))
 			context.Client = &fakePivnetClient
 
-			r, err := apiClient.GetLatestPublicReleaseByReleaseType(4, config.MajorReleaseType)
+			r, err := apiClient.GetLatestPublicReleaseByReleaseType(ctx, 4, config.MajorReleaseType)
 			Expect(err).To(HaveOccurred())
 			Expect(r.Version).To(BeEmpty())
 			Expect(err.Error()).To(Equal("failed get all releases"))
@@ -40,7 +40,7 @@ var _ = Describe("ApiClient", func() {
 		It("Can not found previous release", func() {
 			context.Client = &fakePivnetClient
 
-			r, err := apiClient.GetLatestPublicReleaseByReleaseType(4, config.MajorReleaseType)
+			r, err := apiClient.GetLatestPublicReleaseByReleaseType(ctx, 4, config.MajorReleaseType)
 			Expect(err).To(HaveOccurred())
 			Expect(r.Version).To(BeEmpty())
 			Expect(err.Error()).To(Equal("can not found previous release. major version: 4, release type: Major Release"))
@@ -142,49 +142,85 @@ var _ = Describe("ApiClient", func() {
 			}, nil)
 
 			By("for gpdb4")
-			r, err := apiClient.GetLatestPublicReleaseByReleaseType(4, config.MinorReleaseType)
+			r, err := apiClient.GetLatestPublicReleaseByReleaseType(ctx, 4, config.MinorReleaseType)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r.Version).To(Equal("4.3.27.0"))
 
-			r, err = apiClient.GetLatestPublicReleaseByReleaseType(4, config.MaintenanceReleaseType)
+			r, err = apiClient.GetLatestPublicReleaseByReleaseType(ctx, 4, config.MaintenanceReleaseType)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r.Version).To(Equal("4.3.30.4"))
 
 			By("for gpdb5")
-			r, err = apiClient.GetLatestPublicReleaseByReleaseType(5, config.MajorReleaseType)
+			r, err = apiClient.GetLatestPublicReleaseByReleaseType(ctx, 5, config.MajorReleaseType)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r.Version).To(Equal("5.0.0"))
 
-			r, err = apiClient.GetLatestPublicReleaseByReleaseType(5, config.MinorReleaseType)
+			r, err = apiClient.GetLatestPublicReleaseByReleaseType(ctx, 5, config.MinorReleaseType)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r.Version).To(Equal("5.27.0"))
 
-			r, err = apiClient.GetLatestPublicReleaseByReleaseType(5, config.MaintenanceReleaseType)
+			r, err = apiClient.GetLatestPublicReleaseByReleaseType(ctx, 5, config.MaintenanceReleaseType)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r.Version).To(Equal("5.27.1"))
 
 			By("for gpdb6")
-			r, err = apiClient.GetLatestPublicReleaseByReleaseType(6, config.MajorReleaseType)
+			r, err = apiClient.GetLatestPublicReleaseByReleaseType(ctx, 6, config.MajorReleaseType)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r.Version).To(Equal("6.0.0"))
 
-			r, err = apiClient.GetLatestPublicReleaseByReleaseType(6, config.MinorReleaseType)
+			r, err = apiClient.GetLatestPublicReleaseByReleaseType(ctx, 6, config.MinorReleaseType)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r.Version).To(Equal("6.7.0"))
 
-			r, err = apiClient.GetLatestPublicReleaseByReleaseType(6, config.MaintenanceReleaseType)
+			r, err = apiClient.GetLatestPublicReleaseByReleaseType(ctx, 6, config.MaintenanceReleaseType)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(r.Version).To(Equal("6.7.1"))
 		})
 	})
 
+	Context("GetReleaseByVersion", func() {
+		It("found the release", func() {
+			fakePivnetClient.GetAllReleasesReturns([]pivnet.Release{
+				{ID: 1, Version: "6.6.0"},
+				{ID: 2, Version: "6.7.0"},
+			}, nil)
+
+			r, err := apiClient.GetReleaseByVersion(ctx, "6.7.0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.ID).To(Equal(2))
+			_, productSlug := fakePivnetClient.GetAllReleasesArgsForCall(0)
+			Expect(productSlug).To(Equal("fakeslug"))
+		})
+
+		It("can not found the release", func() {
+			fakePivnetClient.GetAllReleasesReturns([]pivnet.Release{
+				{ID: 1, Version: "6.6.0"},
+			}, nil)
+
+			r, err := apiClient.GetReleaseByVersion(ctx, "6.7.0")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("can not find release. version: 6.7.0"))
+			Expect(err).To(BeAssignableToTypeOf(NotFoundError{}))
+			Expect(r).To(Equal(pivnet.Release{}))
+		})
+
+		It("GetAllReleases failed", func() {
+			fakePivnetClient.GetAllReleasesReturns(nil, errors.New("failed get all releases"))
+
+			_, err := apiClient.GetReleaseByVersion(ctx, "6.7.0")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("failed get all releases"))
+		})
+	})
+
 	Context("FileTransferStatusInProgress", func() {
 		It("Test in progress", func() {
 			fakePivnetClient.GetProductFileReturns(pivnet.ProductFile{
 				FileTransferStatus: "in_progress",
 			}, nil)
 
-			result := apiClient.FileTransferStatusInProgress(1)
+			result, err := apiClient.FileTransferStatusInProgress(ctx, 1)
+			Expect(err).NotTo(HaveOccurred())
 			Expect(result).To(BeTrue())
 		})
 
@@ -193,7 +229,8 @@ var _ = Describe("ApiClient", func() {
 				FileTransferStatus: "not_in_progress",
 			}, nil)
 
-			result := apiClient.FileTransferStatusInProgress(1)
+			result, err := apiClient.FileTransferStatusInProgress(ctx, 1)
+			Expect(err).NotTo(HaveOccurred())
 			Expect(result).To(BeFalse())
 		})
 
@@ -201,10 +238,8 @@ var _ = Describe("ApiClient", func() {
 			fakePivnetClient.GetProductFileReturns(
 				pivnet.ProductFile{}, errors.New("can not find product"))
 
-			f := func() {
-				apiClient.FileTransferStatusInProgress(1)
-			}
-			Expect(f).To(PanicWith(`[Default Logger][FATAL] can not find product`))
+			_, err := apiClient.FileTransferStatusInProgress(ctx, 1)
+			Expect(err).To(MatchError("can not find product file. id=1: can not find product"))
 		})
 	})
 })
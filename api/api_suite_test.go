@@ -1,12 +1,16 @@
 package api_test
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
+// ctx is the context of the calls in the tests, which is never cancelled
+var ctx = context.Background()
+
 func TestApi(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Api Suite")
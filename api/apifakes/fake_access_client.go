@@ -2,6 +2,7 @@
 package apifakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/baotingfang/go-pivnet-client/api"
@@ -9,11 +10,12 @@ import (
 )
 
 type FakeAccessClient struct {
-	AddFileGroupToReleaseStub        func(int, int) error
+	AddFileGroupToReleaseStub        func(context.Context, int, int) error
 	addFileGroupToReleaseMutex       sync.RWMutex
 	addFileGroupToReleaseArgsForCall []struct {
-		arg1 int
+		arg1 context.Context
 		arg2 int
+		arg3 int
 	}
 	addFileGroupToReleaseReturns struct {
 		result1 error
@@ -21,11 +23,12 @@ type FakeAccessClient struct {
 	addFileGroupToReleaseReturnsOnCall map[int]struct {
 		result1 error
 	}
-	AddProductFileToFileGroupStub        func(int, int) error
+	AddProductFileToFileGroupStub        func(context.Context, int, int) error
 	addProductFileToFileGroupMutex       sync.RWMutex
 	addProductFileToFileGroupArgsForCall []struct {
-		arg1 int
+		arg1 context.Context
 		arg2 int
+		arg3 int
 	}
 	addProductFileToFileGroupReturns struct {
 		result1 error
@@ -33,11 +36,12 @@ type FakeAccessClient struct {
 	addProductFileToFileGroupReturnsOnCall map[int]struct {
 		result1 error
 	}
-	AddProductFileToReleaseStub        func(int, int) error
+	AddProductFileToReleaseStub        func(context.Context, int, int) error
 	addProductFileToReleaseMutex       sync.RWMutex
 	addProductFileToReleaseArgsForCall []struct {
-		arg1 int
+		arg1 context.Context
 		arg2 int
+		arg3 int
 	}
 	addProductFileToReleaseReturns struct {
 		result1 error
@@ -45,9 +49,23 @@ type FakeAccessClient struct {
 	addProductFileToReleaseReturnsOnCall map[int]struct {
 		result1 error
 	}
-	CreateFederationTokenStub        func() (pivnet.FederationToken, error)
+	AddUserGroupToReleaseStub        func(context.Context, int, int) error
+	addUserGroupToReleaseMutex       sync.RWMutex
+	addUserGroupToReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}
+	addUserGroupToReleaseReturns struct {
+		result1 error
+	}
+	addUserGroupToReleaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateFederationTokenStub        func(context.Context) (pivnet.FederationToken, error)
 	createFederationTokenMutex       sync.RWMutex
 	createFederationTokenArgsForCall []struct {
+		arg1 context.Context
 	}
 	createFederationTokenReturns struct {
 		result1 pivnet.FederationToken
@@ -57,10 +75,11 @@ type FakeAccessClient struct {
 		result1 pivnet.FederationToken
 		result2 error
 	}
-	CreateFileGroupStub        func(string) (pivnet.FileGroup, error)
+	CreateFileGroupStub        func(context.Context, string) (pivnet.FileGroup, error)
 	createFileGroupMutex       sync.RWMutex
 	createFileGroupArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	createFileGroupReturns struct {
 		result1 pivnet.FileGroup
@@ -70,10 +89,11 @@ type FakeAccessClient struct {
 		result1 pivnet.FileGroup
 		result2 error
 	}
-	CreateProductFileStub        func(pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)
+	CreateProductFileStub        func(context.Context, pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)
 	createProductFileMutex       sync.RWMutex
 	createProductFileArgsForCall []struct {
-		arg1 pivnet.CreateProductFileConfig
+		arg1 context.Context
+		arg2 pivnet.CreateProductFileConfig
 	}
 	createProductFileReturns struct {
 		result1 pivnet.ProductFile
@@ -83,10 +103,11 @@ type FakeAccessClient struct {
 		result1 pivnet.ProductFile
 		result2 error
 	}
-	CreateReleaseStub        func(pivnet.CreateReleaseConfig) (pivnet.Release, error)
+	CreateReleaseStub        func(context.Context, pivnet.CreateReleaseConfig) (pivnet.Release, error)
 	createReleaseMutex       sync.RWMutex
 	createReleaseArgsForCall []struct {
-		arg1 pivnet.CreateReleaseConfig
+		arg1 context.Context
+		arg2 pivnet.CreateReleaseConfig
 	}
 	createReleaseReturns struct {
 		result1 pivnet.Release
@@ -96,10 +117,25 @@ type FakeAccessClient struct {
 		result1 pivnet.Release
 		result2 error
 	}
-	DeleteProductFileStub        func(int) (pivnet.ProductFile, error)
+	DeleteFileGroupStub        func(context.Context, int) (pivnet.FileGroup, error)
+	deleteFileGroupMutex       sync.RWMutex
+	deleteFileGroupArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	deleteFileGroupReturns struct {
+		result1 pivnet.FileGroup
+		result2 error
+	}
+	deleteFileGroupReturnsOnCall map[int]struct {
+		result1 pivnet.FileGroup
+		result2 error
+	}
+	DeleteProductFileStub        func(context.Context, int) (pivnet.ProductFile, error)
 	deleteProductFileMutex       sync.RWMutex
 	deleteProductFileArgsForCall []struct {
-		arg1 int
+		arg1 context.Context
+		arg2 int
 	}
 	deleteProductFileReturns struct {
 		result1 pivnet.ProductFile
@@ -109,20 +145,62 @@ type FakeAccessClient struct {
 		result1 pivnet.ProductFile
 		result2 error
 	}
-	FileTransferStatusInProgressStub        func(int) bool
+	DeleteReleaseStub        func(context.Context, pivnet.Release) error
+	deleteReleaseMutex       sync.RWMutex
+	deleteReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 pivnet.Release
+	}
+	deleteReleaseReturns struct {
+		result1 error
+	}
+	deleteReleaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	FileTransferStatusInProgressStub        func(context.Context, int) (bool, error)
 	fileTransferStatusInProgressMutex       sync.RWMutex
 	fileTransferStatusInProgressArgsForCall []struct {
-		arg1 int
+		arg1 context.Context
+		arg2 int
 	}
 	fileTransferStatusInProgressReturns struct {
 		result1 bool
+		result2 error
 	}
 	fileTransferStatusInProgressReturnsOnCall map[int]struct {
 		result1 bool
+		result2 error
+	}
+	GetAllFileGroupsStub        func(context.Context) ([]pivnet.FileGroup, error)
+	getAllFileGroupsMutex       sync.RWMutex
+	getAllFileGroupsArgsForCall []struct {
+		arg1 context.Context
+	}
+	getAllFileGroupsReturns struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}
+	getAllFileGroupsReturnsOnCall map[int]struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}
+	GetAllProductFilesStub        func(context.Context) ([]pivnet.ProductFile, error)
+	getAllProductFilesMutex       sync.RWMutex
+	getAllProductFilesArgsForCall []struct {
+		arg1 context.Context
+	}
+	getAllProductFilesReturns struct {
+		result1 []pivnet.ProductFile
+		result2 error
 	}
-	GetAllReleasesStub        func() ([]pivnet.Release, error)
+	getAllProductFilesReturnsOnCall map[int]struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}
+	GetAllReleasesStub        func(context.Context) ([]pivnet.Release, error)
 	getAllReleasesMutex       sync.RWMutex
 	getAllReleasesArgsForCall []struct {
+		arg1 context.Context
 	}
 	getAllReleasesReturns struct {
 		result1 []pivnet.Release
@@ -132,11 +210,39 @@ type FakeAccessClient struct {
 		result1 []pivnet.Release
 		result2 error
 	}
-	GetLatestPublicReleaseByReleaseTypeStub        func(int, pivnet.ReleaseType) (pivnet.Release, error)
+	GetAllUserGroupsStub        func(context.Context) ([]pivnet.UserGroup, error)
+	getAllUserGroupsMutex       sync.RWMutex
+	getAllUserGroupsArgsForCall []struct {
+		arg1 context.Context
+	}
+	getAllUserGroupsReturns struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}
+	getAllUserGroupsReturnsOnCall map[int]struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}
+	GetFileGroupsForReleaseStub        func(context.Context, int) ([]pivnet.FileGroup, error)
+	getFileGroupsForReleaseMutex       sync.RWMutex
+	getFileGroupsForReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	getFileGroupsForReleaseReturns struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}
+	getFileGroupsForReleaseReturnsOnCall map[int]struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}
+	GetLatestPublicReleaseByReleaseTypeStub        func(context.Context, int, pivnet.ReleaseType) (pivnet.Release, error)
 	getLatestPublicReleaseByReleaseTypeMutex       sync.RWMutex
 	getLatestPublicReleaseByReleaseTypeArgsForCall []struct {
-		arg1 int
-		arg2 pivnet.ReleaseType
+		arg1 context.Context
+		arg2 int
+		arg3 pivnet.ReleaseType
 	}
 	getLatestPublicReleaseByReleaseTypeReturns struct {
 		result1 pivnet.Release
@@ -146,10 +252,67 @@ type FakeAccessClient struct {
 		result1 pivnet.Release
 		result2 error
 	}
-	UpdateReleaseStub        func(pivnet.Release) (pivnet.Release, error)
+	GetProductFileStub        func(context.Context, int) (pivnet.ProductFile, error)
+	getProductFileMutex       sync.RWMutex
+	getProductFileArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	getProductFileReturns struct {
+		result1 pivnet.ProductFile
+		result2 error
+	}
+	getProductFileReturnsOnCall map[int]struct {
+		result1 pivnet.ProductFile
+		result2 error
+	}
+	GetProductFilesForReleaseStub        func(context.Context, int) ([]pivnet.ProductFile, error)
+	getProductFilesForReleaseMutex       sync.RWMutex
+	getProductFilesForReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	getProductFilesForReleaseReturns struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}
+	getProductFilesForReleaseReturnsOnCall map[int]struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}
+	GetReleaseByVersionStub        func(context.Context, string) (pivnet.Release, error)
+	getReleaseByVersionMutex       sync.RWMutex
+	getReleaseByVersionArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getReleaseByVersionReturns struct {
+		result1 pivnet.Release
+		result2 error
+	}
+	getReleaseByVersionReturnsOnCall map[int]struct {
+		result1 pivnet.Release
+		result2 error
+	}
+	GetUserGroupsForReleaseStub        func(context.Context, int) ([]pivnet.UserGroup, error)
+	getUserGroupsForReleaseMutex       sync.RWMutex
+	getUserGroupsForReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 int
+	}
+	getUserGroupsForReleaseReturns struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}
+	getUserGroupsForReleaseReturnsOnCall map[int]struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}
+	UpdateReleaseStub        func(context.Context, pivnet.Release) (pivnet.Release, error)
 	updateReleaseMutex       sync.RWMutex
 	updateReleaseArgsForCall []struct {
-		arg1 pivnet.Release
+		arg1 context.Context
+		arg2 pivnet.Release
 	}
 	updateReleaseReturns struct {
 		result1 pivnet.Release
@@ -163,17 +326,18 @@ type FakeAccessClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeAccessClient) AddFileGroupToRelease(arg1 int, arg2 int) error {
+func (fake *FakeAccessClient) AddFileGroupToRelease(arg1 context.Context, arg2 int, arg3 int) error {
 	fake.addFileGroupToReleaseMutex.Lock()
 	ret, specificReturn := fake.addFileGroupToReleaseReturnsOnCall[len(fake.addFileGroupToReleaseArgsForCall)]
 	fake.addFileGroupToReleaseArgsForCall = append(fake.addFileGroupToReleaseArgsForCall, struct {
-		arg1 int
+		arg1 context.Context
 		arg2 int
-	}{arg1, arg2})
-	fake.recordInvocation("AddFileGroupToRelease", []interface{}{arg1, arg2})
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("AddFileGroupToRelease", []interface{}{arg1, arg2, arg3})
 	fake.addFileGroupToReleaseMutex.Unlock()
 	if fake.AddFileGroupToReleaseStub != nil {
-		return fake.AddFileGroupToReleaseStub(arg1, arg2)
+		return fake.AddFileGroupToReleaseStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -188,17 +352,17 @@ func (fake *FakeAccessClient) AddFileGroupToReleaseCallCount() int {
 	return len(fake.addFileGroupToReleaseArgsForCall)
 }
 
-func (fake *FakeAccessClient) AddFileGroupToReleaseCalls(stub func(int, int) error) {
+func (fake *FakeAccessClient) AddFileGroupToReleaseCalls(stub func(context.Context, int, int) error) {
 	fake.addFileGroupToReleaseMutex.Lock()
 	defer fake.addFileGroupToReleaseMutex.Unlock()
 	fake.AddFileGroupToReleaseStub = stub
 }
 
-func (fake *FakeAccessClient) AddFileGroupToReleaseArgsForCall(i int) (int, int) {
+func (fake *FakeAccessClient) AddFileGroupToReleaseArgsForCall(i int) (context.Context, int, int) {
 	fake.addFileGroupToReleaseMutex.RLock()
 	defer fake.addFileGroupToReleaseMutex.RUnlock()
 	argsForCall := fake.addFileGroupToReleaseArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeAccessClient) AddFileGroupToReleaseReturns(result1 error) {
@@ -224,17 +388,18 @@ func (fake *FakeAccessClient) AddFileGroupToReleaseReturnsOnCall(i int, result1
 	}{result1}
 }
 
-func (fake *FakeAccessClient) AddProductFileToFileGroup(arg1 int, arg2 int) error {
+func (fake *FakeAccessClient) AddProductFileToFileGroup(arg1 context.Context, arg2 int, arg3 int) error {
 	fake.addProductFileToFileGroupMutex.Lock()
 	ret, specificReturn := fake.addProductFileToFileGroupReturnsOnCall[len(fake.addProductFileToFileGroupArgsForCall)]
 	fake.addProductFileToFileGroupArgsForCall = append(fake.addProductFileToFileGroupArgsForCall, struct {
-		arg1 int
+		arg1 context.Context
 		arg2 int
-	}{arg1, arg2})
-	fake.recordInvocation("AddProductFileToFileGroup", []interface{}{arg1, arg2})
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("AddProductFileToFileGroup", []interface{}{arg1, arg2, arg3})
 	fake.addProductFileToFileGroupMutex.Unlock()
 	if fake.AddProductFileToFileGroupStub != nil {
-		return fake.AddProductFileToFileGroupStub(arg1, arg2)
+		return fake.AddProductFileToFileGroupStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -249,17 +414,17 @@ func (fake *FakeAccessClient) AddProductFileToFileGroupCallCount() int {
 	return len(fake.addProductFileToFileGroupArgsForCall)
 }
 
-func (fake *FakeAccessClient) AddProductFileToFileGroupCalls(stub func(int, int) error) {
+func (fake *FakeAccessClient) AddProductFileToFileGroupCalls(stub func(context.Context, int, int) error) {
 	fake.addProductFileToFileGroupMutex.Lock()
 	defer fake.addProductFileToFileGroupMutex.Unlock()
 	fake.AddProductFileToFileGroupStub = stub
 }
 
-func (fake *FakeAccessClient) AddProductFileToFileGroupArgsForCall(i int) (int, int) {
+func (fake *FakeAccessClient) AddProductFileToFileGroupArgsForCall(i int) (context.Context, int, int) {
 	fake.addProductFileToFileGroupMutex.RLock()
 	defer fake.addProductFileToFileGroupMutex.RUnlock()
 	argsForCall := fake.addProductFileToFileGroupArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeAccessClient) AddProductFileToFileGroupReturns(result1 error) {
@@ -285,17 +450,18 @@ func (fake *FakeAccessClient) AddProductFileToFileGroupReturnsOnCall(i int, resu
 	}{result1}
 }
 
-func (fake *FakeAccessClient) AddProductFileToRelease(arg1 int, arg2 int) error {
+func (fake *FakeAccessClient) AddProductFileToRelease(arg1 context.Context, arg2 int, arg3 int) error {
 	fake.addProductFileToReleaseMutex.Lock()
 	ret, specificReturn := fake.addProductFileToReleaseReturnsOnCall[len(fake.addProductFileToReleaseArgsForCall)]
 	fake.addProductFileToReleaseArgsForCall = append(fake.addProductFileToReleaseArgsForCall, struct {
-		arg1 int
+		arg1 context.Context
 		arg2 int
-	}{arg1, arg2})
-	fake.recordInvocation("AddProductFileToRelease", []interface{}{arg1, arg2})
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("AddProductFileToRelease", []interface{}{arg1, arg2, arg3})
 	fake.addProductFileToReleaseMutex.Unlock()
 	if fake.AddProductFileToReleaseStub != nil {
-		return fake.AddProductFileToReleaseStub(arg1, arg2)
+		return fake.AddProductFileToReleaseStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -310,17 +476,17 @@ func (fake *FakeAccessClient) AddProductFileToReleaseCallCount() int {
 	return len(fake.addProductFileToReleaseArgsForCall)
 }
 
-func (fake *FakeAccessClient) AddProductFileToReleaseCalls(stub func(int, int) error) {
+func (fake *FakeAccessClient) AddProductFileToReleaseCalls(stub func(context.Context, int, int) error) {
 	fake.addProductFileToReleaseMutex.Lock()
 	defer fake.addProductFileToReleaseMutex.Unlock()
 	fake.AddProductFileToReleaseStub = stub
 }
 
-func (fake *FakeAccessClient) AddProductFileToReleaseArgsForCall(i int) (int, int) {
+func (fake *FakeAccessClient) AddProductFileToReleaseArgsForCall(i int) (context.Context, int, int) {
 	fake.addProductFileToReleaseMutex.RLock()
 	defer fake.addProductFileToReleaseMutex.RUnlock()
 	argsForCall := fake.addProductFileToReleaseArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeAccessClient) AddProductFileToReleaseReturns(result1 error) {
@@ -346,15 +512,78 @@ func (fake *FakeAccessClient) AddProductFileToReleaseReturnsOnCall(i int, result
 	}{result1}
 }
 
-func (fake *FakeAccessClient) CreateFederationToken() (pivnet.FederationToken, error) {
+func (fake *FakeAccessClient) AddUserGroupToRelease(arg1 context.Context, arg2 int, arg3 int) error {
+	fake.addUserGroupToReleaseMutex.Lock()
+	ret, specificReturn := fake.addUserGroupToReleaseReturnsOnCall[len(fake.addUserGroupToReleaseArgsForCall)]
+	fake.addUserGroupToReleaseArgsForCall = append(fake.addUserGroupToReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("AddUserGroupToRelease", []interface{}{arg1, arg2, arg3})
+	fake.addUserGroupToReleaseMutex.Unlock()
+	if fake.AddUserGroupToReleaseStub != nil {
+		return fake.AddUserGroupToReleaseStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addUserGroupToReleaseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeAccessClient) AddUserGroupToReleaseCallCount() int {
+	fake.addUserGroupToReleaseMutex.RLock()
+	defer fake.addUserGroupToReleaseMutex.RUnlock()
+	return len(fake.addUserGroupToReleaseArgsForCall)
+}
+
+func (fake *FakeAccessClient) AddUserGroupToReleaseCalls(stub func(context.Context, int, int) error) {
+	fake.addUserGroupToReleaseMutex.Lock()
+	defer fake.addUserGroupToReleaseMutex.Unlock()
+	fake.AddUserGroupToReleaseStub = stub
+}
+
+func (fake *FakeAccessClient) AddUserGroupToReleaseArgsForCall(i int) (context.Context, int, int) {
+	fake.addUserGroupToReleaseMutex.RLock()
+	defer fake.addUserGroupToReleaseMutex.RUnlock()
+	argsForCall := fake.addUserGroupToReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeAccessClient) AddUserGroupToReleaseReturns(result1 error) {
+	fake.addUserGroupToReleaseMutex.Lock()
+	defer fake.addUserGroupToReleaseMutex.Unlock()
+	fake.AddUserGroupToReleaseStub = nil
+	fake.addUserGroupToReleaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAccessClient) AddUserGroupToReleaseReturnsOnCall(i int, result1 error) {
+	fake.addUserGroupToReleaseMutex.Lock()
+	defer fake.addUserGroupToReleaseMutex.Unlock()
+	fake.AddUserGroupToReleaseStub = nil
+	if fake.addUserGroupToReleaseReturnsOnCall == nil {
+		fake.addUserGroupToReleaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addUserGroupToReleaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAccessClient) CreateFederationToken(arg1 context.Context) (pivnet.FederationToken, error) {
 	fake.createFederationTokenMutex.Lock()
 	ret, specificReturn := fake.createFederationTokenReturnsOnCall[len(fake.createFederationTokenArgsForCall)]
 	fake.createFederationTokenArgsForCall = append(fake.createFederationTokenArgsForCall, struct {
-	}{})
-	fake.recordInvocation("CreateFederationToken", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("CreateFederationToken", []interface{}{arg1})
 	fake.createFederationTokenMutex.Unlock()
 	if fake.CreateFederationTokenStub != nil {
-		return fake.CreateFederationTokenStub()
+		return fake.CreateFederationTokenStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -369,12 +598,19 @@ func (fake *FakeAccessClient) CreateFederationTokenCallCount() int {
 	return len(fake.createFederationTokenArgsForCall)
 }
 
-func (fake *FakeAccessClient) CreateFederationTokenCalls(stub func() (pivnet.FederationToken, error)) {
+func (fake *FakeAccessClient) CreateFederationTokenCalls(stub func(context.Context) (pivnet.FederationToken, error)) {
 	fake.createFederationTokenMutex.Lock()
 	defer fake.createFederationTokenMutex.Unlock()
 	fake.CreateFederationTokenStub = stub
 }
 
+func (fake *FakeAccessClient) CreateFederationTokenArgsForCall(i int) context.Context {
+	fake.createFederationTokenMutex.RLock()
+	defer fake.createFederationTokenMutex.RUnlock()
+	argsForCall := fake.createFederationTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeAccessClient) CreateFederationTokenReturns(result1 pivnet.FederationToken, result2 error) {
 	fake.createFederationTokenMutex.Lock()
 	defer fake.createFederationTokenMutex.Unlock()
@@ -401,16 +637,17 @@ func (fake *FakeAccessClient) CreateFederationTokenReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeAccessClient) CreateFileGroup(arg1 string) (pivnet.FileGroup, error) {
+func (fake *FakeAccessClient) CreateFileGroup(arg1 context.Context, arg2 string) (pivnet.FileGroup, error) {
 	fake.createFileGroupMutex.Lock()
 	ret, specificReturn := fake.createFileGroupReturnsOnCall[len(fake.createFileGroupArgsForCall)]
 	fake.createFileGroupArgsForCall = append(fake.createFileGroupArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("CreateFileGroup", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("CreateFileGroup", []interface{}{arg1, arg2})
 	fake.createFileGroupMutex.Unlock()
 	if fake.CreateFileGroupStub != nil {
-		return fake.CreateFileGroupStub(arg1)
+		return fake.CreateFileGroupStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -425,17 +662,17 @@ func (fake *FakeAccessClient) CreateFileGroupCallCount() int {
 	return len(fake.createFileGroupArgsForCall)
 }
 
-func (fake *FakeAccessClient) CreateFileGroupCalls(stub func(string) (pivnet.FileGroup, error)) {
+func (fake *FakeAccessClient) CreateFileGroupCalls(stub func(context.Context, string) (pivnet.FileGroup, error)) {
 	fake.createFileGroupMutex.Lock()
 	defer fake.createFileGroupMutex.Unlock()
 	fake.CreateFileGroupStub = stub
 }
 
-func (fake *FakeAccessClient) CreateFileGroupArgsForCall(i int) string {
+func (fake *FakeAccessClient) CreateFileGroupArgsForCall(i int) (context.Context, string) {
 	fake.createFileGroupMutex.RLock()
 	defer fake.createFileGroupMutex.RUnlock()
 	argsForCall := fake.createFileGroupArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeAccessClient) CreateFileGroupReturns(result1 pivnet.FileGroup, result2 error) {
@@ -464,16 +701,17 @@ func (fake *FakeAccessClient) CreateFileGroupReturnsOnCall(i int, result1 pivnet
 	}{result1, result2}
 }
 
-func (fake *FakeAccessClient) CreateProductFile(arg1 pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+func (fake *FakeAccessClient) CreateProductFile(arg1 context.Context, arg2 pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
 	fake.createProductFileMutex.Lock()
 	ret, specificReturn := fake.createProductFileReturnsOnCall[len(fake.createProductFileArgsForCall)]
 	fake.createProductFileArgsForCall = append(fake.createProductFileArgsForCall, struct {
-		arg1 pivnet.CreateProductFileConfig
-	}{arg1})
-	fake.recordInvocation("CreateProductFile", []interface{}{arg1})
+		arg1 context.Context
+		arg2 pivnet.CreateProductFileConfig
+	}{arg1, arg2})
+	fake.recordInvocation("CreateProductFile", []interface{}{arg1, arg2})
 	fake.createProductFileMutex.Unlock()
 	if fake.CreateProductFileStub != nil {
-		return fake.CreateProductFileStub(arg1)
+		return fake.CreateProductFileStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -488,17 +726,17 @@ func (fake *FakeAccessClient) CreateProductFileCallCount() int {
 	return len(fake.createProductFileArgsForCall)
 }
 
-func (fake *FakeAccessClient) CreateProductFileCalls(stub func(pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)) {
+func (fake *FakeAccessClient) CreateProductFileCalls(stub func(context.Context, pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)) {
 	fake.createProductFileMutex.Lock()
 	defer fake.createProductFileMutex.Unlock()
 	fake.CreateProductFileStub = stub
 }
 
-func (fake *FakeAccessClient) CreateProductFileArgsForCall(i int) pivnet.CreateProductFileConfig {
+func (fake *FakeAccessClient) CreateProductFileArgsForCall(i int) (context.Context, pivnet.CreateProductFileConfig) {
 	fake.createProductFileMutex.RLock()
 	defer fake.createProductFileMutex.RUnlock()
 	argsForCall := fake.createProductFileArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeAccessClient) CreateProductFileReturns(result1 pivnet.ProductFile, result2 error) {
@@ -527,16 +765,17 @@ func (fake *FakeAccessClient) CreateProductFileReturnsOnCall(i int, result1 pivn
 	}{result1, result2}
 }
 
-func (fake *FakeAccessClient) CreateRelease(arg1 pivnet.CreateReleaseConfig) (pivnet.Release, error) {
+func (fake *FakeAccessClient) CreateRelease(arg1 context.Context, arg2 pivnet.CreateReleaseConfig) (pivnet.Release, error) {
 	fake.createReleaseMutex.Lock()
 	ret, specificReturn := fake.createReleaseReturnsOnCall[len(fake.createReleaseArgsForCall)]
 	fake.createReleaseArgsForCall = append(fake.createReleaseArgsForCall, struct {
-		arg1 pivnet.CreateReleaseConfig
-	}{arg1})
-	fake.recordInvocation("CreateRelease", []interface{}{arg1})
+		arg1 context.Context
+		arg2 pivnet.CreateReleaseConfig
+	}{arg1, arg2})
+	fake.recordInvocation("CreateRelease", []interface{}{arg1, arg2})
 	fake.createReleaseMutex.Unlock()
 	if fake.CreateReleaseStub != nil {
-		return fake.CreateReleaseStub(arg1)
+		return fake.CreateReleaseStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -551,17 +790,17 @@ func (fake *FakeAccessClient) CreateReleaseCallCount() int {
 	return len(fake.createReleaseArgsForCall)
 }
 
-func (fake *FakeAccessClient) CreateReleaseCalls(stub func(pivnet.CreateReleaseConfig) (pivnet.Release, error)) {
+func (fake *FakeAccessClient) CreateReleaseCalls(stub func(context.Context, pivnet.CreateReleaseConfig) (pivnet.Release, error)) {
 	fake.createReleaseMutex.Lock()
 	defer fake.createReleaseMutex.Unlock()
 	fake.CreateReleaseStub = stub
 }
 
-func (fake *FakeAccessClient) CreateReleaseArgsForCall(i int) pivnet.CreateReleaseConfig {
+func (fake *FakeAccessClient) CreateReleaseArgsForCall(i int) (context.Context, pivnet.CreateReleaseConfig) {
 	fake.createReleaseMutex.RLock()
 	defer fake.createReleaseMutex.RUnlock()
 	argsForCall := fake.createReleaseArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeAccessClient) CreateReleaseReturns(result1 pivnet.Release, result2 error) {
@@ -590,16 +829,81 @@ func (fake *FakeAccessClient) CreateReleaseReturnsOnCall(i int, result1 pivnet.R
 	}{result1, result2}
 }
 
-func (fake *FakeAccessClient) DeleteProductFile(arg1 int) (pivnet.ProductFile, error) {
+func (fake *FakeAccessClient) DeleteFileGroup(arg1 context.Context, arg2 int) (pivnet.FileGroup, error) {
+	fake.deleteFileGroupMutex.Lock()
+	ret, specificReturn := fake.deleteFileGroupReturnsOnCall[len(fake.deleteFileGroupArgsForCall)]
+	fake.deleteFileGroupArgsForCall = append(fake.deleteFileGroupArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteFileGroup", []interface{}{arg1, arg2})
+	fake.deleteFileGroupMutex.Unlock()
+	if fake.DeleteFileGroupStub != nil {
+		return fake.DeleteFileGroupStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteFileGroupReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAccessClient) DeleteFileGroupCallCount() int {
+	fake.deleteFileGroupMutex.RLock()
+	defer fake.deleteFileGroupMutex.RUnlock()
+	return len(fake.deleteFileGroupArgsForCall)
+}
+
+func (fake *FakeAccessClient) DeleteFileGroupCalls(stub func(context.Context, int) (pivnet.FileGroup, error)) {
+	fake.deleteFileGroupMutex.Lock()
+	defer fake.deleteFileGroupMutex.Unlock()
+	fake.DeleteFileGroupStub = stub
+}
+
+func (fake *FakeAccessClient) DeleteFileGroupArgsForCall(i int) (context.Context, int) {
+	fake.deleteFileGroupMutex.RLock()
+	defer fake.deleteFileGroupMutex.RUnlock()
+	argsForCall := fake.deleteFileGroupArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessClient) DeleteFileGroupReturns(result1 pivnet.FileGroup, result2 error) {
+	fake.deleteFileGroupMutex.Lock()
+	defer fake.deleteFileGroupMutex.Unlock()
+	fake.DeleteFileGroupStub = nil
+	fake.deleteFileGroupReturns = struct {
+		result1 pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) DeleteFileGroupReturnsOnCall(i int, result1 pivnet.FileGroup, result2 error) {
+	fake.deleteFileGroupMutex.Lock()
+	defer fake.deleteFileGroupMutex.Unlock()
+	fake.DeleteFileGroupStub = nil
+	if fake.deleteFileGroupReturnsOnCall == nil {
+		fake.deleteFileGroupReturnsOnCall = make(map[int]struct {
+			result1 pivnet.FileGroup
+			result2 error
+		})
+	}
+	fake.deleteFileGroupReturnsOnCall[i] = struct {
+		result1 pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) DeleteProductFile(arg1 context.Context, arg2 int) (pivnet.ProductFile, error) {
 	fake.deleteProductFileMutex.Lock()
 	ret, specificReturn := fake.deleteProductFileReturnsOnCall[len(fake.deleteProductFileArgsForCall)]
 	fake.deleteProductFileArgsForCall = append(fake.deleteProductFileArgsForCall, struct {
-		arg1 int
-	}{arg1})
-	fake.recordInvocation("DeleteProductFile", []interface{}{arg1})
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteProductFile", []interface{}{arg1, arg2})
 	fake.deleteProductFileMutex.Unlock()
 	if fake.DeleteProductFileStub != nil {
-		return fake.DeleteProductFileStub(arg1)
+		return fake.DeleteProductFileStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -614,17 +918,17 @@ func (fake *FakeAccessClient) DeleteProductFileCallCount() int {
 	return len(fake.deleteProductFileArgsForCall)
 }
 
-func (fake *FakeAccessClient) DeleteProductFileCalls(stub func(int) (pivnet.ProductFile, error)) {
+func (fake *FakeAccessClient) DeleteProductFileCalls(stub func(context.Context, int) (pivnet.ProductFile, error)) {
 	fake.deleteProductFileMutex.Lock()
 	defer fake.deleteProductFileMutex.Unlock()
 	fake.DeleteProductFileStub = stub
 }
 
-func (fake *FakeAccessClient) DeleteProductFileArgsForCall(i int) int {
+func (fake *FakeAccessClient) DeleteProductFileArgsForCall(i int) (context.Context, int) {
 	fake.deleteProductFileMutex.RLock()
 	defer fake.deleteProductFileMutex.RUnlock()
 	argsForCall := fake.deleteProductFileArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeAccessClient) DeleteProductFileReturns(result1 pivnet.ProductFile, result2 error) {
@@ -653,22 +957,84 @@ func (fake *FakeAccessClient) DeleteProductFileReturnsOnCall(i int, result1 pivn
 	}{result1, result2}
 }
 
-func (fake *FakeAccessClient) FileTransferStatusInProgress(arg1 int) bool {
+func (fake *FakeAccessClient) DeleteRelease(arg1 context.Context, arg2 pivnet.Release) error {
+	fake.deleteReleaseMutex.Lock()
+	ret, specificReturn := fake.deleteReleaseReturnsOnCall[len(fake.deleteReleaseArgsForCall)]
+	fake.deleteReleaseArgsForCall = append(fake.deleteReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 pivnet.Release
+	}{arg1, arg2})
+	fake.recordInvocation("DeleteRelease", []interface{}{arg1, arg2})
+	fake.deleteReleaseMutex.Unlock()
+	if fake.DeleteReleaseStub != nil {
+		return fake.DeleteReleaseStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteReleaseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeAccessClient) DeleteReleaseCallCount() int {
+	fake.deleteReleaseMutex.RLock()
+	defer fake.deleteReleaseMutex.RUnlock()
+	return len(fake.deleteReleaseArgsForCall)
+}
+
+func (fake *FakeAccessClient) DeleteReleaseCalls(stub func(context.Context, pivnet.Release) error) {
+	fake.deleteReleaseMutex.Lock()
+	defer fake.deleteReleaseMutex.Unlock()
+	fake.DeleteReleaseStub = stub
+}
+
+func (fake *FakeAccessClient) DeleteReleaseArgsForCall(i int) (context.Context, pivnet.Release) {
+	fake.deleteReleaseMutex.RLock()
+	defer fake.deleteReleaseMutex.RUnlock()
+	argsForCall := fake.deleteReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessClient) DeleteReleaseReturns(result1 error) {
+	fake.deleteReleaseMutex.Lock()
+	defer fake.deleteReleaseMutex.Unlock()
+	fake.DeleteReleaseStub = nil
+	fake.deleteReleaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAccessClient) DeleteReleaseReturnsOnCall(i int, result1 error) {
+	fake.deleteReleaseMutex.Lock()
+	defer fake.deleteReleaseMutex.Unlock()
+	fake.DeleteReleaseStub = nil
+	if fake.deleteReleaseReturnsOnCall == nil {
+		fake.deleteReleaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReleaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeAccessClient) FileTransferStatusInProgress(arg1 context.Context, arg2 int) (bool, error) {
 	fake.fileTransferStatusInProgressMutex.Lock()
 	ret, specificReturn := fake.fileTransferStatusInProgressReturnsOnCall[len(fake.fileTransferStatusInProgressArgsForCall)]
 	fake.fileTransferStatusInProgressArgsForCall = append(fake.fileTransferStatusInProgressArgsForCall, struct {
-		arg1 int
-	}{arg1})
-	fake.recordInvocation("FileTransferStatusInProgress", []interface{}{arg1})
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("FileTransferStatusInProgress", []interface{}{arg1, arg2})
 	fake.fileTransferStatusInProgressMutex.Unlock()
 	if fake.FileTransferStatusInProgressStub != nil {
-		return fake.FileTransferStatusInProgressStub(arg1)
+		return fake.FileTransferStatusInProgressStub(arg1, arg2)
 	}
 	if specificReturn {
-		return ret.result1
+		return ret.result1, ret.result2
 	}
 	fakeReturns := fake.fileTransferStatusInProgressReturns
-	return fakeReturns.result1
+	return fakeReturns.result1, fakeReturns.result2
 }
 
 func (fake *FakeAccessClient) FileTransferStatusInProgressCallCount() int {
@@ -677,51 +1043,181 @@ func (fake *FakeAccessClient) FileTransferStatusInProgressCallCount() int {
 	return len(fake.fileTransferStatusInProgressArgsForCall)
 }
 
-func (fake *FakeAccessClient) FileTransferStatusInProgressCalls(stub func(int) bool) {
+func (fake *FakeAccessClient) FileTransferStatusInProgressCalls(stub func(context.Context, int) (bool, error)) {
 	fake.fileTransferStatusInProgressMutex.Lock()
 	defer fake.fileTransferStatusInProgressMutex.Unlock()
 	fake.FileTransferStatusInProgressStub = stub
 }
 
-func (fake *FakeAccessClient) FileTransferStatusInProgressArgsForCall(i int) int {
+func (fake *FakeAccessClient) FileTransferStatusInProgressArgsForCall(i int) (context.Context, int) {
 	fake.fileTransferStatusInProgressMutex.RLock()
 	defer fake.fileTransferStatusInProgressMutex.RUnlock()
 	argsForCall := fake.fileTransferStatusInProgressArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeAccessClient) FileTransferStatusInProgressReturns(result1 bool) {
+func (fake *FakeAccessClient) FileTransferStatusInProgressReturns(result1 bool, result2 error) {
 	fake.fileTransferStatusInProgressMutex.Lock()
 	defer fake.fileTransferStatusInProgressMutex.Unlock()
 	fake.FileTransferStatusInProgressStub = nil
 	fake.fileTransferStatusInProgressReturns = struct {
 		result1 bool
-	}{result1}
+		result2 error
+	}{result1, result2}
 }
 
-func (fake *FakeAccessClient) FileTransferStatusInProgressReturnsOnCall(i int, result1 bool) {
+func (fake *FakeAccessClient) FileTransferStatusInProgressReturnsOnCall(i int, result1 bool, result2 error) {
 	fake.fileTransferStatusInProgressMutex.Lock()
 	defer fake.fileTransferStatusInProgressMutex.Unlock()
 	fake.FileTransferStatusInProgressStub = nil
 	if fake.fileTransferStatusInProgressReturnsOnCall == nil {
 		fake.fileTransferStatusInProgressReturnsOnCall = make(map[int]struct {
 			result1 bool
+			result2 error
 		})
 	}
 	fake.fileTransferStatusInProgressReturnsOnCall[i] = struct {
 		result1 bool
-	}{result1}
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetAllFileGroups(arg1 context.Context) ([]pivnet.FileGroup, error) {
+	fake.getAllFileGroupsMutex.Lock()
+	ret, specificReturn := fake.getAllFileGroupsReturnsOnCall[len(fake.getAllFileGroupsArgsForCall)]
+	fake.getAllFileGroupsArgsForCall = append(fake.getAllFileGroupsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("GetAllFileGroups", []interface{}{arg1})
+	fake.getAllFileGroupsMutex.Unlock()
+	if fake.GetAllFileGroupsStub != nil {
+		return fake.GetAllFileGroupsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getAllFileGroupsReturns
+	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeAccessClient) GetAllReleases() ([]pivnet.Release, error) {
+func (fake *FakeAccessClient) GetAllFileGroupsCallCount() int {
+	fake.getAllFileGroupsMutex.RLock()
+	defer fake.getAllFileGroupsMutex.RUnlock()
+	return len(fake.getAllFileGroupsArgsForCall)
+}
+
+func (fake *FakeAccessClient) GetAllFileGroupsCalls(stub func(context.Context) ([]pivnet.FileGroup, error)) {
+	fake.getAllFileGroupsMutex.Lock()
+	defer fake.getAllFileGroupsMutex.Unlock()
+	fake.GetAllFileGroupsStub = stub
+}
+
+func (fake *FakeAccessClient) GetAllFileGroupsArgsForCall(i int) context.Context {
+	fake.getAllFileGroupsMutex.RLock()
+	defer fake.getAllFileGroupsMutex.RUnlock()
+	argsForCall := fake.getAllFileGroupsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAccessClient) GetAllFileGroupsReturns(result1 []pivnet.FileGroup, result2 error) {
+	fake.getAllFileGroupsMutex.Lock()
+	defer fake.getAllFileGroupsMutex.Unlock()
+	fake.GetAllFileGroupsStub = nil
+	fake.getAllFileGroupsReturns = struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetAllFileGroupsReturnsOnCall(i int, result1 []pivnet.FileGroup, result2 error) {
+	fake.getAllFileGroupsMutex.Lock()
+	defer fake.getAllFileGroupsMutex.Unlock()
+	fake.GetAllFileGroupsStub = nil
+	if fake.getAllFileGroupsReturnsOnCall == nil {
+		fake.getAllFileGroupsReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.FileGroup
+			result2 error
+		})
+	}
+	fake.getAllFileGroupsReturnsOnCall[i] = struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetAllProductFiles(arg1 context.Context) ([]pivnet.ProductFile, error) {
+	fake.getAllProductFilesMutex.Lock()
+	ret, specificReturn := fake.getAllProductFilesReturnsOnCall[len(fake.getAllProductFilesArgsForCall)]
+	fake.getAllProductFilesArgsForCall = append(fake.getAllProductFilesArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("GetAllProductFiles", []interface{}{arg1})
+	fake.getAllProductFilesMutex.Unlock()
+	if fake.GetAllProductFilesStub != nil {
+		return fake.GetAllProductFilesStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getAllProductFilesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAccessClient) GetAllProductFilesCallCount() int {
+	fake.getAllProductFilesMutex.RLock()
+	defer fake.getAllProductFilesMutex.RUnlock()
+	return len(fake.getAllProductFilesArgsForCall)
+}
+
+func (fake *FakeAccessClient) GetAllProductFilesCalls(stub func(context.Context) ([]pivnet.ProductFile, error)) {
+	fake.getAllProductFilesMutex.Lock()
+	defer fake.getAllProductFilesMutex.Unlock()
+	fake.GetAllProductFilesStub = stub
+}
+
+func (fake *FakeAccessClient) GetAllProductFilesArgsForCall(i int) context.Context {
+	fake.getAllProductFilesMutex.RLock()
+	defer fake.getAllProductFilesMutex.RUnlock()
+	argsForCall := fake.getAllProductFilesArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAccessClient) GetAllProductFilesReturns(result1 []pivnet.ProductFile, result2 error) {
+	fake.getAllProductFilesMutex.Lock()
+	defer fake.getAllProductFilesMutex.Unlock()
+	fake.GetAllProductFilesStub = nil
+	fake.getAllProductFilesReturns = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetAllProductFilesReturnsOnCall(i int, result1 []pivnet.ProductFile, result2 error) {
+	fake.getAllProductFilesMutex.Lock()
+	defer fake.getAllProductFilesMutex.Unlock()
+	fake.GetAllProductFilesStub = nil
+	if fake.getAllProductFilesReturnsOnCall == nil {
+		fake.getAllProductFilesReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.ProductFile
+			result2 error
+		})
+	}
+	fake.getAllProductFilesReturnsOnCall[i] = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetAllReleases(arg1 context.Context) ([]pivnet.Release, error) {
 	fake.getAllReleasesMutex.Lock()
 	ret, specificReturn := fake.getAllReleasesReturnsOnCall[len(fake.getAllReleasesArgsForCall)]
 	fake.getAllReleasesArgsForCall = append(fake.getAllReleasesArgsForCall, struct {
-	}{})
-	fake.recordInvocation("GetAllReleases", []interface{}{})
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("GetAllReleases", []interface{}{arg1})
 	fake.getAllReleasesMutex.Unlock()
 	if fake.GetAllReleasesStub != nil {
-		return fake.GetAllReleasesStub()
+		return fake.GetAllReleasesStub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -736,12 +1232,19 @@ func (fake *FakeAccessClient) GetAllReleasesCallCount() int {
 	return len(fake.getAllReleasesArgsForCall)
 }
 
-func (fake *FakeAccessClient) GetAllReleasesCalls(stub func() ([]pivnet.Release, error)) {
+func (fake *FakeAccessClient) GetAllReleasesCalls(stub func(context.Context) ([]pivnet.Release, error)) {
 	fake.getAllReleasesMutex.Lock()
 	defer fake.getAllReleasesMutex.Unlock()
 	fake.GetAllReleasesStub = stub
 }
 
+func (fake *FakeAccessClient) GetAllReleasesArgsForCall(i int) context.Context {
+	fake.getAllReleasesMutex.RLock()
+	defer fake.getAllReleasesMutex.RUnlock()
+	argsForCall := fake.getAllReleasesArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeAccessClient) GetAllReleasesReturns(result1 []pivnet.Release, result2 error) {
 	fake.getAllReleasesMutex.Lock()
 	defer fake.getAllReleasesMutex.Unlock()
@@ -768,17 +1271,145 @@ func (fake *FakeAccessClient) GetAllReleasesReturnsOnCall(i int, result1 []pivne
 	}{result1, result2}
 }
 
-func (fake *FakeAccessClient) GetLatestPublicReleaseByReleaseType(arg1 int, arg2 pivnet.ReleaseType) (pivnet.Release, error) {
+func (fake *FakeAccessClient) GetAllUserGroups(arg1 context.Context) ([]pivnet.UserGroup, error) {
+	fake.getAllUserGroupsMutex.Lock()
+	ret, specificReturn := fake.getAllUserGroupsReturnsOnCall[len(fake.getAllUserGroupsArgsForCall)]
+	fake.getAllUserGroupsArgsForCall = append(fake.getAllUserGroupsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("GetAllUserGroups", []interface{}{arg1})
+	fake.getAllUserGroupsMutex.Unlock()
+	if fake.GetAllUserGroupsStub != nil {
+		return fake.GetAllUserGroupsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getAllUserGroupsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAccessClient) GetAllUserGroupsCallCount() int {
+	fake.getAllUserGroupsMutex.RLock()
+	defer fake.getAllUserGroupsMutex.RUnlock()
+	return len(fake.getAllUserGroupsArgsForCall)
+}
+
+func (fake *FakeAccessClient) GetAllUserGroupsCalls(stub func(context.Context) ([]pivnet.UserGroup, error)) {
+	fake.getAllUserGroupsMutex.Lock()
+	defer fake.getAllUserGroupsMutex.Unlock()
+	fake.GetAllUserGroupsStub = stub
+}
+
+func (fake *FakeAccessClient) GetAllUserGroupsArgsForCall(i int) context.Context {
+	fake.getAllUserGroupsMutex.RLock()
+	defer fake.getAllUserGroupsMutex.RUnlock()
+	argsForCall := fake.getAllUserGroupsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeAccessClient) GetAllUserGroupsReturns(result1 []pivnet.UserGroup, result2 error) {
+	fake.getAllUserGroupsMutex.Lock()
+	defer fake.getAllUserGroupsMutex.Unlock()
+	fake.GetAllUserGroupsStub = nil
+	fake.getAllUserGroupsReturns = struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetAllUserGroupsReturnsOnCall(i int, result1 []pivnet.UserGroup, result2 error) {
+	fake.getAllUserGroupsMutex.Lock()
+	defer fake.getAllUserGroupsMutex.Unlock()
+	fake.GetAllUserGroupsStub = nil
+	if fake.getAllUserGroupsReturnsOnCall == nil {
+		fake.getAllUserGroupsReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.UserGroup
+			result2 error
+		})
+	}
+	fake.getAllUserGroupsReturnsOnCall[i] = struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetFileGroupsForRelease(arg1 context.Context, arg2 int) ([]pivnet.FileGroup, error) {
+	fake.getFileGroupsForReleaseMutex.Lock()
+	ret, specificReturn := fake.getFileGroupsForReleaseReturnsOnCall[len(fake.getFileGroupsForReleaseArgsForCall)]
+	fake.getFileGroupsForReleaseArgsForCall = append(fake.getFileGroupsForReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("GetFileGroupsForRelease", []interface{}{arg1, arg2})
+	fake.getFileGroupsForReleaseMutex.Unlock()
+	if fake.GetFileGroupsForReleaseStub != nil {
+		return fake.GetFileGroupsForReleaseStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getFileGroupsForReleaseReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAccessClient) GetFileGroupsForReleaseCallCount() int {
+	fake.getFileGroupsForReleaseMutex.RLock()
+	defer fake.getFileGroupsForReleaseMutex.RUnlock()
+	return len(fake.getFileGroupsForReleaseArgsForCall)
+}
+
+func (fake *FakeAccessClient) GetFileGroupsForReleaseCalls(stub func(context.Context, int) ([]pivnet.FileGroup, error)) {
+	fake.getFileGroupsForReleaseMutex.Lock()
+	defer fake.getFileGroupsForReleaseMutex.Unlock()
+	fake.GetFileGroupsForReleaseStub = stub
+}
+
+func (fake *FakeAccessClient) GetFileGroupsForReleaseArgsForCall(i int) (context.Context, int) {
+	fake.getFileGroupsForReleaseMutex.RLock()
+	defer fake.getFileGroupsForReleaseMutex.RUnlock()
+	argsForCall := fake.getFileGroupsForReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessClient) GetFileGroupsForReleaseReturns(result1 []pivnet.FileGroup, result2 error) {
+	fake.getFileGroupsForReleaseMutex.Lock()
+	defer fake.getFileGroupsForReleaseMutex.Unlock()
+	fake.GetFileGroupsForReleaseStub = nil
+	fake.getFileGroupsForReleaseReturns = struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetFileGroupsForReleaseReturnsOnCall(i int, result1 []pivnet.FileGroup, result2 error) {
+	fake.getFileGroupsForReleaseMutex.Lock()
+	defer fake.getFileGroupsForReleaseMutex.Unlock()
+	fake.GetFileGroupsForReleaseStub = nil
+	if fake.getFileGroupsForReleaseReturnsOnCall == nil {
+		fake.getFileGroupsForReleaseReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.FileGroup
+			result2 error
+		})
+	}
+	fake.getFileGroupsForReleaseReturnsOnCall[i] = struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetLatestPublicReleaseByReleaseType(arg1 context.Context, arg2 int, arg3 pivnet.ReleaseType) (pivnet.Release, error) {
 	fake.getLatestPublicReleaseByReleaseTypeMutex.Lock()
 	ret, specificReturn := fake.getLatestPublicReleaseByReleaseTypeReturnsOnCall[len(fake.getLatestPublicReleaseByReleaseTypeArgsForCall)]
 	fake.getLatestPublicReleaseByReleaseTypeArgsForCall = append(fake.getLatestPublicReleaseByReleaseTypeArgsForCall, struct {
-		arg1 int
-		arg2 pivnet.ReleaseType
-	}{arg1, arg2})
-	fake.recordInvocation("GetLatestPublicReleaseByReleaseType", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 int
+		arg3 pivnet.ReleaseType
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetLatestPublicReleaseByReleaseType", []interface{}{arg1, arg2, arg3})
 	fake.getLatestPublicReleaseByReleaseTypeMutex.Unlock()
 	if fake.GetLatestPublicReleaseByReleaseTypeStub != nil {
-		return fake.GetLatestPublicReleaseByReleaseTypeStub(arg1, arg2)
+		return fake.GetLatestPublicReleaseByReleaseTypeStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -793,17 +1424,17 @@ func (fake *FakeAccessClient) GetLatestPublicReleaseByReleaseTypeCallCount() int
 	return len(fake.getLatestPublicReleaseByReleaseTypeArgsForCall)
 }
 
-func (fake *FakeAccessClient) GetLatestPublicReleaseByReleaseTypeCalls(stub func(int, pivnet.ReleaseType) (pivnet.Release, error)) {
+func (fake *FakeAccessClient) GetLatestPublicReleaseByReleaseTypeCalls(stub func(context.Context, int, pivnet.ReleaseType) (pivnet.Release, error)) {
 	fake.getLatestPublicReleaseByReleaseTypeMutex.Lock()
 	defer fake.getLatestPublicReleaseByReleaseTypeMutex.Unlock()
 	fake.GetLatestPublicReleaseByReleaseTypeStub = stub
 }
 
-func (fake *FakeAccessClient) GetLatestPublicReleaseByReleaseTypeArgsForCall(i int) (int, pivnet.ReleaseType) {
+func (fake *FakeAccessClient) GetLatestPublicReleaseByReleaseTypeArgsForCall(i int) (context.Context, int, pivnet.ReleaseType) {
 	fake.getLatestPublicReleaseByReleaseTypeMutex.RLock()
 	defer fake.getLatestPublicReleaseByReleaseTypeMutex.RUnlock()
 	argsForCall := fake.getLatestPublicReleaseByReleaseTypeArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeAccessClient) GetLatestPublicReleaseByReleaseTypeReturns(result1 pivnet.Release, result2 error) {
@@ -832,16 +1463,273 @@ func (fake *FakeAccessClient) GetLatestPublicReleaseByReleaseTypeReturnsOnCall(i
 	}{result1, result2}
 }
 
-func (fake *FakeAccessClient) UpdateRelease(arg1 pivnet.Release) (pivnet.Release, error) {
+func (fake *FakeAccessClient) GetProductFile(arg1 context.Context, arg2 int) (pivnet.ProductFile, error) {
+	fake.getProductFileMutex.Lock()
+	ret, specificReturn := fake.getProductFileReturnsOnCall[len(fake.getProductFileArgsForCall)]
+	fake.getProductFileArgsForCall = append(fake.getProductFileArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("GetProductFile", []interface{}{arg1, arg2})
+	fake.getProductFileMutex.Unlock()
+	if fake.GetProductFileStub != nil {
+		return fake.GetProductFileStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getProductFileReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAccessClient) GetProductFileCallCount() int {
+	fake.getProductFileMutex.RLock()
+	defer fake.getProductFileMutex.RUnlock()
+	return len(fake.getProductFileArgsForCall)
+}
+
+func (fake *FakeAccessClient) GetProductFileCalls(stub func(context.Context, int) (pivnet.ProductFile, error)) {
+	fake.getProductFileMutex.Lock()
+	defer fake.getProductFileMutex.Unlock()
+	fake.GetProductFileStub = stub
+}
+
+func (fake *FakeAccessClient) GetProductFileArgsForCall(i int) (context.Context, int) {
+	fake.getProductFileMutex.RLock()
+	defer fake.getProductFileMutex.RUnlock()
+	argsForCall := fake.getProductFileArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessClient) GetProductFileReturns(result1 pivnet.ProductFile, result2 error) {
+	fake.getProductFileMutex.Lock()
+	defer fake.getProductFileMutex.Unlock()
+	fake.GetProductFileStub = nil
+	fake.getProductFileReturns = struct {
+		result1 pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetProductFileReturnsOnCall(i int, result1 pivnet.ProductFile, result2 error) {
+	fake.getProductFileMutex.Lock()
+	defer fake.getProductFileMutex.Unlock()
+	fake.GetProductFileStub = nil
+	if fake.getProductFileReturnsOnCall == nil {
+		fake.getProductFileReturnsOnCall = make(map[int]struct {
+			result1 pivnet.ProductFile
+			result2 error
+		})
+	}
+	fake.getProductFileReturnsOnCall[i] = struct {
+		result1 pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetProductFilesForRelease(arg1 context.Context, arg2 int) ([]pivnet.ProductFile, error) {
+	fake.getProductFilesForReleaseMutex.Lock()
+	ret, specificReturn := fake.getProductFilesForReleaseReturnsOnCall[len(fake.getProductFilesForReleaseArgsForCall)]
+	fake.getProductFilesForReleaseArgsForCall = append(fake.getProductFilesForReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("GetProductFilesForRelease", []interface{}{arg1, arg2})
+	fake.getProductFilesForReleaseMutex.Unlock()
+	if fake.GetProductFilesForReleaseStub != nil {
+		return fake.GetProductFilesForReleaseStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getProductFilesForReleaseReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAccessClient) GetProductFilesForReleaseCallCount() int {
+	fake.getProductFilesForReleaseMutex.RLock()
+	defer fake.getProductFilesForReleaseMutex.RUnlock()
+	return len(fake.getProductFilesForReleaseArgsForCall)
+}
+
+func (fake *FakeAccessClient) GetProductFilesForReleaseCalls(stub func(context.Context, int) ([]pivnet.ProductFile, error)) {
+	fake.getProductFilesForReleaseMutex.Lock()
+	defer fake.getProductFilesForReleaseMutex.Unlock()
+	fake.GetProductFilesForReleaseStub = stub
+}
+
+func (fake *FakeAccessClient) GetProductFilesForReleaseArgsForCall(i int) (context.Context, int) {
+	fake.getProductFilesForReleaseMutex.RLock()
+	defer fake.getProductFilesForReleaseMutex.RUnlock()
+	argsForCall := fake.getProductFilesForReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessClient) GetProductFilesForReleaseReturns(result1 []pivnet.ProductFile, result2 error) {
+	fake.getProductFilesForReleaseMutex.Lock()
+	defer fake.getProductFilesForReleaseMutex.Unlock()
+	fake.GetProductFilesForReleaseStub = nil
+	fake.getProductFilesForReleaseReturns = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetProductFilesForReleaseReturnsOnCall(i int, result1 []pivnet.ProductFile, result2 error) {
+	fake.getProductFilesForReleaseMutex.Lock()
+	defer fake.getProductFilesForReleaseMutex.Unlock()
+	fake.GetProductFilesForReleaseStub = nil
+	if fake.getProductFilesForReleaseReturnsOnCall == nil {
+		fake.getProductFilesForReleaseReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.ProductFile
+			result2 error
+		})
+	}
+	fake.getProductFilesForReleaseReturnsOnCall[i] = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetReleaseByVersion(arg1 context.Context, arg2 string) (pivnet.Release, error) {
+	fake.getReleaseByVersionMutex.Lock()
+	ret, specificReturn := fake.getReleaseByVersionReturnsOnCall[len(fake.getReleaseByVersionArgsForCall)]
+	fake.getReleaseByVersionArgsForCall = append(fake.getReleaseByVersionArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetReleaseByVersion", []interface{}{arg1, arg2})
+	fake.getReleaseByVersionMutex.Unlock()
+	if fake.GetReleaseByVersionStub != nil {
+		return fake.GetReleaseByVersionStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getReleaseByVersionReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAccessClient) GetReleaseByVersionCallCount() int {
+	fake.getReleaseByVersionMutex.RLock()
+	defer fake.getReleaseByVersionMutex.RUnlock()
+	return len(fake.getReleaseByVersionArgsForCall)
+}
+
+func (fake *FakeAccessClient) GetReleaseByVersionCalls(stub func(context.Context, string) (pivnet.Release, error)) {
+	fake.getReleaseByVersionMutex.Lock()
+	defer fake.getReleaseByVersionMutex.Unlock()
+	fake.GetReleaseByVersionStub = stub
+}
+
+func (fake *FakeAccessClient) GetReleaseByVersionArgsForCall(i int) (context.Context, string) {
+	fake.getReleaseByVersionMutex.RLock()
+	defer fake.getReleaseByVersionMutex.RUnlock()
+	argsForCall := fake.getReleaseByVersionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessClient) GetReleaseByVersionReturns(result1 pivnet.Release, result2 error) {
+	fake.getReleaseByVersionMutex.Lock()
+	defer fake.getReleaseByVersionMutex.Unlock()
+	fake.GetReleaseByVersionStub = nil
+	fake.getReleaseByVersionReturns = struct {
+		result1 pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetReleaseByVersionReturnsOnCall(i int, result1 pivnet.Release, result2 error) {
+	fake.getReleaseByVersionMutex.Lock()
+	defer fake.getReleaseByVersionMutex.Unlock()
+	fake.GetReleaseByVersionStub = nil
+	if fake.getReleaseByVersionReturnsOnCall == nil {
+		fake.getReleaseByVersionReturnsOnCall = make(map[int]struct {
+			result1 pivnet.Release
+			result2 error
+		})
+	}
+	fake.getReleaseByVersionReturnsOnCall[i] = struct {
+		result1 pivnet.Release
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetUserGroupsForRelease(arg1 context.Context, arg2 int) ([]pivnet.UserGroup, error) {
+	fake.getUserGroupsForReleaseMutex.Lock()
+	ret, specificReturn := fake.getUserGroupsForReleaseReturnsOnCall[len(fake.getUserGroupsForReleaseArgsForCall)]
+	fake.getUserGroupsForReleaseArgsForCall = append(fake.getUserGroupsForReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 int
+	}{arg1, arg2})
+	fake.recordInvocation("GetUserGroupsForRelease", []interface{}{arg1, arg2})
+	fake.getUserGroupsForReleaseMutex.Unlock()
+	if fake.GetUserGroupsForReleaseStub != nil {
+		return fake.GetUserGroupsForReleaseStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getUserGroupsForReleaseReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeAccessClient) GetUserGroupsForReleaseCallCount() int {
+	fake.getUserGroupsForReleaseMutex.RLock()
+	defer fake.getUserGroupsForReleaseMutex.RUnlock()
+	return len(fake.getUserGroupsForReleaseArgsForCall)
+}
+
+func (fake *FakeAccessClient) GetUserGroupsForReleaseCalls(stub func(context.Context, int) ([]pivnet.UserGroup, error)) {
+	fake.getUserGroupsForReleaseMutex.Lock()
+	defer fake.getUserGroupsForReleaseMutex.Unlock()
+	fake.GetUserGroupsForReleaseStub = stub
+}
+
+func (fake *FakeAccessClient) GetUserGroupsForReleaseArgsForCall(i int) (context.Context, int) {
+	fake.getUserGroupsForReleaseMutex.RLock()
+	defer fake.getUserGroupsForReleaseMutex.RUnlock()
+	argsForCall := fake.getUserGroupsForReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeAccessClient) GetUserGroupsForReleaseReturns(result1 []pivnet.UserGroup, result2 error) {
+	fake.getUserGroupsForReleaseMutex.Lock()
+	defer fake.getUserGroupsForReleaseMutex.Unlock()
+	fake.GetUserGroupsForReleaseStub = nil
+	fake.getUserGroupsForReleaseReturns = struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) GetUserGroupsForReleaseReturnsOnCall(i int, result1 []pivnet.UserGroup, result2 error) {
+	fake.getUserGroupsForReleaseMutex.Lock()
+	defer fake.getUserGroupsForReleaseMutex.Unlock()
+	fake.GetUserGroupsForReleaseStub = nil
+	if fake.getUserGroupsForReleaseReturnsOnCall == nil {
+		fake.getUserGroupsForReleaseReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.UserGroup
+			result2 error
+		})
+	}
+	fake.getUserGroupsForReleaseReturnsOnCall[i] = struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAccessClient) UpdateRelease(arg1 context.Context, arg2 pivnet.Release) (pivnet.Release, error) {
 	fake.updateReleaseMutex.Lock()
 	ret, specificReturn := fake.updateReleaseReturnsOnCall[len(fake.updateReleaseArgsForCall)]
 	fake.updateReleaseArgsForCall = append(fake.updateReleaseArgsForCall, struct {
-		arg1 pivnet.Release
-	}{arg1})
-	fake.recordInvocation("UpdateRelease", []interface{}{arg1})
+		arg1 context.Context
+		arg2 pivnet.Release
+	}{arg1, arg2})
+	fake.recordInvocation("UpdateRelease", []interface{}{arg1, arg2})
 	fake.updateReleaseMutex.Unlock()
 	if fake.UpdateReleaseStub != nil {
-		return fake.UpdateReleaseStub(arg1)
+		return fake.UpdateReleaseStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -856,17 +1744,17 @@ func (fake *FakeAccessClient) UpdateReleaseCallCount() int {
 	return len(fake.updateReleaseArgsForCall)
 }
 
-func (fake *FakeAccessClient) UpdateReleaseCalls(stub func(pivnet.Release) (pivnet.Release, error)) {
+func (fake *FakeAccessClient) UpdateReleaseCalls(stub func(context.Context, pivnet.Release) (pivnet.Release, error)) {
 	fake.updateReleaseMutex.Lock()
 	defer fake.updateReleaseMutex.Unlock()
 	fake.UpdateReleaseStub = stub
 }
 
-func (fake *FakeAccessClient) UpdateReleaseArgsForCall(i int) pivnet.Release {
+func (fake *FakeAccessClient) UpdateReleaseArgsForCall(i int) (context.Context, pivnet.Release) {
 	fake.updateReleaseMutex.RLock()
 	defer fake.updateReleaseMutex.RUnlock()
 	argsForCall := fake.updateReleaseArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeAccessClient) UpdateReleaseReturns(result1 pivnet.Release, result2 error) {
@@ -904,6 +1792,8 @@ func (fake *FakeAccessClient) Invocations() map[string][][]interface{} {
 	defer fake.addProductFileToFileGroupMutex.RUnlock()
 	fake.addProductFileToReleaseMutex.RLock()
 	defer fake.addProductFileToReleaseMutex.RUnlock()
+	fake.addUserGroupToReleaseMutex.RLock()
+	defer fake.addUserGroupToReleaseMutex.RUnlock()
 	fake.createFederationTokenMutex.RLock()
 	defer fake.createFederationTokenMutex.RUnlock()
 	fake.createFileGroupMutex.RLock()
@@ -912,14 +1802,34 @@ func (fake *FakeAccessClient) Invocations() map[string][][]interface{} {
 	defer fake.createProductFileMutex.RUnlock()
 	fake.createReleaseMutex.RLock()
 	defer fake.createReleaseMutex.RUnlock()
+	fake.deleteFileGroupMutex.RLock()
+	defer fake.deleteFileGroupMutex.RUnlock()
 	fake.deleteProductFileMutex.RLock()
 	defer fake.deleteProductFileMutex.RUnlock()
+	fake.deleteReleaseMutex.RLock()
+	defer fake.deleteReleaseMutex.RUnlock()
 	fake.fileTransferStatusInProgressMutex.RLock()
 	defer fake.fileTransferStatusInProgressMutex.RUnlock()
+	fake.getAllFileGroupsMutex.RLock()
+	defer fake.getAllFileGroupsMutex.RUnlock()
+	fake.getAllProductFilesMutex.RLock()
+	defer fake.getAllProductFilesMutex.RUnlock()
 	fake.getAllReleasesMutex.RLock()
 	defer fake.getAllReleasesMutex.RUnlock()
+	fake.getAllUserGroupsMutex.RLock()
+	defer fake.getAllUserGroupsMutex.RUnlock()
+	fake.getFileGroupsForReleaseMutex.RLock()
+	defer fake.getFileGroupsForReleaseMutex.RUnlock()
 	fake.getLatestPublicReleaseByReleaseTypeMutex.RLock()
 	defer fake.getLatestPublicReleaseByReleaseTypeMutex.RUnlock()
+	fake.getProductFileMutex.RLock()
+	defer fake.getProductFileMutex.RUnlock()
+	fake.getProductFilesForReleaseMutex.RLock()
+	defer fake.getProductFilesForReleaseMutex.RUnlock()
+	fake.getReleaseByVersionMutex.RLock()
+	defer fake.getReleaseByVersionMutex.RUnlock()
+	fake.getUserGroupsForReleaseMutex.RLock()
+	defer fake.getUserGroupsForReleaseMutex.RUnlock()
 	fake.updateReleaseMutex.RLock()
 	defer fake.updateReleaseMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -1 +1,145 @@
 package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	"github.com/baotingfang/go-pivnet-client/service"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	cleanCmdFlagsInit sync.Once
+
+	cleanVerbose   bool
+	cleanDryRun    bool
+	cleanAssumeYes bool
+	olderThan      string
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean [-v] [-y] [--dry-run] [--older-than age]",
+	Short: "Clean orphaned product files and file groups on pivnet",
+	Long: `This program will find the product files and file groups which are not attached to any release, and delete them from pivnet.
+The product files which pivnet is still transferring are skipped, use --older-than to also skip the other objects of running uploads`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel := vlog.InfoLevel
+		if cleanVerbose {
+			logLevel = vlog.DebugLevel
+		}
+		vlog.InitLog("Clean ", logLevel)
+		ctx := context.Background()
+
+		var summaryItems []SummaryItem
+
+		context, err := gp.NewContextFromEnv(false, cleanVerbose)
+		if err != nil {
+			NewErrorSummary("clean", err, summaryItems...).Print(os.Stderr)
+			os.Exit(ExitCode(err))
+		}
+		context = context.WithRetryPolicy(retryPolicy).WithRateLimit(requestsPerSecond)
+
+		summaryItems = append(summaryItems,
+			SummaryItem{Name: "endpoint", Value: context.BaseUrl},
+			SummaryItem{Name: "product slug", Value: context.Slug},
+		)
+
+		options := CleanOptions{
+			DryRun:    cleanDryRun,
+			OlderThan: olderThan,
+			AssumeYes: cleanAssumeYes,
+		}
+		err = RunClean(ctx, context, options, os.Stdin, os.Stdout)
+		if err != nil {
+			NewErrorSummary("clean", err, summaryItems...).Print(os.Stderr)
+			os.Exit(ExitCode(err))
+		}
+	},
+}
+
+type CleanOptions struct {
+	DryRun    bool
+	OlderThan string
+	AssumeYes bool
+}
+
+var ErrCleanAborted = errors.New("clean is aborted by user")
+
+func RunClean(ctx context.Context, context gp.Context, options CleanOptions, in io.Reader, out io.Writer) error {
+	age := options.OlderThan
+	if !Empty(age) {
+		if !strings.HasPrefix(age, "+") {
+			age = "+" + age
+		}
+		if !service.NewOffsetValidator(age).Validate() {
+			return NewValidationError(`older-than must be a valid age of the form "(\d+[mdyMDY])+": %s`, options.OlderThan)
+		}
+	}
+
+	cleaner := service.NewCleaner(context)
+
+	orphans, err := cleaner.FindOrphans(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !Empty(age) {
+		orphans, err = cleaner.OlderThan(ctx, orphans, age)
+		if err != nil {
+			return err
+		}
+	}
+
+	if orphans.Empty() {
+		_, _ = fmt.Fprintln(out, "no orphaned product files or file groups")
+		return nil
+	}
+
+	PrintOrphans(out, orphans)
+
+	if options.DryRun {
+		return nil
+	}
+
+	if !options.AssumeYes {
+		message := fmt.Sprintf("Are you sure to delete %d product files and %d file groups of %s",
+			len(orphans.ProductFiles), len(orphans.FileGroups), context.Slug)
+		if !confirm(in, out, message) {
+			return ErrCleanAborted
+		}
+	}
+
+	return cleaner.Clean(ctx, orphans)
+}
+
+func PrintOrphans(out io.Writer, orphans service.Orphans) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tID\tNAME\tRELEASED AT\tAWS OBJECT KEY")
+	for _, pf := range orphans.ProductFiles {
+		_, _ = fmt.Fprintf(w, "product file\t%d\t%s\t%s\t%s\n", pf.ID, pf.Name, pf.ReleasedAt, pf.AWSObjectKey)
+	}
+	for _, group := range orphans.FileGroups {
+		_, _ = fmt.Fprintf(w, "file group\t%d\t%s\t\t\n", group.ID, group.Name)
+	}
+	_ = w.Flush()
+}
+
+func init() {
+	cleanCmdFlagsInit.Do(func() {
+		cleanCmd.Flags().BoolVarP(&cleanVerbose, FlagNameVerbose.String(), "v", false, "Verbose output")
+		cleanCmd.Flags().BoolVarP(&cleanAssumeYes, FlagNameYes.String(), "y", false, "Clean without confirmation")
+		cleanCmd.Flags().BoolVar(&cleanDryRun, FlagNameDryRun.String(), false, "Only show the orphaned product files and file groups")
+		cleanCmd.Flags().StringVar(&olderThan, FlagNameOlderThan.String(), "", "Only clean the orphans uploaded to s3 before the age, such as 7d, 2m, 1y")
+
+		rootCmd.AddCommand(cleanCmd)
+	})
+}
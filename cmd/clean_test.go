@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"net/http"
+	"strings"
+
+	. "github.com/baotingfang/go-pivnet-client/cmd"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	"github.com/baotingfang/go-pivnet-client/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/pivotal-cf/go-pivnet/v4"
+)
+
+var _ = Describe("Clean", func() {
+	var (
+		server  *ghttp.Server
+		context gp.Context
+		out     *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		context = gp.NewContext(server.URL(), "fakeslug", "faketoken", true, false).WithRateLimit(0)
+		out = gbytes.NewBuffer()
+
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/file_groups"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.FileGroupsResponse{
+					FileGroups: []pivnet.FileGroup{{ID: 20, Name: "server"}},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/product_files"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFilesResponse{
+					ProductFiles: []pivnet.ProductFile{
+						{ID: 30, Name: "rhel7", ReleasedAt: "2020-01-01", AWSObjectKey: "greenplum-db-6.6.0-rhel7-x86_64.rpm"},
+					},
+				}),
+			),
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("only shows the orphans in dry run mode", func() {
+		err := RunClean(ctx, context, CleanOptions{DryRun: true}, strings.NewReader(""), out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(gbytes.Say(`product file\s+30\s+rhel7\s+2020-01-01\s+greenplum-db-6.6.0-rhel7-x86_64.rpm`))
+		Expect(out).To(gbytes.Say(`file group\s+20\s+server`))
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("deletes the orphans without confirmation", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/product_files/30"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFileResponse{}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/file_groups/20"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.FileGroup{}),
+			),
+		)
+
+		err := RunClean(ctx, context, CleanOptions{AssumeYes: true}, strings.NewReader(""), out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.ReceivedRequests()).To(HaveLen(5))
+	})
+
+	It("returns the remote api error when it failed to delete the orphans", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/product_files/30"),
+				ghttp.RespondWithJSONEncoded(http.StatusForbidden, map[string]interface{}{"message": "forbidden"}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/file_groups/20"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.FileGroup{}),
+			),
+		)
+
+		err := RunClean(ctx, context, CleanOptions{AssumeYes: true}, strings.NewReader(""), out)
+		Expect(err).To(MatchError(HavePrefix("clean failed:\ndelete product file rhel7 (id=30) failed: ")))
+		Expect(ExitCode(err)).To(Equal(ExitCodeRemoteApi))
+		Expect(server.ReceivedRequests()).To(HaveLen(5))
+	})
+
+	It("older-than is not valid", func() {
+		err := RunClean(ctx, context, CleanOptions{OlderThan: "7 days"}, strings.NewReader(""), out)
+		Expect(err).To(MatchError(HavePrefix("older-than must be a valid age")))
+		Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
+		Expect(server.ReceivedRequests()).To(BeEmpty())
+	})
+})
@@ -1 +1,122 @@
 package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	"github.com/baotingfang/go-pivnet-client/service"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"io"
+	"os"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	destroyCmdFlagsInit sync.Once
+
+	destroyVerbose     bool
+	destroyGpdbVersion string
+	assumeYes          bool
+)
+
+var destroyCmd = &cobra.Command{
+	Use:   "destroy [-v] [-y] <-g gpdb_version>",
+	Short: "Destroy a release on pivnet",
+	Long:  `Given a gpdb version, this program will delete the product files, the file groups and the release of that version on pivnet`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel := vlog.InfoLevel
+		if destroyVerbose {
+			logLevel = vlog.DebugLevel
+		}
+		vlog.InitLog("Destroy ", logLevel)
+		ctx := context.Background()
+
+		summaryItems := []SummaryItem{
+			{Name: "gpdb version", Value: destroyGpdbVersion},
+		}
+
+		context, err := gp.NewContextFromEnv(false, destroyVerbose)
+		if err != nil {
+			NewErrorSummary("destroy", err, summaryItems...).Print(os.Stderr)
+			os.Exit(ExitCode(err))
+		}
+		context = context.WithRetryPolicy(retryPolicy).WithRateLimit(requestsPerSecond)
+
+		summaryItems = append(summaryItems,
+			SummaryItem{Name: "endpoint", Value: context.BaseUrl},
+			SummaryItem{Name: "product slug", Value: context.Slug},
+		)
+
+		err = RunDestroy(ctx, context, destroyGpdbVersion, assumeYes, os.Stdin, os.Stdout)
+		if err != nil {
+			NewErrorSummary("destroy", err, summaryItems...).Print(os.Stderr)
+			os.Exit(ExitCode(err))
+		}
+	},
+}
+
+var ErrDestroyAborted = errors.New("destroy is aborted by user")
+
+func RunDestroy(ctx context.Context, context gp.Context, gpdbVersion string, assumeYes bool, in io.Reader, out io.Writer) error {
+	destroyer := service.NewDestroyer(context, gpdbVersion)
+
+	plan, err := destroyer.Plan(ctx)
+	if err != nil {
+		return err
+	}
+
+	PrintDestroyPlan(out, plan)
+
+	if !assumeYes {
+		message := fmt.Sprintf("Are you sure to destroy release %s of %s", plan.Release.Version, context.Slug)
+		if !confirm(in, out, message) {
+			return ErrDestroyAborted
+		}
+	}
+
+	err = destroyer.Destroy(ctx, plan)
+	if err != nil {
+		return err
+	}
+
+	vlog.Info("release %s has been destroyed", plan.Release.Version)
+	return nil
+}
+
+func PrintDestroyPlan(out io.Writer, plan service.DestroyPlan) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tID\tNAME\tACTION")
+	for _, pf := range plan.ProductFiles {
+		_, _ = fmt.Fprintf(w, "product file\t%d\t%s\tdelete\n", pf.ID, pf.Name)
+	}
+	for _, pf := range plan.SharedProductFiles {
+		_, _ = fmt.Fprintf(w, "product file\t%d\t%s\tkeep, attached to other releases\n", pf.ID, pf.Name)
+	}
+	for _, group := range plan.FileGroups {
+		_, _ = fmt.Fprintf(w, "file group\t%d\t%s\tdelete\n", group.ID, group.Name)
+	}
+	for _, group := range plan.SharedFileGroups {
+		_, _ = fmt.Fprintf(w, "file group\t%d\t%s\tkeep, attached to other releases\n", group.ID, group.Name)
+	}
+	_, _ = fmt.Fprintf(w, "release\t%d\t%s\tdelete\n", plan.Release.ID, plan.Release.Version)
+	_ = w.Flush()
+}
+
+func init() {
+	destroyCmdFlagsInit.Do(func() {
+		destroyCmd.Flags().StringVarP(&destroyGpdbVersion, FlagNameGpdbVersion.String(), "g", "", "GPDB version of the release to destroy")
+		destroyCmd.Flags().BoolVarP(&destroyVerbose, FlagNameVerbose.String(), "v", false, "Verbose output")
+		destroyCmd.Flags().BoolVarP(&assumeYes, FlagNameYes.String(), "y", false, "Destroy without confirmation")
+
+		err := destroyCmd.MarkFlagRequired(FlagNameGpdbVersion.String())
+		if err != nil {
+			vlog.Fatal(err.Error())
+		}
+
+		rootCmd.AddCommand(destroyCmd)
+	})
+}
@@ -0,0 +1,125 @@
+package cmd_test
+
+import (
+	"net/http"
+	"strings"
+
+	. "github.com/baotingfang/go-pivnet-client/cmd"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/pivotal-cf/go-pivnet/v4"
+)
+
+var _ = Describe("Destroy", func() {
+	var (
+		server  *ghttp.Server
+		context gp.Context
+		out     *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		context = gp.NewContext(server.URL(), "fakeslug", "faketoken", true, false).WithRateLimit(0)
+		out = gbytes.NewBuffer()
+
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{
+					Releases: []pivnet.Release{{ID: 10, Version: "6.6.0"}},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases/10/file_groups"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.FileGroupsResponse{
+					FileGroups: []pivnet.FileGroup{
+						{ID: 20, Name: "server", ProductFiles: []pivnet.ProductFile{{ID: 30, Name: "rhel7"}}},
+					},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases/10/product_files"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFilesResponse{
+					ProductFiles: []pivnet.ProductFile{{ID: 31, Name: "osl"}},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{
+					Releases: []pivnet.Release{{ID: 10, Version: "6.6.0"}, {ID: 11, Version: "6.7.0"}},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases/11/file_groups"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.FileGroupsResponse{}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases/11/product_files"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFilesResponse{
+					ProductFiles: []pivnet.ProductFile{{ID: 31, Name: "osl"}},
+				}),
+			),
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("destroys the release after confirmation", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/product_files/30"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFileResponse{}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/file_groups/20"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.FileGroup{}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/releases/10"),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			),
+		)
+
+		err := RunDestroy(ctx, context, "6.6.0", false, strings.NewReader("yes\n"), out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(gbytes.Say(`product file\s+30\s+rhel7\s+delete`))
+		Expect(out).To(gbytes.Say(`product file\s+31\s+osl\s+keep, attached to other releases`))
+		Expect(out).To(gbytes.Say(`file group\s+20\s+server\s+delete`))
+		Expect(out).To(gbytes.Say(`release\s+10\s+6.6.0\s+delete`))
+		Expect(out).To(gbytes.Say(`Are you sure to destroy release 6.6.0 of fakeslug \[y/N\]: `))
+		Expect(server.ReceivedRequests()).To(HaveLen(9))
+	})
+
+	It("returns the remote api error when it failed to delete the objects", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/product_files/30"),
+				ghttp.RespondWithJSONEncoded(http.StatusForbidden, map[string]interface{}{"message": "forbidden"}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/file_groups/20"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.FileGroup{}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/releases/10"),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			),
+		)
+
+		err := RunDestroy(ctx, context, "6.6.0", true, strings.NewReader(""), out)
+		Expect(err).To(MatchError(HavePrefix("destroy failed:\ndelete product file rhel7 (id=30) failed: ")))
+		Expect(ExitCode(err)).To(Equal(ExitCodeRemoteApi))
+		Expect(server.ReceivedRequests()).To(HaveLen(9))
+	})
+
+	It("does not destroy anything when it is not confirmed", func() {
+		err := RunDestroy(ctx, context, "6.6.0", false, strings.NewReader("\n"), out)
+		Expect(err).To(Equal(ErrDestroyAborted))
+		Expect(server.ReceivedRequests()).To(HaveLen(6))
+	})
+})
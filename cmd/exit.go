@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"net/http"
+)
+
+// The exit codes of the commands, so that the pipelines can tell why a command failed
+const (
+	ExitCodeUnknown     = 1
+	ExitCodeValidation  = 2
+	ExitCodeNotFound    = 3
+	ExitCodeConflict    = 4
+	ExitCodeRemoteApi   = 5
+	ExitCodeStorage     = 6
+	ExitCodeTimeout     = 7
+	ExitCodeInterrupted = 130
+)
+
+// ExitCode returns the exit code of the error. The outermost typed error in the
+// chain decides the code, and the errors of pivnet client are remote api errors,
+// unless pivnet says that the object is not found or conflicts.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if errors.Is(err, context.Canceled) {
+		return ExitCodeInterrupted
+	}
+
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch typed := e.(type) {
+		case ValidationError:
+			return ExitCodeValidation
+		case NotFoundError, pivnet.ErrNotFound:
+			return ExitCodeNotFound
+		case ConflictError:
+			return ExitCodeConflict
+		case StorageError:
+			return ExitCodeStorage
+		case TimeoutError:
+			return ExitCodeTimeout
+		case RemoteApiError, pivnet.ErrUnauthorized, pivnet.ErrTooManyRequests, pivnet.ErrUnavailableForLegalReasons:
+			return ExitCodeRemoteApi
+		case pivnet.ErrPivnetOther:
+			if typed.ResponseCode == http.StatusConflict {
+				return ExitCodeConflict
+			}
+			return ExitCodeRemoteApi
+		}
+	}
+	return ExitCodeUnknown
+}
@@ -0,0 +1,85 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	. "github.com/baotingfang/go-pivnet-client/cmd"
+	"github.com/baotingfang/go-pivnet-client/fakepivnet"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	"github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/baotingfang/go-pivnet-client/wrapper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/go-pivnet/v4"
+)
+
+var _ = Describe("ExitCode", func() {
+	It("returns the exit code of the typed errors", func() {
+		Expect(ExitCode(nil)).To(Equal(0))
+		Expect(ExitCode(errors.New("unknown"))).To(Equal(ExitCodeUnknown))
+		Expect(ExitCode(utils.NewValidationError("invalid"))).To(Equal(ExitCodeValidation))
+		Expect(ExitCode(utils.NewNotFoundError("not found"))).To(Equal(ExitCodeNotFound))
+		Expect(ExitCode(utils.NewConflictError("conflict"))).To(Equal(ExitCodeConflict))
+		Expect(ExitCode(utils.NewRemoteApiError(errors.New("timeout"), "remote"))).To(Equal(ExitCodeRemoteApi))
+		Expect(ExitCode(utils.NewStorageError(errors.New("disk full"), "storage"))).To(Equal(ExitCodeStorage))
+		Expect(ExitCode(utils.NewTimeoutError("timeout"))).To(Equal(ExitCodeTimeout))
+		Expect(ExitCode(context.Canceled)).To(Equal(ExitCodeInterrupted))
+	})
+
+	It("returns the exit code of the outermost typed error", func() {
+		err := utils.NewStorageError(utils.NewValidationError("invalid"), "storage")
+		Expect(ExitCode(fmt.Errorf("failed: %w", err))).To(Equal(ExitCodeStorage))
+	})
+
+	It("returns interrupted when the context is cancelled", func() {
+		err := utils.NewStorageError(context.Canceled, "failed to upload file")
+		Expect(ExitCode(err)).To(Equal(ExitCodeInterrupted))
+	})
+
+	It("returns the exit code of the pivnet errors", func() {
+		Expect(ExitCode(pivnet.ErrNotFound{})).To(Equal(ExitCodeNotFound))
+		Expect(ExitCode(pivnet.ErrUnauthorized{})).To(Equal(ExitCodeRemoteApi))
+		Expect(ExitCode(pivnet.ErrTooManyRequests{})).To(Equal(ExitCodeRemoteApi))
+		Expect(ExitCode(pivnet.ErrPivnetOther{ResponseCode: http.StatusConflict})).To(Equal(ExitCodeConflict))
+		Expect(ExitCode(pivnet.ErrPivnetOther{ResponseCode: http.StatusInternalServerError})).To(Equal(ExitCodeRemoteApi))
+	})
+
+	Context("commands", func() {
+		var (
+			server  *fakepivnet.Server
+			context gp.Context
+		)
+
+		BeforeEach(func() {
+			server = fakepivnet.NewServer()
+			context = gp.NewContext(server.URL, "fakeslug", server.RefreshToken, false, false).
+				WithRetryPolicy(wrapper.RetryPolicy{}).
+				WithRateLimit(0)
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("returns validation when both state file and resume are specified", func() {
+			err := RunUpload(ctx, context, UploadOptions{StateFilePath: "state.json", ResumeFilePath: "state.json"}, gbytes.NewBuffer())
+			Expect(ExitCode(err)).To(Equal(ExitCodeValidation))
+		})
+
+		It("returns not found when the release does not exist", func() {
+			err := RunPublish(ctx, context, "6.6.0", "All Users", nil, gbytes.NewBuffer())
+			Expect(ExitCode(err)).To(Equal(ExitCodeNotFound))
+		})
+
+		It("returns remote api when pivnet fails", func() {
+			server.Fail("GET", "/products/fakeslug/releases", http.StatusInternalServerError, 1)
+
+			err := RunPublish(ctx, context, "6.6.0", "All Users", nil, gbytes.NewBuffer())
+			Expect(ExitCode(err)).To(Equal(ExitCodeRemoteApi))
+		})
+	})
+})
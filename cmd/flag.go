@@ -4,8 +4,31 @@ type FlagName int
 
 //go:generate stringer -type FlagName -linecomment -output flag_string.go
 const (
-	FlagNameMetaFilePath FlagName = iota // metadata
-	FlagNameSearchPath                   // search-path
-	FlagNameVerbose                      // verbose
-	FlagNameGpdbVersion                  // gpdb-version
+	FlagNameMetaFilePath        FlagName = iota // metadata
+	FlagNameSearchPath                          // search-path
+	FlagNameVerbose                             // verbose
+	FlagNameGpdbVersion                         // gpdb-version
+	FlagNameYes                                 // yes
+	FlagNameDryRun                              // dry-run
+	FlagNameOlderThan                           // older-than
+	FlagNameNoRollback                          // no-rollback
+	FlagNameStateFile                           // state-file
+	FlagNameResume                              // resume
+	FlagNameOutput                              // output
+	FlagNameParallel                            // parallel
+	FlagNameTransferInterval                    // transfer-interval
+	FlagNameTransferMaxInterval                 // transfer-max-interval
+	FlagNameTransferTimeout                     // transfer-timeout
+	FlagNameAvailability                        // availability
+	FlagNameUserGroup                           // user-group
+	FlagNameS3Endpoint                          // s3-endpoint
+	FlagNameS3PathStyle                         // s3-path-style
+	FlagNameS3PartSize                          // s3-part-size
+	FlagNameS3Concurrency                       // s3-concurrency
+	FlagNameS3MaxRetries                        // s3-max-retries
+	FlagNameApiMaxRetries                       // api-max-retries
+	FlagNameApiMaxBackoff                       // api-max-backoff
+	FlagNameApiRateLimit                        // api-rate-limit
+	FlagNameApiMetrics                          // api-metrics
+	FlagNameMetadataOverlay                     // metadata-overlay
 )
@@ -12,11 +12,34 @@ func _() {
 	_ = x[FlagNameSearchPath-1]
 	_ = x[FlagNameVerbose-2]
 	_ = x[FlagNameGpdbVersion-3]
+	_ = x[FlagNameYes-4]
+	_ = x[FlagNameDryRun-5]
+	_ = x[FlagNameOlderThan-6]
+	_ = x[FlagNameNoRollback-7]
+	_ = x[FlagNameStateFile-8]
+	_ = x[FlagNameResume-9]
+	_ = x[FlagNameOutput-10]
+	_ = x[FlagNameParallel-11]
+	_ = x[FlagNameTransferInterval-12]
+	_ = x[FlagNameTransferMaxInterval-13]
+	_ = x[FlagNameTransferTimeout-14]
+	_ = x[FlagNameAvailability-15]
+	_ = x[FlagNameUserGroup-16]
+	_ = x[FlagNameS3Endpoint-17]
+	_ = x[FlagNameS3PathStyle-18]
+	_ = x[FlagNameS3PartSize-19]
+	_ = x[FlagNameS3Concurrency-20]
+	_ = x[FlagNameS3MaxRetries-21]
+	_ = x[FlagNameApiMaxRetries-22]
+	_ = x[FlagNameApiMaxBackoff-23]
+	_ = x[FlagNameApiRateLimit-24]
+	_ = x[FlagNameApiMetrics-25]
+	_ = x[FlagNameMetadataOverlay-26]
 }
 
-const _FlagName_name = "metadatasearch-pathverbosegpdb-version"
+const _FlagName_name = "metadatasearch-pathverbosegpdb-versionyesdry-runolder-thanno-rollbackstate-fileresumeoutputparalleltransfer-intervaltransfer-max-intervaltransfer-timeoutavailabilityuser-groups3-endpoints3-path-styles3-part-sizes3-concurrencys3-max-retriesapi-max-retriesapi-max-backoffapi-rate-limitapi-metricsmetadata-overlay"
 
-var _FlagName_index = [...]uint8{0, 8, 19, 26, 38}
+var _FlagName_index = [...]uint16{0, 8, 19, 26, 38, 41, 48, 58, 69, 79, 85, 91, 99, 116, 137, 153, 165, 175, 186, 199, 211, 225, 239, 254, 269, 283, 294, 310}
 
 func (i FlagName) String() string {
 	if i < 0 || i >= FlagName(len(_FlagName_index)-1) {
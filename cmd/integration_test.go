@@ -0,0 +1,270 @@
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/baotingfang/go-pivnet-client/cmd"
+	"github.com/baotingfang/go-pivnet-client/fakepivnet"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	"github.com/baotingfang/go-pivnet-client/wrapper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-cf/go-pivnet/v4"
+)
+
+var integrationMetadataYaml = `
+---
+release:
+  release_type: "Major Release"
+  eula_slug: pivotal_software_eula
+  description: "test description"
+  availability: Admins Only
+  release_date: 2020-05-19
+aws_object_key: ${GPDB_VERSION}/${FILE_NAME}
+file_groups:
+- name: Greenplum Database Server
+  product_files:
+  - file: file://server/greenplum-db-(6\..*)-rhel7-x86_64.rpm
+    upload_as: Greenplum Database ${VERSION_REGEX} Installer for RHEL 7
+    file_type: Software
+    file_version: ${VERSION_REGEX}
+product_files:
+- file: file://osl/open_source_license_pivotal-gpdb-([0-9]+\.[0-9]+\.[0-9]+)-(.*).txt
+  upload_as: Open Source Licenses for GPDB 6.x
+  file_type: Open Source License
+  file_version: ${VERSION_REGEX}
+`
+
+var integrationMetadataJson = `{
+  "release": {
+    "release_type": "Major Release",
+    "eula_slug": "pivotal_software_eula",
+    "availability": "Admins Only",
+    "release_date": "2020-05-19"
+  },
+  "aws_object_key": "${GPDB_VERSION}/${FILE_NAME}",
+  "product_files": [
+    {
+      "file": "file://osl/open_source_license_pivotal-gpdb-([0-9]+\\.[0-9]+\\.[0-9]+)-(.*).txt",
+      "upload_as": "Open Source Licenses for GPDB 6.x",
+      "file_type": "Open Source License",
+      "file_version": "${VERSION_REGEX}"
+    }
+  ]
+}
+`
+
+var _ = Describe("Integration with fake pivnet", func() {
+	var (
+		server    *fakepivnet.Server
+		gpContext gp.Context
+		tmpDir    string
+		options   UploadOptions
+		out       *gbytes.Buffer
+	)
+
+	writeFile := func(path string, content string) {
+		path = filepath.Join(tmpDir, path)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		server = fakepivnet.NewServer()
+		gpContext = gp.NewContext(server.URL, "fakeslug", server.RefreshToken, false, false).
+			WithRetryPolicy(wrapper.RetryPolicy{}).
+			WithRateLimit(0)
+		gpContext.S3 = gp.S3Config{Endpoint: server.S3Endpoint(), ForcePathStyle: true}
+		out = gbytes.NewBuffer()
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "integration-test")
+		Expect(err).NotTo(HaveOccurred())
+		writeFile("metadata.yml", integrationMetadataYaml)
+		writeFile("server/greenplum-db-6.6.0-rhel7-x86_64.rpm", "server rpm")
+		writeFile("osl/open_source_license_pivotal-gpdb-6.6.0-abcdef.txt", "license")
+
+		options = UploadOptions{
+			MetadataFilePath: filepath.Join(tmpDir, "metadata.yml"),
+			SearchPath:       tmpDir,
+			GpdbVersion:      "6.6.0",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("uploads, publishes and destroys the release", func() {
+		err := RunUpload(ctx, gpContext, options, out)
+		Expect(err).NotTo(HaveOccurred())
+
+		releases := server.Releases("fakeslug")
+		Expect(releases).To(HaveLen(1))
+		release := releases[0]
+		Expect(release.Version).To(Equal("6.6.0"))
+		fileGroups := server.ReleaseFileGroups("fakeslug", release.ID)
+		Expect(fileGroups).To(HaveLen(1))
+		Expect(fileGroups[0].Name).To(Equal("Greenplum Database Server"))
+		Expect(fileGroups[0].ProductFiles).To(HaveLen(1))
+		Expect(fileGroups[0].ProductFiles[0].Description).To(Equal("Greenplum Database 6.6.0 Installer for RHEL 7"))
+		productFiles := server.ReleaseProductFiles("fakeslug", release.ID)
+		Expect(productFiles).To(HaveLen(1))
+		Expect(productFiles[0].AWSObjectKey).To(Equal("6.6.0/open_source_license_pivotal-gpdb-6.6.0-abcdef.txt"))
+		content, ok := server.Object(fakepivnet.DefaultBucket, "6.6.0/greenplum-db-6.6.0-rhel7-x86_64.rpm")
+		Expect(ok).To(BeTrue())
+		Expect(string(content)).To(Equal("server rpm"))
+
+		beta := server.AddUserGroup("beta", "beta testers")
+		err = RunPublish(ctx, gpContext, "6.6.0", "Selected User Groups Only", []string{"beta"}, out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Releases("fakeslug")[0].Availability).To(Equal("Selected User Groups Only"))
+		Expect(server.ReleaseUserGroups("fakeslug", release.ID)).To(ConsistOf(beta))
+
+		err = RunDestroy(ctx, gpContext, "6.6.0", true, nil, out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.Releases("fakeslug")).To(BeEmpty())
+		Expect(server.FileGroups("fakeslug")).To(BeEmpty())
+		Expect(server.ProductFiles("fakeslug")).To(BeEmpty())
+	})
+
+	It("uploads the release with the metadata in json", func() {
+		writeFile("metadata.json", integrationMetadataJson)
+		options.MetadataFilePath = filepath.Join(tmpDir, "metadata.json")
+
+		err := RunUpload(ctx, gpContext, options, out)
+		Expect(err).NotTo(HaveOccurred())
+
+		release := server.Releases("fakeslug")[0]
+		productFiles := server.ReleaseProductFiles("fakeslug", release.ID)
+		Expect(productFiles).To(HaveLen(1))
+		Expect(productFiles[0].AWSObjectKey).To(Equal("6.6.0/open_source_license_pivotal-gpdb-6.6.0-abcdef.txt"))
+	})
+
+	It("uploads the release with the metadata overlay", func() {
+		writeFile("gpdb6.yml", `
+release:
+  description: "gpdb 6 description"
+aws_object_key: gpdb6/${GPDB_VERSION}/${FILE_NAME}
+product_files:
+- upload_as: Open Source Licenses for GPDB 6.x
+  file_type: Open Source License
+`)
+		options.MetadataOverlayPaths = []string{filepath.Join(tmpDir, "gpdb6.yml")}
+
+		err := RunUpload(ctx, gpContext, options, out)
+		Expect(err).NotTo(HaveOccurred())
+
+		release := server.Releases("fakeslug")[0]
+		Expect(release.Description).To(Equal("gpdb 6 description"))
+		Expect(server.ReleaseFileGroups("fakeslug", release.ID)).To(HaveLen(1))
+		productFiles := server.ReleaseProductFiles("fakeslug", release.ID)
+		Expect(productFiles).To(HaveLen(1))
+		Expect(productFiles[0].AWSObjectKey).To(Equal("gpdb6/6.6.0/open_source_license_pivotal-gpdb-6.6.0-abcdef.txt"))
+	})
+
+	It("rejects the metadata with unknown fields", func() {
+		writeFile("metadata.json", strings.Replace(integrationMetadataJson, `"upload_as"`, `"upload_ass"`, 1))
+		options.MetadataFilePath = filepath.Join(tmpDir, "metadata.json")
+
+		err := RunUpload(ctx, gpContext, options, out)
+		Expect(err).To(MatchError(ContainSubstring("product_files[0].upload_ass (" + options.MetadataFilePath + ", line 12, column 7): field upload_ass is not supported")))
+		Expect(ExitCode(err)).To(Equal(ExitCodeValidation))
+		Expect(server.Requests()).To(BeEmpty())
+	})
+
+	It("rolls back the upload when pivnet fails to create a product file", func() {
+		server.Fail("POST", "/products/fakeslug/product_files", http.StatusInternalServerError, 2)
+
+		err := RunUpload(ctx, gpContext, options, out)
+		Expect(err).To(HaveOccurred())
+		Expect(out).To(gbytes.Say("upload is not completed, the completed steps below are rolled back, except the reused objects which are kept:"))
+		Expect(out).To(gbytes.Say(`release\s+6.6.0\s+\d+\s+rolled back`))
+
+		Expect(server.Releases("fakeslug")).To(BeEmpty())
+		Expect(server.FileGroups("fakeslug")).To(BeEmpty())
+		Expect(server.ProductFiles("fakeslug")).To(BeEmpty())
+		Expect(server.ObjectKeys(fakepivnet.DefaultBucket)).To(BeEmpty())
+	})
+
+	It("keeps the reused release when the upload is rolled back", func() {
+		release := server.AddRelease("fakeslug", pivnet.Release{Version: "6.6.0"})
+		server.Fail("POST", "/products/fakeslug/product_files", http.StatusInternalServerError, 2)
+
+		err := RunUpload(ctx, gpContext, options, out)
+		Expect(err).To(HaveOccurred())
+		Expect(out).To(gbytes.Say(`release\s+6.6.0\s+\d+\s+kept`))
+		Expect(out).To(gbytes.Say(`file group\s+Greenplum Database Server\s+\d+\s+(yes|no)\s+rolled back`))
+
+		Expect(server.Releases("fakeslug")).To(ConsistOf(release))
+		Expect(server.FileGroups("fakeslug")).To(BeEmpty())
+	})
+
+	It("rolls back the product file which is created after the upload is cancelled", func() {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		server.Before("POST", "/products/fakeslug/product_files", func() {
+			cancel()
+			time.Sleep(100 * time.Millisecond)
+		})
+
+		err := RunUpload(cancelCtx, gpContext, options, out)
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		Expect(server.Requests()).To(ContainElement("POST /products/fakeslug/product_files"))
+
+		Expect(server.Releases("fakeslug")).To(BeEmpty())
+		Expect(server.FileGroups("fakeslug")).To(BeEmpty())
+		Expect(server.ProductFiles("fakeslug")).To(BeEmpty())
+		Expect(server.ObjectKeys(fakepivnet.DefaultBucket)).To(BeEmpty())
+	})
+
+	It("only cleans the orphans older than the age", func() {
+		server.Fail("PATCH", "/products/fakeslug/releases/2/add_product_file", http.StatusInternalServerError, 1)
+		options.NoRollback = true
+
+		err := RunUpload(ctx, gpContext, options, out)
+		Expect(err).To(HaveOccurred())
+		Expect(server.ProductFiles("fakeslug")).To(HaveLen(2))
+
+		err = RunClean(ctx, gpContext, CleanOptions{AssumeYes: true, OlderThan: "7d"}, strings.NewReader(""), out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(gbytes.Say("no orphaned product files or file groups"))
+		Expect(server.ProductFiles("fakeslug")).To(HaveLen(2))
+
+		for _, key := range server.ObjectKeys(fakepivnet.DefaultBucket) {
+			server.SetObjectLastModified(fakepivnet.DefaultBucket, key, time.Now().AddDate(0, 0, -8))
+		}
+		err = RunClean(ctx, gpContext, CleanOptions{AssumeYes: true, OlderThan: "7d"}, strings.NewReader(""), out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.ProductFiles("fakeslug")).To(HaveLen(1))
+		Expect(server.ProductFiles("fakeslug")[0].AWSObjectKey).To(Equal("6.6.0/greenplum-db-6.6.0-rhel7-x86_64.rpm"))
+	})
+
+	It("resumes the upload from the saved state after pivnet failed", func() {
+		server.Fail("PATCH", "/products/fakeslug/releases/2/add_product_file", http.StatusInternalServerError, 1)
+		options.StateFilePath = filepath.Join(tmpDir, "state.json")
+
+		err := RunUpload(ctx, gpContext, options, out)
+		Expect(err).To(HaveOccurred())
+		Expect(out).To(gbytes.Say("rollback is skipped because the progress is saved to " + options.StateFilePath + " for resume"))
+		Expect(server.Releases("fakeslug")).To(HaveLen(1))
+
+		options.StateFilePath = ""
+		options.ResumeFilePath = filepath.Join(tmpDir, "state.json")
+		err = RunUpload(ctx, gpContext, options, out)
+		Expect(err).NotTo(HaveOccurred())
+
+		release := server.Releases("fakeslug")[0]
+		Expect(server.ReleaseProductFiles("fakeslug", release.ID)).To(HaveLen(1))
+		Expect(server.ProductFiles("fakeslug")).To(HaveLen(2))
+	})
+})
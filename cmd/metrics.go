@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/baotingfang/go-pivnet-client/wrapper"
+	"io"
+	"io/ioutil"
+	"text/tabwriter"
+	"time"
+)
+
+var requestsPerSecond float64
+
+func init() {
+	rootCmd.PersistentFlags().Float64Var(&requestsPerSecond, FlagNameApiRateLimit.String(), wrapper.DefaultRequestsPerSecond,
+		"Max number of pivnet api requests per second, 0 disables the limit")
+}
+
+// PrintApiMetrics prints the calls, errors and latencies of every called pivnet api
+func PrintApiMetrics(out io.Writer, metrics []wrapper.MethodMetrics) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "PIVNET API\tCALLS\tERRORS\tMEAN\tMAX\tTOTAL")
+	for _, m := range metrics {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n", m.Method, m.Calls, m.Errors,
+			m.MeanLatency().Round(time.Millisecond), m.MaxLatency.Round(time.Millisecond), m.TotalLatency.Round(time.Millisecond))
+	}
+	_ = w.Flush()
+}
+
+func WriteApiMetrics(path string, metrics []wrapper.MethodMetrics) error {
+	content, err := json.MarshalIndent(metrics, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		return NewStorageError(err, "can not write pivnet api metrics to %s", path)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/service"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	OutputFormatText = "text"
+	OutputFormatJson = "json"
+)
+
+func PrintUploadPlan(out io.Writer, plan service.UploadPlan, format string) error {
+	switch format {
+	case OutputFormatJson:
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	case OutputFormatText, "":
+		printUploadPlanText(out, plan)
+		return nil
+	default:
+		return NewValidationError("not support output format: %s", format)
+	}
+}
+
+func printUploadPlanText(out io.Writer, plan service.UploadPlan) {
+	w := tabwriter.NewWriter(out, 0, 4, 1, ' ', 0)
+
+	r := plan.Release
+	_, _ = fmt.Fprintln(w, "Release:")
+	_, _ = fmt.Fprintf(w, "  product slug:\t%s\n", r.ProductSlug)
+	_, _ = fmt.Fprintf(w, "  version:\t%s\n", r.Version)
+	_, _ = fmt.Fprintf(w, "  release type:\t%s\n", r.ReleaseType)
+	_, _ = fmt.Fprintf(w, "  release date:\t%s\n", r.ReleaseDate)
+	_, _ = fmt.Fprintf(w, "  eula slug:\t%s\n", r.EULASlug)
+	_, _ = fmt.Fprintf(w, "  description:\t%s\n", r.Description)
+	_, _ = fmt.Fprintf(w, "  release notes url:\t%s\n", r.ReleaseNotesURL)
+	_, _ = fmt.Fprintf(w, "  eccn:\t%s\n", r.ECCN)
+	_, _ = fmt.Fprintf(w, "  license exception:\t%s\n", r.LicenseException)
+	_, _ = fmt.Fprintf(w, "  end of support date:\t%s\n", r.EndOfSupportDate)
+	_, _ = fmt.Fprintf(w, "  end of guidance date:\t%s\n", r.EndOfGuidanceDate)
+	_, _ = fmt.Fprintf(w, "  end of availability date:\t%s\n", r.EndOfAvailabilityDate)
+
+	for _, group := range plan.FileGroups {
+		_, _ = fmt.Fprintf(w, "File group: %s\n", group.Name)
+		for _, pf := range group.ProductFiles {
+			printProductFilePlan(w, "  ", pf)
+		}
+	}
+
+	if len(plan.ProductFiles) > 0 {
+		_, _ = fmt.Fprintln(w, "Product files:")
+		for _, pf := range plan.ProductFiles {
+			printProductFilePlan(w, "  ", pf)
+		}
+	}
+
+	_ = w.Flush()
+}
+
+func printProductFilePlan(w io.Writer, indent string, pf service.ProductFilePlan) {
+	c := pf.ProductFile
+	_, _ = fmt.Fprintf(w, "%sProduct file: %s\n", indent, pf.File)
+	_, _ = fmt.Fprintf(w, "%s  local file:\t%s\n", indent, pf.LocalFilePath)
+	_, _ = fmt.Fprintf(w, "%s  aws object key:\t%s\n", indent, pf.AwsObjectKey)
+	_, _ = fmt.Fprintf(w, "%s  name:\t%s\n", indent, c.Name)
+	_, _ = fmt.Fprintf(w, "%s  description:\t%s\n", indent, c.Description)
+	_, _ = fmt.Fprintf(w, "%s  file type:\t%s\n", indent, c.FileType)
+	_, _ = fmt.Fprintf(w, "%s  file version:\t%s\n", indent, c.FileVersion)
+	_, _ = fmt.Fprintf(w, "%s  docs url:\t%s\n", indent, c.DocsURL)
+	_, _ = fmt.Fprintf(w, "%s  platforms:\t%s\n", indent, strings.Join(c.Platforms, ", "))
+	_, _ = fmt.Fprintf(w, "%s  sha256:\t%s\n", indent, c.SHA256)
+	_, _ = fmt.Fprintf(w, "%s  md5:\t%s\n", indent, c.MD5)
+}
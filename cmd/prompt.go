@@ -0,0 +1,20 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func confirm(in io.Reader, out io.Writer, message string) bool {
+	_, _ = fmt.Fprintf(out, "%s [y/N]: ", message)
+
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
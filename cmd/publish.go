@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	"github.com/baotingfang/go-pivnet-client/service"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	publishCmdFlagsInit sync.Once
+
+	publishVerbose     bool
+	publishGpdbVersion string
+	availability       string
+	userGroups         []string
+)
+
+var publishCmd = &cobra.Command{
+	Use:   "publish [-v] [--user-group group]... <-a availability> <-g gpdb_version>",
+	Short: "Change the availability of a release on pivnet",
+	Long: `Given a gpdb version, this program will check that all product files of the release have been transferred,
+add the user groups to the release, and change the availability of the release to one of: ` +
+		strings.Join(service.Availabilities, ", "),
+	Run: func(cmd *cobra.Command, args []string) {
+		logLevel := vlog.InfoLevel
+		if publishVerbose {
+			logLevel = vlog.DebugLevel
+		}
+		vlog.InitLog("Publish ", logLevel)
+		ctx := context.Background()
+
+		summaryItems := []SummaryItem{
+			{Name: "gpdb version", Value: publishGpdbVersion},
+			{Name: "availability", Value: availability},
+		}
+
+		context, err := gp.NewContextFromEnv(false, publishVerbose)
+		if err != nil {
+			NewErrorSummary("publish", err, summaryItems...).Print(os.Stderr)
+			os.Exit(ExitCode(err))
+		}
+		context = context.WithRetryPolicy(retryPolicy).WithRateLimit(requestsPerSecond)
+
+		summaryItems = append(summaryItems,
+			SummaryItem{Name: "endpoint", Value: context.BaseUrl},
+			SummaryItem{Name: "product slug", Value: context.Slug},
+		)
+
+		err = RunPublish(ctx, context, publishGpdbVersion, availability, userGroups, os.Stdout)
+		if err != nil {
+			NewErrorSummary("publish", err, summaryItems...).Print(os.Stderr)
+			os.Exit(ExitCode(err))
+		}
+	},
+}
+
+func RunPublish(ctx context.Context, context gp.Context, gpdbVersion string, availability string, userGroups []string, out io.Writer) error {
+	publisher := service.NewPublisher(context, gpdbVersion, availability, userGroups)
+
+	release, err := publisher.Publish(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(out, "release %s (id=%d) is available to: %s\n", release.Version, release.ID, release.Availability)
+	return nil
+}
+
+func init() {
+	publishCmdFlagsInit.Do(func() {
+		publishCmd.Flags().StringVarP(&publishGpdbVersion, FlagNameGpdbVersion.String(), "g", "", "GPDB version of the release to publish")
+		publishCmd.Flags().StringVarP(&availability, FlagNameAvailability.String(), "a", "",
+			"Availability of the release: "+strings.Join(service.Availabilities, ", "))
+		publishCmd.Flags().StringSliceVar(&userGroups, FlagNameUserGroup.String(), nil,
+			"Name of the user group to add to the release, only for "+service.AvailabilitySelectedUserGroups)
+		publishCmd.Flags().BoolVarP(&publishVerbose, FlagNameVerbose.String(), "v", false, "Verbose output")
+
+		publishCmdRequiredFlags := []string{
+			FlagNameGpdbVersion.String(),
+			FlagNameAvailability.String(),
+		}
+
+		for _, flag := range publishCmdRequiredFlags {
+			err := publishCmd.MarkFlagRequired(flag)
+			if err != nil {
+				vlog.Fatal(err.Error())
+			}
+		}
+
+		rootCmd.AddCommand(publishCmd)
+	})
+}
@@ -0,0 +1,104 @@
+package cmd_test
+
+import (
+	"net/http"
+
+	. "github.com/baotingfang/go-pivnet-client/cmd"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/pivotal-cf/go-pivnet/v4"
+)
+
+var _ = Describe("Publish", func() {
+	var (
+		server  *ghttp.Server
+		context gp.Context
+		out     *gbytes.Buffer
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		context = gp.NewContext(server.URL(), "fakeslug", "faketoken", true, false).WithRateLimit(0)
+		out = gbytes.NewBuffer()
+
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{
+					Releases: []pivnet.Release{{ID: 10, Version: "6.6.0", Availability: "Admins Only"}},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases/10/file_groups"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.FileGroupsResponse{
+					FileGroups: []pivnet.FileGroup{
+						{ID: 20, Name: "server", ProductFiles: []pivnet.ProductFile{{ID: 30, Name: "rhel7"}}},
+					},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases/10/product_files"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFilesResponse{}),
+			),
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("adds the user groups and changes the availability", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/product_files/30"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFileResponse{
+					ProductFile: pivnet.ProductFile{ID: 30, FileTransferStatus: "complete"},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/user_groups"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.UserGroupsResponse{
+					UserGroups: []pivnet.UserGroup{{ID: 40, Name: "beta"}},
+				}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases/10/user_groups"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.UserGroupsResponse{}),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PATCH", "/api/v2/products/fakeslug/releases/10/add_user_group"),
+				ghttp.VerifyJSON(`{"user_group": {"id": 40}}`),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PATCH", "/api/v2/products/fakeslug/releases/10"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.CreateReleaseResponse{
+					Release: pivnet.Release{ID: 10, Version: "6.6.0", Availability: "Selected User Groups Only"},
+				}),
+			),
+		)
+
+		err := RunPublish(ctx, context, "6.6.0", "Selected User Groups Only", []string{"beta"}, out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out).To(gbytes.Say(`release 6.6.0 \(id=10\) is available to: Selected User Groups Only`))
+		Expect(server.ReceivedRequests()).To(HaveLen(8))
+	})
+
+	It("does not change the availability while the file transfer is in progress", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/product_files/30"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFileResponse{
+					ProductFile: pivnet.ProductFile{ID: 30, FileTransferStatus: "in_progress"},
+				}),
+			),
+		)
+
+		err := RunPublish(ctx, context, "6.6.0", "All Users", nil, out)
+		Expect(err).To(MatchError("file transfer of the product files are still in progress:\nrhel7 (id=30)"))
+		Expect(server.ReceivedRequests()).To(HaveLen(4))
+	})
+})
@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"github.com/baotingfang/go-pivnet-client/wrapper"
 	"github.com/spf13/cobra"
 	"os"
 )
 
 var (
 	Version string
+
+	retryPolicy = wrapper.DefaultRetryPolicy()
 )
 
 var rootCmd = &cobra.Command{
@@ -17,6 +20,13 @@ var rootCmd = &cobra.Command{
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(ExitCode(err))
 	}
 }
+
+func init() {
+	rootCmd.PersistentFlags().IntVar(&retryPolicy.MaxRetries, FlagNameApiMaxRetries.String(), wrapper.DefaultMaxRetries,
+		"Max number of retries of a failed pivnet api call, 0 disables the retries")
+	rootCmd.PersistentFlags().DurationVar(&retryPolicy.MaxBackoff, FlagNameApiMaxBackoff.String(), wrapper.DefaultMaxBackoff,
+		"Max backoff between the retries of a pivnet api call, the backoff is doubled after every retry, and the Retry-After of pivnet is capped by it")
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"github.com/baotingfang/go-pivnet-client/config"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the json schema of the metadata file",
+	Long: `This program will print the json schema of the metadata file, which can be used by the editors to complete
+the metadata files, and by CI to validate the metadata files in yaml, json or toml format before uploading`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := RunSchema(os.Stdout)
+		if err != nil {
+			NewErrorSummary("schema", err).Print(os.Stderr)
+			os.Exit(ExitCode(err))
+		}
+	},
+}
+
+func RunSchema(out io.Writer) error {
+	schema, err := config.MetadataSchemaJSON()
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(append(schema, '\n'))
+	return err
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,23 @@
+package cmd_test
+
+import (
+	"encoding/json"
+
+	. "github.com/baotingfang/go-pivnet-client/cmd"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Schema", func() {
+	It("prints the json schema of the metadata", func() {
+		out := gbytes.NewBuffer()
+		err := RunSchema(out)
+		Expect(err).NotTo(HaveOccurred())
+
+		var schema map[string]interface{}
+		Expect(json.Unmarshal(out.Contents(), &schema)).To(Succeed())
+		Expect(schema).To(HaveKeyWithValue("$schema", "http://json-schema.org/draft-07/schema#"))
+		Expect(schema["properties"]).To(HaveKey("release"))
+	})
+})
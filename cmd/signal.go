@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"context"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// cancelOnSignal returns a context which is cancelled by the first SIGINT or SIGTERM,
+// so that the command can stop and clean up. The process exits at once on the second one.
+// The returned cancel func stops watching the signals.
+func cancelOnSignal(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case sig := <-signals:
+			vlog.Warn("received %s, stopping, send it again to exit at once", sig)
+			cancel()
+		case <-done:
+			return
+		}
+
+		select {
+		case sig := <-signals:
+			vlog.Error("received %s again, exit at once", sig)
+			os.Exit(ExitCodeInterrupted)
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+		cancel()
+	}
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/service"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type SummaryItem struct {
+	Name  string
+	Value string
+}
+
+type ErrorSummary struct {
+	Command string
+	Items   []SummaryItem
+	Err     error
+}
+
+func NewErrorSummary(command string, err error, items ...SummaryItem) ErrorSummary {
+	return ErrorSummary{
+		Command: command,
+		Items:   items,
+		Err:     err,
+	}
+}
+
+func (s ErrorSummary) Print(w io.Writer) {
+	items := append([]SummaryItem{}, s.Items...)
+	items = append(items, SummaryItem{Name: "error", Value: s.Err.Error()})
+	items = append(items, remoteErrorItems(s.Err)...)
+
+	width := 0
+	for _, item := range items {
+		if len(item.Name) > width {
+			width = len(item.Name)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "%s failed:\n", s.Command)
+	for _, item := range items {
+		_, _ = fmt.Fprintf(w, "  %-*s %s\n", width+1, item.Name+":", item.Value)
+	}
+}
+
+func remoteErrorItems(err error) []SummaryItem {
+	var otherErr pivnet.ErrPivnetOther
+	if errors.As(err, &otherErr) {
+		items := []SummaryItem{
+			{Name: "response code", Value: fmt.Sprintf("%d", otherErr.ResponseCode)},
+		}
+		if len(otherErr.Errors) > 0 {
+			items = append(items, SummaryItem{Name: "remote errors", Value: strings.Join(otherErr.Errors, ", ")})
+		}
+		return items
+	}
+
+	var notFoundErr pivnet.ErrNotFound
+	if errors.As(err, &notFoundErr) {
+		return []SummaryItem{{Name: "response code", Value: fmt.Sprintf("%d", notFoundErr.ResponseCode)}}
+	}
+
+	var unauthorizedErr pivnet.ErrUnauthorized
+	if errors.As(err, &unauthorizedErr) {
+		return []SummaryItem{{Name: "response code", Value: fmt.Sprintf("%d", unauthorizedErr.ResponseCode)}}
+	}
+
+	var tooManyRequestsErr pivnet.ErrTooManyRequests
+	if errors.As(err, &tooManyRequestsErr) {
+		return []SummaryItem{{Name: "response code", Value: fmt.Sprintf("%d", tooManyRequestsErr.ResponseCode)}}
+	}
+
+	return nil
+}
+
+// PrintUploadSummary prints what was and was not completed by a failed or interrupted upload,
+// and what has been done to the completed steps
+func PrintUploadSummary(out io.Writer, summary service.UploadSummary) {
+	switch summary.Rollback {
+	case service.RolledBack:
+		_, _ = fmt.Fprintln(out, "upload is not completed, the completed steps below are rolled back, except the reused objects which are kept:")
+	case service.RollbackFailed:
+		_, _ = fmt.Fprintln(out, "upload is not completed, the rollback failed, the objects below are failed to delete:")
+		for _, entry := range summary.RollbackFailures {
+			_, _ = fmt.Fprintf(out, "  %s\n", entry)
+		}
+		_, _ = fmt.Fprintln(out, "the completed steps below are rolled back, except the objects above and the reused objects which are kept:")
+	case service.RollbackSkipped:
+		_, _ = fmt.Fprintf(out, "upload is not completed, rollback is skipped because the progress is saved to %s for resume, "+
+			"the completed steps below are kept on pivnet:\n", summary.StatePath)
+	default:
+		_, _ = fmt.Fprintln(out, "upload is not completed, the completed steps below are kept on pivnet:")
+	}
+
+	done := func(completed bool) string {
+		if completed {
+			return "yes"
+		}
+		return "no"
+	}
+	id := func(id int) string {
+		if id == 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%d", id)
+	}
+	status := func(id int, rolledBack bool) string {
+		switch {
+		case id == 0:
+			return "-"
+		case rolledBack:
+			return "rolled back"
+		default:
+			return "kept"
+		}
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tNAME\tID\tUPLOADED\tATTACHED\tSTATUS")
+	_, _ = fmt.Fprintf(w, "release\t%s\t%s\t\t\t%s\n", summary.ReleaseVersion, id(summary.ReleaseID),
+		status(summary.ReleaseID, summary.ReleaseRolledBack))
+	for _, group := range summary.FileGroups {
+		_, _ = fmt.Fprintf(w, "file group\t%s\t%s\t\t%s\t%s\n", group.Name, id(group.ID), done(group.Attached),
+			status(group.ID, group.RolledBack))
+	}
+	for _, pf := range summary.ProductFiles {
+		name := pf.UploadAs
+		if pf.FileGroup != "" {
+			name = pf.FileGroup + "/" + pf.UploadAs
+		}
+		_, _ = fmt.Fprintf(w, "product file\t%s\t%s\t%s\t%s\t%s\n", name, id(pf.ProductFileID), done(pf.Uploaded), done(pf.Attached),
+			status(pf.ProductFileID, pf.RolledBack))
+	}
+	_ = w.Flush()
+}
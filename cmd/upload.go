@@ -1,7 +1,15 @@
 package cmd
 
 import (
+	"context"
+	"github.com/baotingfang/go-pivnet-client/config"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	"github.com/baotingfang/go-pivnet-client/service"
+	. "github.com/baotingfang/go-pivnet-client/utils"
 	"github.com/baotingfang/go-pivnet-client/vlog"
+	"io"
+	"os"
+	"strings"
 	"sync"
 
 	"github.com/spf13/cobra"
@@ -10,29 +18,201 @@ import (
 var (
 	uploadCmdFlagsInit sync.Once
 
-	metaDataFilePath string
-	searchPath       string
-	verbose          bool
-	gpdbVersion      string
+	metaDataFilePath     string
+	metadataOverlayPaths []string
+	searchPath           string
+	verbose              bool
+	gpdbVersion          string
+	noRollback           bool
+	stateFilePath        string
+	resumeFilePath       string
+	outputFormat         string
+	parallel             int
+	transferWait         service.FileTransferWait
+	s3Config             gp.S3Config
+	s3PartSizeMB         int64
+	metricsFilePath      string
+	dryRun               bool
 
 	logLevel = vlog.InfoLevel
 )
 
 var uploadCmd = &cobra.Command{
-	Use:   "upload [-v] [-s search_path] <-m metadata_file> <-g gpdb_version>",
+	Use:   "upload [-v] [-s search_path] [--metadata-overlay file ...] [--no-rollback] [-p parallel] [--s3-endpoint url [--s3-path-style]] [--state-file state | --resume state] [--dry-run [-o text|json]] <-m metadata_file> <-g gpdb_version>",
 	Short: "Upload artifacts to pivnet",
 	Long:  `Given metadata specifying a pivnet release with file groups and/or product files, this program will perform the necessary actions to create those components on pivnet`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if verbose {
+			logLevel = vlog.DebugLevel
+		}
 		vlog.InitLog("Upload ", logLevel)
+		if dryRun {
+			// the plan is the only output on stdout, so that it can be parsed
+			vlog.Log.OutLogger.SetOutput(os.Stderr)
+		}
+
+		// the upload is cancelled by SIGINT or SIGTERM, then the created objects are
+		// rolled back, or kept in the state file to resume the upload.
+		ctx, stop := cancelOnSignal(context.Background())
+		defer stop()
+
+		summaryItems := []SummaryItem{
+			{Name: "gpdb version", Value: gpdbVersion},
+			{Name: "metadata", Value: metaDataFilePath},
+			{Name: "search path", Value: searchPath},
+		}
+		if len(metadataOverlayPaths) > 0 {
+			summaryItems = append(summaryItems, SummaryItem{Name: "metadata overlays", Value: strings.Join(metadataOverlayPaths, ", ")})
+		}
+
+		// the api client logs to stdout, it is kept quiet in dry run mode
+		gpContext, err := gp.NewContextFromEnv(false, verbose && !dryRun)
+		if err != nil {
+			NewErrorSummary("upload", err, summaryItems...).Print(os.Stderr)
+			os.Exit(ExitCode(err))
+		}
+		gpContext = gpContext.WithRetryPolicy(retryPolicy).WithRateLimit(requestsPerSecond)
+
+		summaryItems = append(summaryItems,
+			SummaryItem{Name: "endpoint", Value: gpContext.BaseUrl},
+			SummaryItem{Name: "product slug", Value: gpContext.Slug},
+		)
+		if s3Config.Endpoint != "" {
+			summaryItems = append(summaryItems, SummaryItem{Name: "s3 endpoint", Value: s3Config.Endpoint})
+		}
+
+		gpContext.S3 = s3Config
+		gpContext.S3.PartSize = s3PartSizeMB * 1024 * 1024
+
+		options := UploadOptions{
+			MetadataFilePath:     metaDataFilePath,
+			MetadataOverlayPaths: metadataOverlayPaths,
+			SearchPath:           searchPath,
+			GpdbVersion:          gpdbVersion,
+			NoRollback:           noRollback,
+			StateFilePath:        stateFilePath,
+			ResumeFilePath:       resumeFilePath,
+			DryRun:               dryRun,
+			OutputFormat:         outputFormat,
+			Parallel:             parallel,
+			TransferWait:         transferWait,
+			MetricsFilePath:      metricsFilePath,
+		}
+		err = RunUpload(ctx, gpContext, options, os.Stdout)
+		if err != nil {
+			NewErrorSummary("upload", err, summaryItems...).Print(os.Stderr)
+			os.Exit(ExitCode(err))
+		}
+
+		vlog.Info("gpdb %s has been uploaded to pivnet", gpdbVersion)
 	},
 }
 
+type UploadOptions struct {
+	MetadataFilePath     string
+	MetadataOverlayPaths []string
+	SearchPath           string
+	GpdbVersion          string
+	NoRollback           bool
+	StateFilePath        string
+	ResumeFilePath       string
+	DryRun               bool
+	OutputFormat         string
+	Parallel             int
+	TransferWait         service.FileTransferWait
+	MetricsFilePath      string
+}
+
+func RunUpload(ctx context.Context, gpContext gp.Context, options UploadOptions, out io.Writer) error {
+	if options.StateFilePath != "" && options.ResumeFilePath != "" {
+		return NewValidationError("can not specify both %s and %s", FlagNameStateFile, FlagNameResume)
+	}
+
+	if err := gpContext.S3.Validate(); err != nil {
+		return err
+	}
+
+	metadataFilePaths := append([]string{options.MetadataFilePath}, options.MetadataOverlayPaths...)
+	metadata, err := config.MetadataFromFiles(options.GpdbVersion, metadataFilePaths...)
+	if err != nil {
+		return err
+	}
+
+	uploader := service.NewUploader(gpContext, options.GpdbVersion, metadata, options.SearchPath)
+	uploader.NoRollback = options.NoRollback
+	uploader.Parallel = options.Parallel
+	uploader.TransferWait = options.TransferWait
+	uploader.Progress = service.NewProgressReporter(out)
+
+	if options.DryRun {
+		plan, err := uploader.Plan(ctx)
+		if err != nil {
+			return err
+		}
+		return PrintUploadPlan(out, plan, options.OutputFormat)
+	}
+
+	if options.StateFilePath != "" {
+		uploader.State = service.NewUploadState(options.GpdbVersion, options.StateFilePath)
+	}
+
+	if options.ResumeFilePath != "" {
+		state, err := service.LoadUploadState(options.ResumeFilePath, options.GpdbVersion)
+		if err != nil {
+			return err
+		}
+		uploader.State = state
+	}
+
+	err = uploader.Run(ctx)
+	if err != nil {
+		PrintUploadSummary(out, uploader.Summary())
+	}
+	if gpContext.Metrics != nil {
+		// the metrics are printed even if the upload failed, to diagnose the failure
+		metrics := gpContext.Metrics.Snapshot()
+		PrintApiMetrics(out, metrics)
+		if options.MetricsFilePath != "" {
+			if writeErr := WriteApiMetrics(options.MetricsFilePath, metrics); writeErr != nil {
+				vlog.Warn(writeErr.Error())
+			}
+		}
+	}
+	return err
+}
+
 func init() {
 	uploadCmdFlagsInit.Do(func() {
-		uploadCmd.Flags().StringVarP(&metaDataFilePath, FlagNameMetaFilePath.String(), "m", "", "Path to a valid pivnet client metadata yaml file")
+		uploadCmd.Flags().StringVarP(&metaDataFilePath, FlagNameMetaFilePath.String(), "m", "", "Path to a valid pivnet client metadata file in yaml, json or toml format")
+		uploadCmd.Flags().StringArrayVar(&metadataOverlayPaths, FlagNameMetadataOverlay.String(), nil,
+			"Path to a metadata file merged onto the metadata file, can be specified multiple times, the later overlays win")
 		uploadCmd.Flags().StringVarP(&searchPath, FlagNameSearchPath.String(), "s", ".", "Path to look for product files defined in metadata")
 		uploadCmd.Flags().BoolVarP(&verbose, FlagNameVerbose.String(), "v", false, "Verbose output")
 		uploadCmd.Flags().StringVarP(&gpdbVersion, FlagNameGpdbVersion.String(), "g", "", "GPDB version from getversion tool")
+		uploadCmd.Flags().BoolVar(&noRollback, FlagNameNoRollback.String(), false, "Keep the created objects on pivnet when the upload failed, for debugging")
+		uploadCmd.Flags().StringVar(&stateFilePath, FlagNameStateFile.String(), "", "Save the upload progress to the state file, so that a failed upload can be resumed")
+		uploadCmd.Flags().StringVar(&resumeFilePath, FlagNameResume.String(), "", "Resume a failed upload from the state file")
+		uploadCmd.Flags().IntVarP(&parallel, FlagNameParallel.String(), "p", 1, "Number of product files uploaded at the same time")
+		uploadCmd.Flags().DurationVar(&transferWait.Interval, FlagNameTransferInterval.String(),
+			service.DefaultFileTransferInterval, "Interval to check whether pivnet has completed the file transfer")
+		uploadCmd.Flags().DurationVar(&transferWait.MaxInterval, FlagNameTransferMaxInterval.String(),
+			service.DefaultFileTransferMaxInterval, "Max interval to check the file transfer, the interval is doubled after every check")
+		uploadCmd.Flags().DurationVar(&transferWait.Timeout, FlagNameTransferTimeout.String(),
+			service.DefaultFileTransferTimeout, "Timeout to wait for pivnet completing the file transfer of a product file")
+		uploadCmd.Flags().StringVar(&s3Config.Endpoint, FlagNameS3Endpoint.String(), "",
+			"Endpoint url of a s3 compatible service to upload the product files, instead of aws s3")
+		uploadCmd.Flags().BoolVar(&s3Config.ForcePathStyle, FlagNameS3PathStyle.String(), false,
+			"Use path style addressing of s3 bucket, which is required by most of s3 compatible services")
+		uploadCmd.Flags().Int64Var(&s3PartSizeMB, FlagNameS3PartSize.String(), 0,
+			"Size in MiB of the parts uploaded for a product file, at least 5, the default is 5")
+		uploadCmd.Flags().IntVar(&s3Config.Concurrency, FlagNameS3Concurrency.String(), 0,
+			"Number of parts uploaded at the same time for a product file, the default is 5")
+		uploadCmd.Flags().IntVar(&s3Config.MaxRetries, FlagNameS3MaxRetries.String(), 0,
+			"Max number of retries of a failed s3 request, the default is the retries of aws sdk")
+		uploadCmd.Flags().BoolVar(&dryRun, FlagNameDryRun.String(), false, "Only print the upload plan, nothing is created on pivnet")
+		uploadCmd.Flags().StringVar(&metricsFilePath, FlagNameApiMetrics.String(), "",
+			"Write the calls, errors and latencies of pivnet api to the file as json")
+		uploadCmd.Flags().StringVarP(&outputFormat, FlagNameOutput.String(), "o", OutputFormatText, "Output format of the upload plan: text or json")
 
 		uploadCmdRequiredFlags := []string{
 			FlagNameMetaFilePath.String(),
@@ -46,10 +226,6 @@ func init() {
 			}
 		}
 
-		if verbose {
-			logLevel = vlog.DebugLevel
-		}
-
 		rootCmd.AddCommand(uploadCmd)
 	})
 }
@@ -0,0 +1,365 @@
+package cmd_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	. "github.com/baotingfang/go-pivnet-client/cmd"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	"github.com/baotingfang/go-pivnet-client/service"
+	"github.com/baotingfang/go-pivnet-client/wrapper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/pivotal-cf/go-pivnet/v4"
+)
+
+var releaseOnlyMetadataYaml = `
+---
+release:
+  release_type: "Major Release"
+  eula_slug: pivotal_software_eula
+  description: "test description"
+  release_notes_url: "http://example.com/notes/url"
+  availability: Admins Only
+  release_date: 2020-05-19
+  end_of_support_date: 2023-05-31
+  end_of_guidance_date: 2024-05-31
+  end_of_availability_date: 2025-05-31
+`
+
+var _ = Describe("Upload", func() {
+	var (
+		server       *ghttp.Server
+		context      gp.Context
+		tmpDir       string
+		metadataPath string
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		context = gp.NewContext(server.URL(), "fakeslug", "faketoken", true, false).WithRateLimit(0)
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "upload-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		metadataPath = filepath.Join(tmpDir, "metadata.yml")
+		err = ioutil.WriteFile(metadataPath, []byte(releaseOnlyMetadataYaml), 0644)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	Context("RunUpload", func() {
+		It("creates the release against the pivnet api", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases"),
+					ghttp.VerifyHeader(http.Header{"Authorization": []string{"Token faketoken"}}),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v2/products/fakeslug/releases"),
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, pivnet.CreateReleaseResponse{
+						Release: pivnet.Release{ID: 1, Version: "6.6.0"},
+					}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v2/federation_token"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.FederationToken{Bucket: "fakebucket"}),
+				),
+			)
+
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+			}, gbytes.NewBuffer())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(5))
+		})
+
+		It("prints and writes the metrics of pivnet api", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusCreated, pivnet.CreateReleaseResponse{
+					Release: pivnet.Release{ID: 1, Version: "6.6.0"},
+				}),
+				ghttp.RespondWithJSONEncoded(http.StatusInternalServerError, map[string]string{"error": "server error"}),
+			)
+			context = context.WithRetryPolicy(wrapper.RetryPolicy{})
+			metricsPath := filepath.Join(tmpDir, "metrics.json")
+
+			out := gbytes.NewBuffer()
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+				NoRollback:       true,
+				MetricsFilePath:  metricsPath,
+			}, out)
+			Expect(err).To(HaveOccurred())
+
+			Expect(out).To(gbytes.Say(`PIVNET API\s+CALLS\s+ERRORS\s+MEAN\s+MAX\s+TOTAL`))
+			Expect(out).To(gbytes.Say(`CreateFederationToken\s+1\s+1\s`))
+			Expect(out).To(gbytes.Say(`CreateRelease\s+1\s+0\s`))
+			Expect(out).To(gbytes.Say(`GetAllReleases\s+3\s+0\s`))
+
+			content, err := ioutil.ReadFile(metricsPath)
+			Expect(err).NotTo(HaveOccurred())
+			var metrics []wrapper.MethodMetrics
+			Expect(json.Unmarshal(content, &metrics)).To(Succeed())
+			Expect(metrics).To(HaveLen(3))
+			Expect(metrics[2].Method).To(Equal("GetAllReleases"))
+			Expect(metrics[2].Calls).To(Equal(3))
+			Expect(metrics[2].Histogram).To(HaveLen(len(wrapper.LatencyBuckets) + 1))
+		})
+
+		It("returns error for invalid s3 config before creating anything", func() {
+			context.S3 = gp.S3Config{Endpoint: "localhost:9000", PartSize: 1024}
+
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+			}, gbytes.NewBuffer())
+			Expect(err).To(MatchError("s3 endpoint is not a valid url: localhost:9000"))
+
+			context.S3.Endpoint = "http://localhost:9000"
+			err = RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+			}, gbytes.NewBuffer())
+			Expect(err).To(MatchError("s3 part size 1024 is less than the min part size 5242880"))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+
+		It("returns the remote error when creating release failed", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusUnprocessableEntity, map[string]interface{}{
+					"message": "release already exists",
+					"errors":  []string{"version has already been taken"},
+				}),
+			)
+
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+			}, gbytes.NewBuffer())
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrPivnetOther{}))
+			Expect(err.(pivnet.ErrPivnetOther).ResponseCode).To(Equal(http.StatusUnprocessableEntity))
+		})
+
+		It("rolls back the created release when the upload failed", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusCreated, pivnet.CreateReleaseResponse{
+					Release: pivnet.Release{ID: 1, Version: "6.6.0"},
+				}),
+				ghttp.RespondWithJSONEncoded(http.StatusForbidden, map[string]interface{}{
+					"message": "forbidden",
+				}),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", "/api/v2/products/fakeslug/releases/1"),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+			)
+
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+			}, gbytes.NewBuffer())
+			Expect(err).To(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(6))
+		})
+
+		It("keeps the created release when rollback is disabled", func() {
+			server.AppendHandlers(
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				ghttp.RespondWithJSONEncoded(http.StatusCreated, pivnet.CreateReleaseResponse{
+					Release: pivnet.Release{ID: 1, Version: "6.6.0"},
+				}),
+				ghttp.RespondWithJSONEncoded(http.StatusForbidden, map[string]interface{}{
+					"message": "forbidden",
+				}),
+			)
+
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+				NoRollback:       true,
+			}, gbytes.NewBuffer())
+			Expect(err).To(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(5))
+		})
+
+		It("metadata file does not exist", func() {
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: filepath.Join(tmpDir, "missing.yml"),
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+			}, gbytes.NewBuffer())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("can not open metadata file"))
+			Expect(ExitCode(err)).To(Equal(ExitCodeStorage))
+			Expect(server.ReceivedRequests()).To(BeEmpty())
+		})
+	})
+
+	Context("RunUpload with dry run", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/releases"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ReleasesResponse{}),
+				),
+			)
+		})
+
+		It("prints the plan as text and creates nothing", func() {
+			out := gbytes.NewBuffer()
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+				DryRun:           true,
+			}, out)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(gbytes.Say(`version:\s+6.6.0`))
+			Expect(out).To(gbytes.Say(`release type:\s+Major Release`))
+			Expect(out).To(gbytes.Say(`release notes url:\s+http://example.com/notes/url`))
+			Expect(out).To(gbytes.Say(`end of support date:\s+2023-05-31`))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("prints the plan as json", func() {
+			out := gbytes.NewBuffer()
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+				DryRun:           true,
+				OutputFormat:     OutputFormatJson,
+			}, out)
+			Expect(err).NotTo(HaveOccurred())
+
+			var plan map[string]interface{}
+			Expect(json.Unmarshal(out.Contents(), &plan)).To(Succeed())
+			Expect(plan).To(HaveKey("release"))
+			Expect(plan["release"]).To(HaveKeyWithValue("version", "6.6.0"))
+			Expect(plan["release"]).To(HaveKeyWithValue("release_type", "Major Release"))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("returns error for an unknown output format", func() {
+			err := RunUpload(ctx, context, UploadOptions{
+				MetadataFilePath: metadataPath,
+				SearchPath:       tmpDir,
+				GpdbVersion:      "6.6.0",
+				DryRun:           true,
+				OutputFormat:     "xml",
+			}, gbytes.NewBuffer())
+			Expect(err).To(MatchError("not support output format: xml"))
+		})
+	})
+
+	Context("UploadSummary", func() {
+		It("prints that the rollback is skipped when the progress is saved for resume", func() {
+			buffer := gbytes.NewBuffer()
+			PrintUploadSummary(buffer, service.UploadSummary{
+				ReleaseID:    1,
+				ProductFiles: []service.ProductFileSummary{{UploadAs: "osl", Uploaded: true, ProductFileID: 2}},
+				StatePath:    "/tmp/state.json",
+				Rollback:     service.RollbackSkipped,
+			})
+
+			Expect(buffer).To(gbytes.Say("rollback is skipped because the progress is saved to /tmp/state.json for resume, " +
+				"the completed steps below are kept on pivnet:"))
+			Expect(buffer).To(gbytes.Say(`product file\s+osl\s+2\s+yes\s+no\s+kept`))
+		})
+
+		It("prints the objects which are failed to delete when the rollback failed", func() {
+			buffer := gbytes.NewBuffer()
+			PrintUploadSummary(buffer, service.UploadSummary{
+				ReleaseVersion:    "6.6.0",
+				ReleaseID:         1,
+				ReleaseRolledBack: true,
+				FileGroups:        []service.FileGroupSummary{{Name: "server", ID: 2, Attached: true}},
+				ProductFiles:      []service.ProductFileSummary{{UploadAs: "osl", Uploaded: true, ProductFileID: 3, Attached: true}},
+				Rollback:          service.RollbackFailed,
+				RollbackFailures: []service.JournalEntry{
+					{Kind: service.JournalEntryFileGroup, ID: 2, Name: "server"},
+				},
+			})
+
+			Expect(buffer).To(gbytes.Say("the rollback failed, the objects below are failed to delete:"))
+			Expect(buffer).To(gbytes.Say(`  file group server \(id=2\)`))
+			Expect(buffer).To(gbytes.Say("the completed steps below are rolled back, except the objects above and the reused objects which are kept:"))
+			Expect(buffer).To(gbytes.Say(`release\s+6.6.0\s+1\s+rolled back`))
+			Expect(buffer).To(gbytes.Say(`file group\s+server\s+2\s+yes\s+kept`))
+			Expect(buffer).To(gbytes.Say(`product file\s+osl\s+3\s+yes\s+yes\s+kept`))
+			Expect(string(buffer.Contents())).NotTo(ContainSubstring("upload is not completed, the completed steps below are rolled back"))
+		})
+	})
+
+	Context("ErrorSummary", func() {
+		It("prints the summary items and the error", func() {
+			buffer := gbytes.NewBuffer()
+			NewErrorSummary("upload", errors.New("something wrong"),
+				SummaryItem{Name: "gpdb version", Value: "6.6.0"},
+			).Print(buffer)
+
+			Expect(string(buffer.Contents())).To(Equal(
+				"upload failed:\n" +
+					"  gpdb version: 6.6.0\n" +
+					"  error:        something wrong\n"))
+		})
+
+		It("prints the details of remote errors", func() {
+			buffer := gbytes.NewBuffer()
+			NewErrorSummary("upload", pivnet.ErrPivnetOther{
+				ResponseCode: 422,
+				Message:      "invalid",
+				Errors:       []string{"a", "b"},
+			}).Print(buffer)
+
+			Expect(buffer).To(gbytes.Say(`response code: 422`))
+			Expect(buffer).To(gbytes.Say(`remote errors: a, b`))
+		})
+	})
+})
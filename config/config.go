@@ -1,12 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	. "github.com/baotingfang/go-pivnet-client/utils"
 	"github.com/baotingfang/go-pivnet-client/vlog"
 	semver "github.com/cppforlife/go-semi-semantic/version"
 	"github.com/pivotal-cf/go-pivnet/v4"
-	"gopkg.in/yaml.v2"
+	"gopkg.in/yaml.v3"
 	"io"
 	"strconv"
 	"strings"
@@ -21,8 +22,29 @@ const (
 	MajorReleaseType       = "Major Release"
 	MinorReleaseType       = "Minor Release"
 	MaintenanceReleaseType = "Maintenance Release"
+
+	AvailabilityAdminsOnly         = "Admins Only"
+	AvailabilitySelectedUserGroups = "Selected User Groups Only"
+	AvailabilityAllUsers           = "All Users"
+
+	// OffsetPattern is the form of the offsets of the dates, like +1y+3m+10d
+	OffsetPattern = `(\+\d+[mdyMDY])+`
 )
 
+var ReleaseTypes = []string{
+	AlphaReleaseType,
+	BetaReleaseType,
+	MajorReleaseType,
+	MinorReleaseType,
+	MaintenanceReleaseType,
+}
+
+var Availabilities = []string{
+	AvailabilityAdminsOnly,
+	AvailabilitySelectedUserGroups,
+	AvailabilityAllUsers,
+}
+
 type Version struct {
 	version semver.Version
 }
@@ -31,29 +53,32 @@ func NewVersion(v semver.Version) Version {
 	return Version{version: v}
 }
 
-func (v Version) MajorVersion() int {
+func (v Version) MajorVersion() (int, error) {
 	return v.versionAt(0)
 }
 
-func (v Version) MinorVersion() int {
+func (v Version) MinorVersion() (int, error) {
 	return v.versionAt(1)
 }
 
-func (v Version) PatchVersion() int {
+func (v Version) PatchVersion() (int, error) {
 	return v.versionAt(2)
 }
 
-func (v Version) versionAt(position int) int {
+// versionAt returns -1 when the version does not have the component at the position
+func (v Version) versionAt(position int) (int, error) {
 	ver := v.version
 	if len(ver.Release.Components) > position {
-		patchVersion, err := strconv.Atoi(ver.Release.Components[position].AsString())
+		component, err := strconv.Atoi(ver.Release.Components[position].AsString())
 		if err != nil {
-			vlog.Fatal(err.Error())
-			return -1
+			return -1, ValidationError{
+				Message: fmt.Sprintf("invalid version component at %d: %s", position, ver.AsString()),
+				Err:     err,
+			}
 		}
-		return patchVersion
+		return component, nil
 	}
-	return -1
+	return -1, nil
 }
 
 type Release struct {
@@ -62,28 +87,40 @@ type Release struct {
 	EndOfAvailabilityDateOffset string `json:"end_of_availability_date_offset,omitempty" yaml:"end_of_availability_date_offset,omitempty"`
 }
 
-func (r Release) GpdbVersion() semver.Version {
+func (r Release) GpdbVersion() (semver.Version, error) {
 	if !Empty(r.Version) {
 		v, err := semver.NewVersionFromString(r.Version)
 		if err != nil {
-			vlog.Fatal("invalid gpdb version: %s", r.Version)
+			return semver.Version{}, NewValidationError("invalid gpdb version: %s", r.Version)
 		}
-		return v
+		return v, nil
 	}
 	vlog.Info("gpdb version is empty")
-	return semver.Version{}
+	return semver.Version{}, nil
 }
 
-func (r Release) GpdbMajorVersion() int {
-	return NewVersion(r.GpdbVersion()).MajorVersion()
+func (r Release) GpdbMajorVersion() (int, error) {
+	v, err := r.GpdbVersion()
+	if err != nil {
+		return -1, err
+	}
+	return NewVersion(v).MajorVersion()
 }
 
-func (r Release) GpdbMinorVersion() int {
-	return NewVersion(r.GpdbVersion()).MinorVersion()
+func (r Release) GpdbMinorVersion() (int, error) {
+	v, err := r.GpdbVersion()
+	if err != nil {
+		return -1, err
+	}
+	return NewVersion(v).MinorVersion()
 }
 
-func (r Release) GpdbPatchVersion() int {
-	return NewVersion(r.GpdbVersion()).PatchVersion()
+func (r Release) GpdbPatchVersion() (int, error) {
+	v, err := r.GpdbVersion()
+	if err != nil {
+		return -1, err
+	}
+	return NewVersion(v).PatchVersion()
 }
 
 func (r Release) Empty() bool {
@@ -95,7 +132,10 @@ func (r *Release) ComputeReleaseType() (pivnet.ReleaseType, error) {
 		return r.ReleaseType, nil
 	}
 
-	version := r.GpdbVersion()
+	version, err := r.GpdbVersion()
+	if err != nil {
+		return "", err
+	}
 
 	if version.PreRelease.Components != nil {
 		for _, p := range version.PreRelease.Components {
@@ -110,10 +150,15 @@ func (r *Release) ComputeReleaseType() (pivnet.ReleaseType, error) {
 		}
 	}
 
+	majorVersion, err := NewVersion(version).MajorVersion()
+	if err != nil {
+		return "", err
+	}
+
 	// gpdb4
-	if r.GpdbMajorVersion() == 4 {
+	if majorVersion == 4 {
 		if len(version.Release.Components) != 4 {
-			return "", fmt.Errorf("invalid release version for gpdb4: %s", r.Version)
+			return "", NewValidationError("invalid release version for gpdb4: %s", r.Version)
 		}
 
 		// 4.3.33.0, not Patch Version
@@ -127,27 +172,36 @@ func (r *Release) ComputeReleaseType() (pivnet.ReleaseType, error) {
 	}
 
 	// gpdb5 gpdb6
-	if r.GpdbMajorVersion() == 5 || r.GpdbMajorVersion() == 6 {
+	if majorVersion == 5 || majorVersion == 6 {
 		if len(version.Release.Components) != 3 {
-			return "", fmt.Errorf("invalid release version for gpdb%d: %s", r.GpdbMajorVersion(), r.Version)
+			return "", NewValidationError("invalid release version for gpdb%d: %s", majorVersion, r.Version)
 		}
 
-		if r.GpdbMinorVersion() == 0 && r.GpdbPatchVersion() == 0 {
+		minorVersion, err := NewVersion(version).MinorVersion()
+		if err != nil {
+			return "", err
+		}
+		patchVersion, err := NewVersion(version).PatchVersion()
+		if err != nil {
+			return "", err
+		}
+
+		if minorVersion == 0 && patchVersion == 0 {
 			r.ReleaseType = MajorReleaseType
 			return r.ReleaseType, nil
 		}
 
-		if r.GpdbMinorVersion() != 0 && r.GpdbPatchVersion() == 0 {
+		if minorVersion != 0 && patchVersion == 0 {
 			r.ReleaseType = MinorReleaseType
 			return r.ReleaseType, nil
 		}
 
-		if r.GpdbPatchVersion() != 0 {
+		if patchVersion != 0 {
 			r.ReleaseType = MaintenanceReleaseType
 			return r.ReleaseType, nil
 		}
 	}
-	return "", fmt.Errorf("invalid gpdb release version: %s", r.Version)
+	return "", NewValidationError("invalid gpdb release version: %s", r.Version)
 }
 
 func (r *Release) ComputeEndOfSupportDate(previousMajorRelease, previousMinorRelease pivnet.Release) (Date, error) {
@@ -157,44 +211,53 @@ func (r *Release) ComputeEndOfSupportDate(previousMajorRelease, previousMinorRel
 	)
 
 	if !Empty(r.EndOfSupportDate) && r.EndOfSupportDate != COMPUTED {
-		return MustParseDateFrom(r.EndOfSupportDate), nil
+		return ParseDateFrom(r.EndOfSupportDate)
 	}
 
 	if !Empty(previousMajorRelease) &&
 		previousMajorRelease.ReleaseType != MajorReleaseType {
 		return Date{},
-			fmt.Errorf("previous major release type is wrong. actual release type:%s", previousMajorRelease.ReleaseType)
+			NewValidationError("previous major release type is wrong. actual release type:%s", previousMajorRelease.ReleaseType)
 	}
 
 	if !Empty(previousMinorRelease) &&
 		previousMinorRelease.ReleaseType != MinorReleaseType {
 		return Date{},
-			fmt.Errorf("previous minor release type is wrong. actual release type:%s", previousMinorRelease.ReleaseType)
+			NewValidationError("previous minor release type is wrong. actual release type:%s", previousMinorRelease.ReleaseType)
 	}
 
 	initReleaseDate := Date{Time: time.Now()}
-	releaseDate := MustParseDateFrom(r.ReleaseDate)
-	if !releaseDate.IsZero() {
+	if !Empty(r.ReleaseDate) && r.ReleaseDate != COMPUTED {
+		releaseDate, err := ParseDateFrom(r.ReleaseDate)
+		if err != nil {
+			return Date{}, err
+		}
 		initReleaseDate = releaseDate
 	}
 
 	if r.ReleaseType == MaintenanceReleaseType {
 		if Empty(previousMinorRelease) {
 			return Date{},
-				fmt.Errorf("current release type: %s, Can not find the previous minor release", r.ReleaseType)
+				NewNotFoundError("current release type: %s, Can not find the previous minor release", r.ReleaseType)
+		}
+		previousMinorReleaseDate, err := ParseDateFrom(previousMinorRelease.ReleaseDate)
+		if err != nil {
+			return Date{}, err
 		}
-		previousMinorReleaseDate := MustParseDateFrom(previousMinorRelease.ReleaseDate)
 		r.EndOfSupportDate = previousMinorReleaseDate.LastDayOfCurrentMonth().String()
-		return MustParseDateFrom(r.EndOfSupportDate), nil
+		return ParseDateFrom(r.EndOfSupportDate)
 	}
 
 	if r.ReleaseType == MinorReleaseType {
 		if Empty(previousMajorRelease) {
 			return Date{},
-				fmt.Errorf("current release type: %s, Can not find the previous major release", r.ReleaseType)
+				NewNotFoundError("current release type: %s, Can not find the previous major release", r.ReleaseType)
 		}
 
-		previousMajorReleaseDate := MustParseDateFrom(previousMajorRelease.ReleaseDate)
+		previousMajorReleaseDate, err := ParseDateFrom(previousMajorRelease.ReleaseDate)
+		if err != nil {
+			return Date{}, err
+		}
 		t1 := Date{
 			Time: previousMajorReleaseDate.AddDate(0, OffsetFromMajorReleaseMonths, 0),
 		}
@@ -204,10 +267,10 @@ func (r *Release) ComputeEndOfSupportDate(previousMajorRelease, previousMinorRel
 
 		if t1.Time.Before(t2.Time) {
 			r.EndOfSupportDate = t2.LastDayOfCurrentMonth().String()
-			return MustParseDateFrom(r.EndOfSupportDate), nil
+			return ParseDateFrom(r.EndOfSupportDate)
 		} else {
 			r.EndOfSupportDate = t1.LastDayOfCurrentMonth().String()
-			return MustParseDateFrom(r.EndOfSupportDate), nil
+			return ParseDateFrom(r.EndOfSupportDate)
 		}
 	}
 
@@ -218,21 +281,25 @@ func (r *Release) ComputeEndOfSupportDate(previousMajorRelease, previousMinorRel
 			Time: initReleaseDate.AddDate(0, OffsetFromMajorReleaseMonths, 0),
 		}
 		r.EndOfSupportDate = t1.LastDayOfCurrentMonth().String()
-		return MustParseDateFrom(r.EndOfSupportDate), nil
+		return ParseDateFrom(r.EndOfSupportDate)
 	}
 
-	return Date{}, fmt.Errorf("invalid release type: %s", r.ReleaseType)
+	return Date{}, NewValidationError("invalid release type: %s", r.ReleaseType)
 }
 
 func (r *Release) ComputeEndOfGuidanceDate(previousMajorRelease, previousMinorRelease pivnet.Release) (Date, error) {
 
 	if !Empty(r.EndOfGuidanceDate) && r.EndOfGuidanceDate != COMPUTED {
-		return MustParseDateFrom(r.EndOfGuidanceDate), nil
+		return ParseDateFrom(r.EndOfGuidanceDate)
 	}
 
 	var endOfSupportDate Date
 	if !Empty(r.EndOfSupportDate) && r.EndOfSupportDate != COMPUTED {
-		endOfSupportDate = MustParseDateFrom(r.EndOfSupportDate)
+		date, err := ParseDateFrom(r.EndOfSupportDate)
+		if err != nil {
+			return Date{}, err
+		}
+		endOfSupportDate = date
 	}
 
 	if endOfSupportDate.IsZero() {
@@ -250,22 +317,26 @@ func (r *Release) ComputeEndOfGuidanceDate(previousMajorRelease, previousMinorRe
 		Time: endOfSupportDate.AddDate(0, OffsetFromEndOfSupportDate, 0),
 	}.String()
 
-	return MustParseDateFrom(r.EndOfGuidanceDate), nil
+	return ParseDateFrom(r.EndOfGuidanceDate)
 }
 
-func (r *Release) ComputeEndOfAvailabilityDate() Date {
+func (r *Release) ComputeEndOfAvailabilityDate() (Date, error) {
 	if !Empty(r.EndOfAvailabilityDate) && r.EndOfAvailabilityDate != COMPUTED {
-		return MustParseDateFrom(r.EndOfAvailabilityDate)
+		return ParseDateFrom(r.EndOfAvailabilityDate)
 	}
 
 	initReleaseDate := Date{Time: time.Now()}
 	if !Empty(r.ReleaseDate) && r.ReleaseDate != COMPUTED {
-		initReleaseDate = MustParseDateFrom(r.ReleaseDate)
+		releaseDate, err := ParseDateFrom(r.ReleaseDate)
+		if err != nil {
+			return Date{}, err
+		}
+		initReleaseDate = releaseDate
 	}
 
 	endOfAvailabilityDate := initReleaseDate.Offset(r.EndOfAvailabilityDateOffset)
 	r.EndOfAvailabilityDate = endOfAvailabilityDate.String()
-	return endOfAvailabilityDate
+	return endOfAvailabilityDate, nil
 }
 
 func (r *Release) ComputeReleaseNotesUrl() (string, error) {
@@ -273,19 +344,28 @@ func (r *Release) ComputeReleaseNotesUrl() (string, error) {
 		return r.ReleaseNotesURL, nil
 	}
 
-	if r.GpdbMajorVersion() == 6 {
-		return generateGpdb6ReleaseNotesUrl(r.GpdbVersion()), nil
+	version, err := r.GpdbVersion()
+	if err != nil {
+		return "", err
+	}
+	majorVersion, err := NewVersion(version).MajorVersion()
+	if err != nil {
+		return "", err
+	}
+
+	if majorVersion == 6 {
+		return generateGpdb6ReleaseNotesUrl(version), nil
 	}
 
-	if r.GpdbMajorVersion() == 5 {
-		return generateGpdb5ReleaseNotesUrl(r.GpdbVersion()), nil
+	if majorVersion == 5 {
+		return generateGpdb5ReleaseNotesUrl(version), nil
 	}
 
-	if r.GpdbMajorVersion() == 4 {
-		return generateGpdb4ReleaseNotesUrl(r.GpdbVersion()), nil
+	if majorVersion == 4 {
+		return generateGpdb4ReleaseNotesUrl(version), nil
 	}
 
-	return "", fmt.Errorf("compute release notes url failed. only support gpdb4/5/6")
+	return "", NewValidationError("compute release notes url failed. only support gpdb4/5/6")
 
 }
 
@@ -372,23 +452,90 @@ type ProductFile struct {
 }
 
 type Metadata struct {
+	// Include are the metadata files which are merged before this metadata, the
+	// paths are relative to the including file, and ${GPDB_MAJOR_VERSION} in the
+	// paths is replaced with the major version of gpdb.
+	Include []string `json:"include,omitempty" yaml:"include,omitempty"`
+	// AwsObjectKey is the default aws object key template of the product files
+	AwsObjectKey string        `json:"aws_object_key,omitempty" yaml:"aws_object_key,omitempty"`
 	Release      Release       `json:"release,omitempty" yaml:"release,omitempty"`
 	FileGroups   []FileGroup   `json:"file_groups,omitempty" yaml:"file_groups,omitempty"`
-	ProductFiles []ProductFile `json:"product_file,omitempty" yaml:"product_files,omitempty"`
+	ProductFiles []ProductFile `json:"product_files,omitempty" yaml:"product_files,omitempty"`
+
+	// positions are the positions of the fields in the metadata file by their paths
+	positions map[string]Position
 }
 
+// MetadataFrom reads the metadata, the format of which is detected from the content
 func MetadataFrom(reader io.Reader, gpdbVersion string) (Metadata, error) {
-	_, err := semver.NewVersionFromString(gpdbVersion)
+	return MetadataFromFormat(reader, MetadataFormatAuto, gpdbVersion)
+}
+
+// MetadataFromFormat reads the metadata in yaml, json or toml format, the metadata
+// has the same fields and is validated in the same way in all the formats.
+func MetadataFromFormat(reader io.Reader, format MetadataFormat, gpdbVersion string) (Metadata, error) {
+	version, err := semver.NewVersionFromString(gpdbVersion)
+	if err != nil {
+		return Metadata{}, ValidationError{Err: err}
+	}
+
+	loader, err := newMetadataLoader(version)
 	if err != nil {
 		return Metadata{}, err
 	}
+	metadata, err := decodeMetadata(reader, format, loader)
+	if err != nil {
+		return Metadata{}, metadataError(err)
+	}
+	metadata.Release.Version = gpdbVersion
+	vlog.Info("GPDB Version: %s", version.String())
 
-	var metadata Metadata
-	if err := yaml.NewDecoder(reader).Decode(&metadata); err != nil {
+	return metadata, nil
+}
+
+// MetadataFromFiles reads the metadata files with their includes, the files after the
+// first one are the overlays which are merged onto the files before them in order.
+// The mappings are merged by keys, the file groups are merged by their names and
+// the product files are merged by their upload_as, the other values in the overlays
+// replace the ones in the files before them.
+func MetadataFromFiles(gpdbVersion string, paths ...string) (Metadata, error) {
+	version, err := semver.NewVersionFromString(gpdbVersion)
+	if err != nil {
+		return Metadata{}, ValidationError{Err: err}
+	}
+	if len(paths) == 0 {
+		return Metadata{}, NewValidationError("metadata file is not specified")
+	}
+
+	loader, err := newMetadataLoader(version)
+	if err != nil {
 		return Metadata{}, err
 	}
+	var root *yaml.Node
+	for _, path := range paths {
+		node, err := loader.loadFile(path)
+		if err != nil {
+			return Metadata{}, metadataError(err)
+		}
+		root = loader.merge(root, node, "")
+	}
+
+	metadata, err := decodeNode(root, loader)
+	if err != nil {
+		return Metadata{}, metadataError(err)
+	}
 	metadata.Release.Version = gpdbVersion
-	vlog.Info("GPDB Version: %s", metadata.Release.GpdbVersion().String())
+	vlog.Info("GPDB Version: %s", version.String())
 
 	return metadata, nil
 }
+
+// metadataError wraps the errors of the metadata content as validation errors,
+// the errors of reading the files are kept.
+func metadataError(err error) error {
+	var storageErr StorageError
+	if errors.As(err, &storageErr) {
+		return err
+	}
+	return ValidationError{Err: err}
+}
@@ -3,6 +3,7 @@ package config_test
 import (
 	"fmt"
 	"github.com/baotingfang/go-pivnet-client/config"
+	"github.com/baotingfang/go-pivnet-client/utils"
 	"github.com/baotingfang/go-pivnet-client/vlog"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -95,9 +96,65 @@ var _ = Describe("Config", func() {
 			metaData, err := config.MetadataFrom(metadataReader, "6.7.0")
 			Expect(err).To(HaveOccurred())
 			fmt.Println(err.Error())
-			Expect(err.Error()).To(HavePrefix("yaml: unmarshal errors:"))
+			Expect(err.Error()).To(Equal("line 1, column 1: must be a mapping"))
 			Expect(metaData).To(Equal(config.Metadata{}))
 		})
+
+		It("metadata yaml has unknown fields", func() {
+			metadataReader := strings.NewReader(`
+release:
+  relase_date: 2020-05-19
+file_groups:
+- name: server
+  product_files:
+  - file: file://server.rpm
+    upload_ass: server
+`)
+			_, err := config.MetadataFrom(metadataReader, "6.7.0")
+			Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
+			Expect(err.Error()).To(Equal(
+				"release.relase_date (line 3, column 3): field relase_date is not supported\n" +
+					"file_groups[0].product_files[0].upload_ass (line 8, column 5): field upload_ass is not supported"))
+		})
+
+		It("metadata yaml has values of wrong types", func() {
+			metadataReader := strings.NewReader(`
+release:
+  controlled: maybe
+product_files:
+  file: file://server.rpm
+`)
+			_, err := config.MetadataFrom(metadataReader, "6.7.0")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal(
+				"release.controlled (line 3, column 15): \"maybe\" is not a valid bool value\n" +
+					"product_files (line 5, column 3): must be a sequence"))
+		})
+
+		It("metadata yaml has syntax error", func() {
+			metadataReader := strings.NewReader("release:\n\tdescription: tab\n")
+			_, err := config.MetadataFrom(metadataReader, "6.7.0")
+			Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
+			Expect(err).To(MatchError("yaml: line 2: found character that cannot start any token"))
+		})
+
+		It("metadata yaml is empty", func() {
+			_, err := config.MetadataFrom(strings.NewReader(""), "6.7.0")
+			Expect(err).To(MatchError("metadata is empty"))
+		})
+
+		It("Position of the fields", func() {
+			metaData, err := config.MetadataFrom(strings.NewReader(metadataYaml), "6.6.0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(metaData.Position("release.release_date")).To(Equal(config.Position{Line: 12, Column: 17}))
+			Expect(metaData.Position("file_groups[0].product_files[1].file_type")).
+				To(Equal(config.Position{Line: 30, Column: 16}))
+
+			By("the position of the closest parent for the field which is not in the file")
+			Expect(metaData.Position("file_groups[0].product_files[1].sha256")).
+				To(Equal(config.Position{Line: 27, Column: 5}))
+			Expect(config.Metadata{}.Position("release")).To(Equal(config.Position{}))
+		})
 	})
 
 	Context("Test major/minor/patch versions", func() {
@@ -120,39 +177,39 @@ var _ = Describe("Config", func() {
 			r := &config.Release{}
 
 			r.ReleaseType = config.COMPUTED
-			majorVersion := r.GpdbMajorVersion()
+			majorVersion, err := r.GpdbMajorVersion()
+			Expect(err).NotTo(HaveOccurred())
 			Expect(majorVersion).To(Equal(-1))
 			Expect(string(outLog.Contents())).To(ContainSubstring(
 				"gpdb version is empty"))
 		})
 
-		It("Test Major version with panic", func() {
+		It("Test Major version with invalid component", func() {
 			r := &config.Release{}
 
 			r.ReleaseType = config.COMPUTED
 			r.Version = "beta-1.2.3.build.1"
-			f := func() {
-				r.GpdbMajorVersion()
-			}
-			Expect(f).To(PanicWith(MatchRegexp(`strconv.Atoi: parsing "beta": invalid syntax`)))
+			_, err := r.GpdbMajorVersion()
+			Expect(err).To(MatchError(MatchRegexp(`strconv.Atoi: parsing "beta": invalid syntax`)))
+			Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
 		})
 
-		It("Test Major version with panic. invalid version", func() {
+		It("Test Major version with invalid version", func() {
 			r := &config.Release{}
 
 			r.ReleaseType = config.COMPUTED
 			r.Version = "invalid version"
-			f := func() {
-				r.GpdbMajorVersion()
-			}
-			Expect(f).To(PanicWith(MatchRegexp(`invalid gpdb version: invalid version`)))
+			_, err := r.GpdbMajorVersion()
+			Expect(err).To(MatchError("invalid gpdb version: invalid version"))
+			Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
 		})
 
 		It("Test Minor version", func() {
 			r := &config.Release{}
 
 			r.ReleaseType = config.COMPUTED
-			minorVersion := r.GpdbMinorVersion()
+			minorVersion, err := r.GpdbMinorVersion()
+			Expect(err).NotTo(HaveOccurred())
 			Expect(minorVersion).To(Equal(-1))
 			Expect(string(outLog.Contents())).To(ContainSubstring("gpdb version is empty"))
 		})
@@ -161,7 +218,8 @@ var _ = Describe("Config", func() {
 			r := &config.Release{}
 			r.ReleaseType = config.COMPUTED
 
-			patchVersion := r.GpdbPatchVersion()
+			patchVersion, err := r.GpdbPatchVersion()
+			Expect(err).NotTo(HaveOccurred())
 			Expect(patchVersion).To(Equal(-1))
 			Expect(string(outLog.Contents())).To(ContainSubstring("gpdb version is empty"))
 		})
@@ -675,7 +733,8 @@ var _ = Describe("Config", func() {
 					EndOfAvailabilityDate: "2012-12-10",
 				},
 			}
-			d := release.ComputeEndOfAvailabilityDate()
+			d, err := release.ComputeEndOfAvailabilityDate()
+			Expect(err).NotTo(HaveOccurred())
 			Expect(d.String()).To(Equal("2012-12-10"))
 		})
 
@@ -685,7 +744,8 @@ var _ = Describe("Config", func() {
 					ReleaseDate: "2008-08-18",
 				},
 			}
-			d := release.ComputeEndOfAvailabilityDate()
+			d, err := release.ComputeEndOfAvailabilityDate()
+			Expect(err).NotTo(HaveOccurred())
 			Expect(d.String()).To(Equal("2008-08-18"))
 		})
 
@@ -696,9 +756,20 @@ var _ = Describe("Config", func() {
 				},
 				EndOfAvailabilityDateOffset: "+1y+3m+10d",
 			}
-			d := release.ComputeEndOfAvailabilityDate()
+			d, err := release.ComputeEndOfAvailabilityDate()
+			Expect(err).NotTo(HaveOccurred())
 			Expect(d.String()).To(Equal("2009-11-28"))
 		})
+
+		It("ComputeEndOfAvailabilityDate: user provide invalid release date", func() {
+			release := config.Release{
+				Release: pivnet.Release{
+					ReleaseDate: "2008/08/18",
+				},
+			}
+			_, err := release.ComputeEndOfAvailabilityDate()
+			Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
+		})
 	})
 
 	Context("Test ComputeReleaseNotesUrl", func() {
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+)
+
+// Position is the line and column of a value in the metadata file, the file is
+// set when the metadata is loaded from the files with includes and overlays.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) IsZero() bool {
+	return p.File == "" && p.Line == 0 && p.Column == 0
+}
+
+func (p Position) String() string {
+	if p.Line == 0 && p.File != "" {
+		return p.File
+	}
+	position := fmt.Sprintf("line %d", p.Line)
+	if p.Column != 0 {
+		position = fmt.Sprintf("%s, column %d", position, p.Column)
+	}
+	if p.File != "" {
+		return p.File + ", " + position
+	}
+	return position
+}
+
+// FieldError is an error of a field in the metadata file, the path of the
+// field is like file_groups[0].product_files[1].file_type
+type FieldError struct {
+	Path     string
+	Position Position
+	Message  string
+}
+
+func (e FieldError) Error() string {
+	location := e.Path
+	if !e.Position.IsZero() {
+		if location == "" {
+			location = e.Position.String()
+		} else {
+			location = fmt.Sprintf("%s (%s)", location, e.Position)
+		}
+	}
+	if location == "" {
+		return e.Message
+	}
+	return location + ": " + e.Message
+}
+
+// FieldErrors are all the errors found in the metadata file
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Position returns the position of the field in the metadata file. When the field
+// is not in the file, the position of its closest parent is returned.
+func (m Metadata) Position(path string) Position {
+	for path != "" {
+		if p, ok := m.positions[path]; ok {
+			return p
+		}
+		path = parentPath(path)
+	}
+	return Position{}
+}
+
+func parentPath(path string) string {
+	i := strings.LastIndexAny(path, ".[")
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+// decodeMetadata decodes the metadata in the format strictly, the keys which are not the fields
+// of metadata and the values which can not be decoded to their fields are all
+// reported with their paths and positions, instead of being ignored.
+func decodeMetadata(reader io.Reader, format MetadataFormat, loader *metadataLoader) (Metadata, error) {
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	root, err := loader.load(content, format, "")
+	if err != nil {
+		return Metadata{}, err
+	}
+	return decodeNode(root, loader)
+}
+
+// decodeNode decodes the node loaded by the loader, the positions are in the
+// files where the values are from.
+func decodeNode(root *yaml.Node, loader *metadataLoader) (Metadata, error) {
+	d := &metadataDecoder{positions: make(map[string]Position), files: loader.files}
+	d.check(root, reflect.TypeOf(Metadata{}), "")
+	if len(d.errs) > 0 {
+		return Metadata{}, d.errs
+	}
+
+	var metadata Metadata
+	if err := root.Decode(&metadata); err != nil {
+		return Metadata{}, FieldErrors{{Message: err.Error()}}
+	}
+	metadata.positions = d.positions
+	return metadata, nil
+}
+
+type metadataDecoder struct {
+	positions map[string]Position
+	files     map[*yaml.Node]string
+	errs      FieldErrors
+}
+
+func (d *metadataDecoder) position(node *yaml.Node) Position {
+	return Position{File: d.files[node], Line: node.Line, Column: node.Column}
+}
+
+func (d *metadataDecoder) fail(node *yaml.Node, path string, format string, args ...interface{}) {
+	d.errs = append(d.errs, FieldError{
+		Path:     path,
+		Position: d.position(node),
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// check walks the node along the type, and records the position of every field
+func (d *metadataDecoder) check(node *yaml.Node, t reflect.Type, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if position := d.position(node); path != "" && (position.Line != 0 || position.File != "") {
+		d.positions[path] = position
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			d.fail(node, path, "must be a mapping")
+			return
+		}
+		fields := yamlFields(t)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			fieldPath := key.Value
+			if path != "" {
+				fieldPath = path + "." + key.Value
+			}
+			fieldType, ok := fields[key.Value]
+			if !ok {
+				d.fail(key, fieldPath, "field %s is not supported", key.Value)
+				continue
+			}
+			d.check(value, fieldType, fieldPath)
+		}
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			d.fail(node, path, "must be a sequence")
+			return
+		}
+		for i, item := range node.Content {
+			d.check(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map, reflect.Interface:
+		return
+	default:
+		if node.Kind != yaml.ScalarNode {
+			d.fail(node, path, "must be a %s value", t.Kind())
+			return
+		}
+		if err := node.Decode(reflect.New(t).Interface()); err != nil {
+			d.fail(node, path, "%q is not a valid %s value", node.Value, t.Kind())
+		}
+	}
+}
+
+// yamlFields returns the types of the fields of the struct by their yaml keys,
+// the fields of the inline structs are included.
+func yamlFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		tag := field.Tag.Get("yaml")
+		if tag == "-" {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		name := options[0]
+		inline := false
+		for _, option := range options[1:] {
+			if option == "inline" {
+				inline = true
+			}
+		}
+
+		if inline {
+			for key, fieldType := range yamlFields(field.Type) {
+				fields[key] = fieldType
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MetadataFormat is the format of the metadata file
+type MetadataFormat string
+
+const (
+	// MetadataFormatAuto detects the format from the content of the metadata file
+	MetadataFormatAuto MetadataFormat = ""
+	MetadataFormatYAML MetadataFormat = "yaml"
+	MetadataFormatJSON MetadataFormat = "json"
+	MetadataFormatTOML MetadataFormat = "toml"
+)
+
+// MetadataFormatOf returns the format of the metadata file by its extension,
+// MetadataFormatAuto is returned when the extension is unknown.
+func MetadataFormatOf(path string) MetadataFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yml", ".yaml":
+		return MetadataFormatYAML
+	case ".json":
+		return MetadataFormatJSON
+	case ".toml":
+		return MetadataFormatTOML
+	default:
+		return MetadataFormatAuto
+	}
+}
+
+var tomlLineRegexp = regexp.MustCompile(`^(\[\[?[^\]]+\]\]?|[A-Za-z0-9_."'-]+\s*=)`)
+
+// detectMetadataFormat detects the format from the first line which is not a comment,
+// the metadata is always a mapping, so that json starts with "{", and toml starts
+// with a table header or a key/value pair.
+func detectMetadataFormat(content []byte) MetadataFormat {
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "{") {
+			return MetadataFormatJSON
+		}
+		if tomlLineRegexp.MatchString(line) {
+			return MetadataFormatTOML
+		}
+		return MetadataFormatYAML
+	}
+	return MetadataFormatYAML
+}
+
+// parseMetadata parses the content in the format to a yaml node, so that the
+// metadata in all formats is checked and decoded in the same way.
+func parseMetadata(content []byte, format MetadataFormat) (*yaml.Node, error) {
+	if format == MetadataFormatAuto {
+		format = detectMetadataFormat(content)
+	}
+
+	switch format {
+	case MetadataFormatYAML:
+		return parseYAML(content)
+	case MetadataFormatJSON:
+		return parseJSON(content)
+	case MetadataFormatTOML:
+		return parseTOML(content)
+	default:
+		return nil, FieldErrors{{Message: fmt.Sprintf("not support metadata format: %s", format)}}
+	}
+}
+
+func parseYAML(content []byte) (*yaml.Node, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, FieldErrors{{Message: err.Error()}}
+	}
+	if len(root.Content) == 0 {
+		return nil, FieldErrors{{Message: "metadata is empty"}}
+	}
+	return root.Content[0], nil
+}
+
+// parseJSON parses json as yaml, which is a superset of json, after the json is
+// validated. The tabs can only be whitespaces in valid json, they are replaced
+// by spaces, since yaml does not allow tabs for indentation.
+func parseJSON(content []byte) (*yaml.Node, error) {
+	if len(bytes.TrimSpace(content)) == 0 {
+		return nil, FieldErrors{{Message: "metadata is empty"}}
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(content, &value); err != nil {
+		if syntaxErr, ok := err.(*json.SyntaxError); ok {
+			return nil, FieldErrors{{
+				// the offset is after the invalid character
+				Position: positionAt(content, syntaxErr.Offset-1),
+				Message:  "json: " + syntaxErr.Error(),
+			}}
+		}
+		return nil, FieldErrors{{Message: "json: " + err.Error()}}
+	}
+
+	return parseYAML(bytes.ReplaceAll(content, []byte("\t"), []byte(" ")))
+}
+
+// positionAt returns the position of the character at the byte offset in the content
+func positionAt(content []byte, offset int64) Position {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return Position{Line: line, Column: column}
+}
+
+// parseTOML decodes toml to the values, and encodes the values to a yaml node.
+// The node does not have the positions of the values, since the toml decoder
+// does not expose them, so the field errors of toml only have the paths. The
+// syntax errors only have the lines, since the decoder does not report the columns.
+func parseTOML(content []byte) (*yaml.Node, error) {
+	var value map[string]interface{}
+	if _, err := toml.Decode(string(content), &value); err != nil {
+		if parseErr, ok := err.(toml.ParseError); ok {
+			return nil, FieldErrors{{
+				Position: Position{Line: parseErr.Line},
+				Message:  "toml: " + parseErr.Message,
+			}}
+		}
+		return nil, FieldErrors{{Message: err.Error()}}
+	}
+	if len(value) == 0 {
+		return nil, FieldErrors{{Message: "metadata is empty"}}
+	}
+
+	var node yaml.Node
+	if err := node.Encode(tomlValue(value)); err != nil {
+		return nil, FieldErrors{{Message: err.Error()}}
+	}
+	return &node, nil
+}
+
+// tomlValue converts the dates and times of toml to the strings in metadata
+func tomlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = tomlValue(item)
+		}
+		return converted
+	case []map[string]interface{}:
+		converted := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted = append(converted, tomlValue(item))
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			converted = append(converted, tomlValue(item))
+		}
+		return converted
+	case time.Time:
+		// the local dates and times are in the zones of these names in toml decoder
+		switch v.Location().String() {
+		case "date-local":
+			return v.Format("2006-01-02")
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05.999999999")
+		case "time-local":
+			return v.Format("15:04:05.999999999")
+		default:
+			return v.Format(time.RFC3339Nano)
+		}
+	default:
+		return value
+	}
+}
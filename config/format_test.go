@@ -0,0 +1,159 @@
+package config_test
+
+import (
+	"strings"
+
+	"github.com/baotingfang/go-pivnet-client/config"
+	"github.com/baotingfang/go-pivnet-client/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var metadataJson = `{
+	"release": {
+		"release_type": "Major Release",
+		"eula_slug": "pivotal_software_eula",
+		"description": "test description",
+		"controlled": false,
+		"release_date": "2013-05-19"
+	},
+	"aws_object_key": "${GPDB_VERSION}/${FILE_NAME}",
+	"file_groups": [
+		{
+			"name": "Greenplum Database Server",
+			"product_files": [
+				{
+					"file": "file://server/greenplum-db-(6\\..*)-rhel7-x86_64.rpm",
+					"upload_as": "Greenplum Database ${VERSION_REGEX} Installer for RHEL 7",
+					"file_type": "Software",
+					"file_version": "${VERSION_REGEX}",
+					"platforms": ["rhel7"]
+				}
+			]
+		}
+	]
+}
+`
+
+var metadataToml = `
+aws_object_key = "${GPDB_VERSION}/${FILE_NAME}"
+
+[release]
+release_type = "Major Release"
+eula_slug = "pivotal_software_eula"
+description = "test description"
+controlled = false
+release_date = 2013-05-19
+
+[[file_groups]]
+name = "Greenplum Database Server"
+
+[[file_groups.product_files]]
+file = 'file://server/greenplum-db-(6\..*)-rhel7-x86_64.rpm'
+upload_as = "Greenplum Database ${VERSION_REGEX} Installer for RHEL 7"
+file_type = "Software"
+file_version = "${VERSION_REGEX}"
+platforms = ["rhel7"]
+`
+
+var metadataYamlOfFormats = `
+release:
+  release_type: "Major Release"
+  eula_slug: pivotal_software_eula
+  description: "test description"
+  controlled: false
+  release_date: 2013-05-19
+aws_object_key: ${GPDB_VERSION}/${FILE_NAME}
+file_groups:
+- name: Greenplum Database Server
+  product_files:
+  - file: file://server/greenplum-db-(6\..*)-rhel7-x86_64.rpm
+    upload_as: Greenplum Database ${VERSION_REGEX} Installer for RHEL 7
+    file_type: Software
+    file_version: ${VERSION_REGEX}
+    platforms: [rhel7]
+`
+
+var _ = Describe("Metadata formats", func() {
+	expectSameMetadata := func(actual config.Metadata, expected config.Metadata) {
+		Expect(actual.AwsObjectKey).To(Equal(expected.AwsObjectKey))
+		Expect(actual.Release).To(Equal(expected.Release))
+		Expect(actual.FileGroups).To(Equal(expected.FileGroups))
+		Expect(actual.ProductFiles).To(Equal(expected.ProductFiles))
+	}
+
+	It("MetadataFormatOf: by the extension", func() {
+		Expect(config.MetadataFormatOf("metadata.yml")).To(Equal(config.MetadataFormatYAML))
+		Expect(config.MetadataFormatOf("metadata.YAML")).To(Equal(config.MetadataFormatYAML))
+		Expect(config.MetadataFormatOf("/path/to/metadata.json")).To(Equal(config.MetadataFormatJSON))
+		Expect(config.MetadataFormatOf("metadata.toml")).To(Equal(config.MetadataFormatTOML))
+		Expect(config.MetadataFormatOf("metadata")).To(Equal(config.MetadataFormatAuto))
+	})
+
+	It("decodes the same metadata in all formats", func() {
+		expected, err := config.MetadataFromFormat(strings.NewReader(metadataYamlOfFormats), config.MetadataFormatYAML, "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(expected.Release.ReleaseDate).To(Equal("2013-05-19"))
+		Expect(expected.FileGroups[0].ProductFiles[0].Platforms).To(Equal([]string{"rhel7"}))
+
+		metadata, err := config.MetadataFromFormat(strings.NewReader(metadataJson), config.MetadataFormatJSON, "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+		expectSameMetadata(metadata, expected)
+
+		metadata, err = config.MetadataFromFormat(strings.NewReader(metadataToml), config.MetadataFormatTOML, "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+		expectSameMetadata(metadata, expected)
+	})
+
+	It("detects the format from the content", func() {
+		expected, err := config.MetadataFrom(strings.NewReader(metadataYamlOfFormats), "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+
+		metadata, err := config.MetadataFrom(strings.NewReader(metadataJson), "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+		expectSameMetadata(metadata, expected)
+
+		metadata, err = config.MetadataFrom(strings.NewReader("# generated\n"+metadataToml), "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+		expectSameMetadata(metadata, expected)
+	})
+
+	It("rejects unknown fields in json with their positions", func() {
+		content := strings.Replace(metadataJson, `"file_type"`, `"file_typ"`, 1)
+		_, err := config.MetadataFromFormat(strings.NewReader(content), config.MetadataFormatJSON, "6.6.0")
+		Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
+		Expect(err).To(MatchError(
+			"file_groups[0].product_files[0].file_typ (line 17, column 6): field file_typ is not supported"))
+	})
+
+	It("reports the position of json syntax error", func() {
+		_, err := config.MetadataFromFormat(strings.NewReader("{\n  \"release\": {,\n}"), config.MetadataFormatJSON, "6.6.0")
+		Expect(err).To(MatchError("line 2, column 15: json: invalid character ',' looking for beginning of object key string"))
+	})
+
+	It("rejects unknown fields and wrong types in toml with their paths but without positions", func() {
+		content := strings.Replace(metadataToml, "file_type =", "file_typ =", 1)
+		content = strings.Replace(content, "controlled = false", "controlled = 1", 1)
+		_, err := config.MetadataFromFormat(strings.NewReader(content), config.MetadataFormatTOML, "6.6.0")
+		Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
+		Expect(err.Error()).To(ContainSubstring("file_groups[0].product_files[0].file_typ: field file_typ is not supported"))
+		Expect(err.Error()).To(ContainSubstring(`release.controlled: "1" is not a valid bool value`))
+
+		metadata, err := config.MetadataFromFormat(strings.NewReader(metadataToml), config.MetadataFormatTOML, "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(metadata.Position("release.description")).To(Equal(config.Position{}))
+	})
+
+	It("reports the line of toml syntax error", func() {
+		_, err := config.MetadataFromFormat(strings.NewReader("[release]\ndescription = \n"), config.MetadataFormatTOML, "6.6.0")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(MatchRegexp(`^line 2: toml: `))
+	})
+
+	It("rejects the empty metadata", func() {
+		for _, format := range []config.MetadataFormat{config.MetadataFormatJSON, config.MetadataFormatTOML} {
+			_, err := config.MetadataFromFormat(strings.NewReader(""), format, "6.6.0")
+			Expect(err).To(MatchError("metadata is empty"))
+		}
+	})
+})
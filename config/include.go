@@ -0,0 +1,243 @@
+package config
+
+import (
+	"fmt"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	semver "github.com/cppforlife/go-semi-semantic/version"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PlaceholderGpdbMajorVersion is replaced with the major version of gpdb in the
+// include paths, so that the metadata of a major version can be included.
+const PlaceholderGpdbMajorVersion = "${GPDB_MAJOR_VERSION}"
+
+// mergeKeys are the keys of the items of the lists which are merged item by item,
+// the other lists in an overlay replace the lists in the base.
+var mergeKeys = map[string]string{
+	"file_groups":   "name",
+	"product_files": "upload_as",
+}
+
+// metadataLoader loads the metadata files with their includes to yaml nodes,
+// and keeps the files where the nodes are from.
+type metadataLoader struct {
+	gpdbMajorVersion int
+	files            map[*yaml.Node]string
+	loading          map[string]bool
+}
+
+func newMetadataLoader(gpdbVersion semver.Version) (*metadataLoader, error) {
+	gpdbMajorVersion, err := NewVersion(gpdbVersion).MajorVersion()
+	if err != nil {
+		return nil, err
+	}
+	return &metadataLoader{
+		gpdbMajorVersion: gpdbMajorVersion,
+		files:            make(map[*yaml.Node]string),
+		loading:          make(map[string]bool),
+	}, nil
+}
+
+// loadFile loads the metadata file, the format is detected by the extension,
+// or by the content when the extension is unknown.
+func (l *metadataLoader) loadFile(path string) (*yaml.Node, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, NewStorageError(err, "can not open metadata file %s", path)
+	}
+	return l.load(content, MetadataFormatOf(path), path)
+}
+
+// load parses the content, and merges the content onto its includes in order.
+// The includes are relative to the directory of the file, or to the current
+// directory when the content is not from a file.
+func (l *metadataLoader) load(content []byte, format MetadataFormat, file string) (*yaml.Node, error) {
+	root, err := parseMetadata(content, format)
+	if err != nil {
+		if errs, ok := err.(FieldErrors); ok {
+			for i := range errs {
+				errs[i].Position.File = file
+			}
+		}
+		return nil, err
+	}
+	l.recordFile(root, file)
+
+	includes, err := l.includes(root, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(includes) == 0 {
+		return root, nil
+	}
+
+	if file != "" {
+		key := file
+		if abs, err := filepath.Abs(file); err == nil {
+			key = abs
+		}
+		l.loading[key] = true
+		defer delete(l.loading, key)
+	}
+
+	var base *yaml.Node
+	for _, include := range includes {
+		path := include.Value
+		if !filepath.IsAbs(path) && file != "" {
+			path = filepath.Join(filepath.Dir(file), path)
+		}
+		if abs, err := filepath.Abs(path); err == nil && l.loading[abs] {
+			return nil, FieldErrors{{
+				Path:     "include",
+				Position: l.position(include),
+				Message:  fmt.Sprintf("%s is included recursively", include.Value),
+			}}
+		}
+
+		node, err := l.loadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		base = l.merge(base, node, "")
+	}
+	return l.merge(base, root, ""), nil
+}
+
+// includes returns the include paths of the metadata with the placeholders replaced
+func (l *metadataLoader) includes(root *yaml.Node, file string) ([]*yaml.Node, error) {
+	node := mappingValue(root, "include")
+	if isNullNode(node) {
+		return nil, nil
+	}
+
+	if node.Kind != yaml.SequenceNode {
+		return nil, FieldErrors{{Path: "include", Position: l.position(node), Message: "must be a sequence"}}
+	}
+
+	includes := make([]*yaml.Node, 0, len(node.Content))
+	for i, item := range node.Content {
+		if item.Kind != yaml.ScalarNode || Empty(item.Value) {
+			return nil, FieldErrors{{
+				Path:     fmt.Sprintf("include[%d]", i),
+				Position: l.position(item),
+				Message:  "must be a path of metadata file",
+			}}
+		}
+		include := *item
+		include.Value = strings.ReplaceAll(item.Value, PlaceholderGpdbMajorVersion, strconv.Itoa(l.gpdbMajorVersion))
+		l.files[&include] = file
+		includes = append(includes, &include)
+	}
+	return includes, nil
+}
+
+// merge merges the overlay onto the base. The mappings are merged key by key, the
+// lists of file groups and product files are merged item by item by their keys,
+// and the other values in the overlay replace the ones in the base. The null
+// values in the overlay do not replace anything.
+func (l *metadataLoader) merge(base *yaml.Node, overlay *yaml.Node, listKey string) *yaml.Node {
+	base, overlay = resolveAlias(base), resolveAlias(overlay)
+	if isNullNode(overlay) {
+		return base
+	}
+	if isNullNode(base) {
+		return overlay
+	}
+
+	switch {
+	case base.Kind == yaml.MappingNode && overlay.Kind == yaml.MappingNode:
+		merged := l.copyNode(base)
+		for i := 0; i+1 < len(overlay.Content); i += 2 {
+			key, value := overlay.Content[i], overlay.Content[i+1]
+			if j := mappingIndex(merged, key.Value); j >= 0 {
+				merged.Content[j+1] = l.merge(merged.Content[j+1], value, mergeKeys[key.Value])
+			} else {
+				merged.Content = append(merged.Content, key, value)
+			}
+		}
+		return merged
+	case listKey != "" && base.Kind == yaml.SequenceNode && overlay.Kind == yaml.SequenceNode:
+		merged := l.copyNode(base)
+		for _, item := range overlay.Content {
+			j := -1
+			if itemKey := mappingValue(resolveAlias(item), listKey); itemKey != nil && itemKey.Kind == yaml.ScalarNode {
+				j = sequenceIndex(merged, listKey, itemKey.Value)
+			}
+			if j >= 0 {
+				merged.Content[j] = l.merge(merged.Content[j], item, "")
+			} else {
+				merged.Content = append(merged.Content, item)
+			}
+		}
+		return merged
+	default:
+		return overlay
+	}
+}
+
+func (l *metadataLoader) copyNode(node *yaml.Node) *yaml.Node {
+	copied := *node
+	copied.Content = append([]*yaml.Node(nil), node.Content...)
+	l.files[&copied] = l.files[node]
+	return &copied
+}
+
+func (l *metadataLoader) recordFile(node *yaml.Node, file string) {
+	if node == nil {
+		return
+	}
+	l.files[node] = file
+	for _, child := range node.Content {
+		l.recordFile(child, file)
+	}
+}
+
+func (l *metadataLoader) position(node *yaml.Node) Position {
+	return Position{File: l.files[node], Line: node.Line, Column: node.Column}
+}
+
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	if node != nil && node.Kind == yaml.AliasNode {
+		return node.Alias
+	}
+	return node
+}
+
+func isNullNode(node *yaml.Node) bool {
+	return node == nil || (node.Kind == yaml.ScalarNode && node.Tag == "!!null")
+}
+
+// mappingIndex returns the index of the key in the content of the mapping, or -1
+func mappingIndex(node *yaml.Node, key string) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if i := mappingIndex(node, key); i >= 0 {
+		return node.Content[i+1]
+	}
+	return nil
+}
+
+// sequenceIndex returns the index of the item whose key has the value, or -1
+func sequenceIndex(node *yaml.Node, key string, value string) int {
+	for i, item := range node.Content {
+		itemKey := mappingValue(resolveAlias(item), key)
+		if itemKey != nil && itemKey.Kind == yaml.ScalarNode && itemKey.Value == value {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,199 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baotingfang/go-pivnet-client/config"
+	"github.com/baotingfang/go-pivnet-client/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var baseMetadataYaml = `
+release:
+  release_type: "Major Release"
+  eula_slug: pivotal_software_eula
+  description: "base description"
+aws_object_key: ${GPDB_VERSION}/${FILE_NAME}
+file_groups:
+- name: Greenplum Database Server
+  product_files:
+  - file: file://server/greenplum-db-(.*)-rhel7-x86_64.rpm
+    upload_as: Greenplum Database ${VERSION_REGEX} Installer for RHEL 7
+    file_type: Software
+    file_version: ${VERSION_REGEX}
+- name: Greenplum Clients
+  product_files:
+  - file: file://clients/greenplum-clients-(.*)-rhel7-x86_64.rpm
+    upload_as: Greenplum Clients ${VERSION_REGEX} for RHEL 7
+    file_type: Software
+    file_version: ${VERSION_REGEX}
+product_files:
+- file: file://osl/open_source_license_pivotal-gpdb-(.*).txt
+  upload_as: Open Source Licenses
+  file_type: Open Source License
+  file_version: ${VERSION_REGEX}
+`
+
+var gpdb6MetadataYaml = `
+release:
+  description: "gpdb 6 description"
+file_groups:
+- name: Greenplum Database Server
+  product_files:
+  - upload_as: Greenplum Database ${VERSION_REGEX} Installer for RHEL 7
+    file: file://server/greenplum-db-(6\..*)-rhel7-x86_64.rpm
+  - file: file://server/greenplum-db-(6\..*)-rhel8-x86_64.rpm
+    upload_as: Greenplum Database ${VERSION_REGEX} Installer for RHEL 8
+    file_type: Software
+    file_version: ${VERSION_REGEX}
+- name: Greenplum Streaming Server
+  product_files:
+  - file: file://gpss/gpss-(.*).gppkg
+    upload_as: Greenplum Streaming Server
+    file_type: Software
+    file_version: ${VERSION_REGEX}
+product_files:
+- file: file://osl/open_source_license_pivotal-gpdb-(6\..*).txt
+  upload_as: Open Source Licenses
+`
+
+var _ = Describe("metadata includes and overlays", func() {
+	var tmpDir string
+
+	writeFile := func(name string, content string) string {
+		path := filepath.Join(tmpDir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "metadata")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	expectMerged := func(metadata config.Metadata) {
+		Expect(metadata.Release.Description).To(Equal("gpdb 6 description"))
+		Expect(metadata.Release.ReleaseType).To(BeEquivalentTo("Major Release"))
+		Expect(metadata.Release.EulaSlug).To(Equal("pivotal_software_eula"))
+		Expect(metadata.AwsObjectKey).To(Equal("${GPDB_VERSION}/${FILE_NAME}"))
+
+		Expect(metadata.FileGroups).To(HaveLen(3))
+		server := metadata.FileGroups[0]
+		Expect(server.Name).To(Equal("Greenplum Database Server"))
+		Expect(server.ProductFiles).To(HaveLen(2))
+		Expect(server.ProductFiles[0].File).To(Equal(`file://server/greenplum-db-(6\..*)-rhel7-x86_64.rpm`))
+		Expect(server.ProductFiles[0].FileType).To(Equal("Software"))
+		Expect(server.ProductFiles[0].FileVersion).To(Equal("${VERSION_REGEX}"))
+		Expect(server.ProductFiles[1].UploadAs).To(Equal("Greenplum Database ${VERSION_REGEX} Installer for RHEL 8"))
+		Expect(metadata.FileGroups[1].Name).To(Equal("Greenplum Clients"))
+		Expect(metadata.FileGroups[2].Name).To(Equal("Greenplum Streaming Server"))
+
+		Expect(metadata.ProductFiles).To(HaveLen(1))
+		Expect(metadata.ProductFiles[0].File).To(Equal(`file://osl/open_source_license_pivotal-gpdb-(6\..*).txt`))
+		Expect(metadata.ProductFiles[0].FileType).To(Equal("Open Source License"))
+	}
+
+	It("merges the overlay files onto the metadata file", func() {
+		base := writeFile("base.yml", baseMetadataYaml)
+		overlay := writeFile("gpdb6.yml", gpdb6MetadataYaml)
+
+		metadata, err := config.MetadataFromFiles("6.6.0", base, overlay)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(metadata.Release.Version).To(Equal("6.6.0"))
+		expectMerged(metadata)
+	})
+
+	It("merges the metadata onto its includes", func() {
+		writeFile("shared/base.yml", baseMetadataYaml)
+		path := writeFile("gpdb6.yml", "include:\n- shared/base.yml\n"+gpdb6MetadataYaml)
+
+		metadata, err := config.MetadataFromFiles("6.6.0", path)
+		Expect(err).NotTo(HaveOccurred())
+		expectMerged(metadata)
+		Expect(metadata.Include).To(Equal([]string{"shared/base.yml"}))
+	})
+
+	It("replaces the major version of gpdb in the include paths", func() {
+		writeFile("base.yml", baseMetadataYaml)
+		writeFile("gpdb6.yml", "include:\n- base.yml\n"+gpdb6MetadataYaml)
+		path := writeFile("metadata.yml", "include:\n- gpdb${GPDB_MAJOR_VERSION}.yml\n")
+
+		metadata, err := config.MetadataFromFiles("6.6.0", path)
+		Expect(err).NotTo(HaveOccurred())
+		expectMerged(metadata)
+	})
+
+	It("includes the metadata in other formats", func() {
+		writeFile("base.json", metadataJson)
+		path := writeFile("metadata.yml", `
+include:
+- base.json
+release:
+  description: "overlay description"
+`)
+
+		metadata, err := config.MetadataFromFiles("6.6.0", path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(metadata.Release.Description).To(Equal("overlay description"))
+		Expect(metadata.Release.EulaSlug).To(Equal("pivotal_software_eula"))
+		Expect(metadata.FileGroups).To(HaveLen(1))
+		Expect(metadata.FileGroups[0].ProductFiles[0].Platforms).To(Equal([]string{"rhel7"}))
+	})
+
+	It("keeps the values of the base when the values in the overlay are null", func() {
+		base := writeFile("base.yml", baseMetadataYaml)
+		overlay := writeFile("overlay.yml", "release:\n  description: ~\nfile_groups:\n")
+
+		metadata, err := config.MetadataFromFiles("6.6.0", base, overlay)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(metadata.Release.Description).To(Equal("base description"))
+		Expect(metadata.FileGroups).To(HaveLen(2))
+	})
+
+	It("reports the positions of the errors in the files where the fields are from", func() {
+		base := writeFile("base.yml", baseMetadataYaml)
+		overlay := writeFile("overlay.yml", "file_groups:\n- name: Greenplum Clients\n  product_files:\n  - upload_as: Greenplum Clients ${VERSION_REGEX} for RHEL 7\n    file_typo: Software\n")
+
+		_, err := config.MetadataFromFiles("6.6.0", base, overlay)
+		Expect(err).To(MatchError("file_groups[1].product_files[0].file_typo (" + overlay + ", line 5, column 5): field file_typo is not supported"))
+		Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
+
+		metadata, err := config.MetadataFromFiles("6.6.0", base, writeFile("valid.yml", "release:\n  description: overlay\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(metadata.Position("release.description")).To(Equal(config.Position{File: filepath.Join(tmpDir, "valid.yml"), Line: 2, Column: 16}))
+		Expect(metadata.Position("file_groups[1].name")).To(Equal(config.Position{File: base, Line: 14, Column: 9}))
+	})
+
+	It("rejects the recursive includes", func() {
+		writeFile("a.yml", "include:\n- b.yml\n")
+		path := writeFile("b.yml", "include:\n- a.yml\n")
+
+		_, err := config.MetadataFromFiles("6.6.0", path)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("b.yml is included recursively"))
+		Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
+	})
+
+	It("returns the storage error when the included file does not exist", func() {
+		path := writeFile("metadata.yml", "include:\n- missing.yml\n")
+
+		_, err := config.MetadataFromFiles("6.6.0", path)
+		Expect(err).To(MatchError(ContainSubstring("can not open metadata file " + filepath.Join(tmpDir, "missing.yml"))))
+		Expect(err).To(BeAssignableToTypeOf(utils.StorageError{}))
+	})
+
+	It("rejects the include which is not a list of paths", func() {
+		_, err := config.MetadataFrom(strings.NewReader("include: base.yml\n"), "6.6.0")
+		Expect(err).To(MatchError("include (line 1, column 10): must be a sequence"))
+	})
+})
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+const SchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a json schema of the metadata file, which is used by the editors to
+// complete the metadata, and by CI to validate the metadata before uploading.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *bool              `json:"additionalProperties,omitempty"`
+}
+
+// datePattern is the form of the dates, the date is YYYY-MM-DD or computed by the client
+var datePattern = `^(\d{4}-\d{2}-\d{2}|` + COMPUTED + `)$`
+
+func dateSchema(description string) *Schema {
+	return &Schema{
+		Description: description + `, the format is "YYYY-MM-DD", or ` + COMPUTED,
+		Pattern:     datePattern,
+	}
+}
+
+// schemaFields are the schemas of the fields which have the descriptions, enums
+// and patterns, by their paths. The items of the lists are in the paths like file_groups[].
+var schemaFields = map[string]*Schema{
+	"include": {
+		Description: "Metadata files merged before this file, the paths are relative to this file, " +
+			PlaceholderGpdbMajorVersion + " is replaced with the major version of gpdb",
+	},
+	"aws_object_key": {
+		Description: "Default aws object key template of the product files",
+	},
+	"release.release_type": {
+		Type:        "string",
+		Description: "Type of the release, computed from the gpdb version when it is " + COMPUTED,
+		Enum:        append(append([]string(nil), ReleaseTypes...), COMPUTED),
+	},
+	"release.availability": {
+		Type:        "string",
+		Description: "Availability of the release",
+		Enum:        Availabilities,
+	},
+	"release.release_date":             dateSchema("Release date, today when it is empty"),
+	"release.end_of_support_date":      dateSchema("End of support date"),
+	"release.end_of_guidance_date":     dateSchema("End of guidance date"),
+	"release.end_of_availability_date": dateSchema("End of availability date, can not be set with end_of_availability_date_offset"),
+	"release.end_of_availability_date_offset": {
+		Type:        "string",
+		Description: "Offset of the end of availability date from the release date, like +1y+3m+10d",
+		Pattern:     "^" + OffsetPattern + "$",
+	},
+	"release.release_notes_url": {
+		Type:        "string",
+		Description: "Release notes url, computed from the gpdb version when it is " + COMPUTED,
+	},
+	"file_groups": {
+		Description: "File groups of the release, the file groups in the overlays are merged by name",
+	},
+	"product_files": {
+		Description: "Product files of the release, the product files in the overlays are merged by upload_as",
+	},
+}
+
+// MetadataSchema returns the json schema of the metadata, which is generated from
+// the fields of Metadata, so that the unknown fields are rejected as the client does.
+func MetadataSchema() *Schema {
+	schema := typeSchema(reflect.TypeOf(Metadata{}), "")
+	schema.Schema = SchemaDraft
+	schema.Title = "go-pivnet-client metadata"
+	schema.Description = "Metadata of a gpdb release uploaded to pivnet"
+	return schema
+}
+
+// MetadataSchemaJSON returns the json schema of the metadata in indented json
+func MetadataSchemaJSON() ([]byte, error) {
+	return json.MarshalIndent(MetadataSchema(), "", "  ")
+}
+
+func typeSchema(t reflect.Type, path string) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	schema := &Schema{}
+	switch t.Kind() {
+	case reflect.Struct:
+		schema.Type = "object"
+		schema.Properties = make(map[string]*Schema)
+		for key, fieldType := range yamlFields(t) {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+			schema.Properties[key] = typeSchema(fieldType, fieldPath)
+		}
+		additionalProperties := false
+		schema.AdditionalProperties = &additionalProperties
+	case reflect.Slice, reflect.Array:
+		schema.Type = "array"
+		schema.Items = typeSchema(t.Elem(), path+"[]")
+	case reflect.Map:
+		schema.Type = "object"
+	case reflect.Interface:
+	case reflect.Bool:
+		schema.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema.Type = "number"
+	default:
+		schema.Type = "string"
+	}
+
+	if field, ok := schemaFields[path]; ok {
+		schema = mergeSchema(schema, field)
+	}
+	return schema
+}
+
+// mergeSchema sets the settings of the field onto the schema generated from the type
+func mergeSchema(schema *Schema, field *Schema) *Schema {
+	merged := *schema
+	if field.Description != "" {
+		merged.Description = field.Description
+	}
+	if field.Pattern != "" {
+		merged.Pattern = field.Pattern
+	}
+	if len(field.Enum) > 0 {
+		merged.Enum = field.Enum
+	}
+	if field.Type != "" {
+		merged.Type = field.Type
+	}
+	return &merged
+}
@@ -0,0 +1,82 @@
+package config_test
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/baotingfang/go-pivnet-client/config"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+var _ = Describe("metadata schema", func() {
+	validate := func(document string) []string {
+		schema, err := config.MetadataSchemaJSON()
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema), gojsonschema.NewStringLoader(document))
+		Expect(err).NotTo(HaveOccurred())
+		var errs []string
+		for _, e := range result.Errors() {
+			errs = append(errs, e.String())
+		}
+		return errs
+	}
+
+	It("generates the schema from the fields of metadata", func() {
+		schema := config.MetadataSchema()
+		Expect(schema.Schema).To(Equal(config.SchemaDraft))
+		Expect(schema.Properties).To(HaveKey("include"))
+		Expect(schema.Properties).To(HaveKey("aws_object_key"))
+
+		release := schema.Properties["release"]
+		Expect(*release.AdditionalProperties).To(BeFalse())
+		Expect(release.Properties["release_type"].Enum).To(ConsistOf(
+			"Alpha Release", "Beta Release", "Major Release", "Minor Release", "Maintenance Release", config.COMPUTED))
+		Expect(release.Properties["availability"].Enum).To(Equal(config.Availabilities))
+		Expect(release.Properties["end_of_availability_date_offset"].Pattern).To(Equal(`^(\+\d+[mdyMDY])+$`))
+
+		productFile := schema.Properties["file_groups"].Items.Properties["product_files"].Items
+		Expect(productFile.Properties).To(HaveKey("upload_as"))
+		Expect(productFile.Properties).To(HaveKey("file_type"))
+		Expect(productFile.Properties["platforms"].Items.Type).To(Equal("string"))
+	})
+
+	It("accepts the valid metadata", func() {
+		Expect(validate(metadataJson)).To(BeEmpty())
+
+		metadata, err := config.MetadataFrom(strings.NewReader(metadataYaml), "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+		content, err := json.Marshal(metadata)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(validate(string(content))).To(BeEmpty())
+	})
+
+	It("accepts the computed values", func() {
+		Expect(validate(`{"release": {"release_type": "<COMPUTED>", "end_of_support_date": "<COMPUTED>"}}`)).To(BeEmpty())
+	})
+
+	It("rejects the unknown fields", func() {
+		errs := validate(`{"release": {"releases_type": "Major Release"}, "product_files": [{"upload_ass": "osl"}]}`)
+		Expect(errs).To(ConsistOf(
+			ContainSubstring("releases_type is not allowed"),
+			ContainSubstring("upload_ass is not allowed"),
+		))
+	})
+
+	It("rejects the invalid enums, offsets and dates", func() {
+		errs := validate(`{"release": {
+			"release_type": "Patch Release",
+			"availability": "Everyone",
+			"end_of_availability_date_offset": "1y3m",
+			"end_of_support_date": "2020/05/19"
+		}}`)
+		Expect(errs).To(ConsistOf(
+			ContainSubstring("release.release_type"),
+			ContainSubstring("release.availability"),
+			ContainSubstring("release.end_of_availability_date_offset"),
+			ContainSubstring("release.end_of_support_date"),
+		))
+	})
+})
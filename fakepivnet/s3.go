@@ -0,0 +1,223 @@
+package fakepivnet
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// multipartUpload keeps the parts of a s3 multipart upload until it is completed
+type multipartUpload struct {
+	bucket string
+	key    string
+	parts  map[int][]byte
+}
+
+// S3Endpoint is the endpoint to upload the product files to the bucket of the
+// federation token, the bucket is in the path of url.
+func (s *Server) S3Endpoint() string {
+	return s.URL
+}
+
+// Object returns the content of the object uploaded to the bucket
+func (s *Server) Object(bucket string, key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content, ok := s.objects[objectName(bucket, key)]
+	return content, ok
+}
+
+// SetObjectLastModified changes the last modified time of the object, so that
+// the objects uploaded long ago can be simulated
+func (s *Server) SetObjectLastModified(bucket string, key string, lastModified time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.objects[objectName(bucket, key)]; ok {
+		s.modTimes[objectName(bucket, key)] = lastModified
+	}
+}
+
+// ObjectKeys returns the keys of all the objects in the bucket in order
+func (s *Server) ObjectKeys(bucket string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for name := range s.objects {
+		if strings.HasPrefix(name, bucket+"/") {
+			keys = append(keys, strings.TrimPrefix(name, bucket+"/"))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// serveS3 handles the s3 requests sent by the aws sdk in path style, it does not
+// verify the signature of the requests.
+func (s *Server) serveS3(w http.ResponseWriter, req *http.Request) {
+	segments := strings.SplitN(strings.TrimPrefix(req.URL.Path, "/"), "/", 2)
+	if len(segments) != 2 || segments[0] == "" || segments[1] == "" {
+		writeS3Error(w, http.StatusBadRequest, "InvalidRequest", "bucket and key are required")
+		return
+	}
+	bucket, key := segments[0], segments[1]
+	query := req.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if bucket != s.Bucket {
+		writeS3Error(w, http.StatusNotFound, "NoSuchBucket", fmt.Sprintf("bucket %s does not exist", bucket))
+		return
+	}
+
+	_, initiate := query["uploads"]
+	uploadId := query.Get("uploadId")
+	switch {
+	case req.Method == http.MethodPost && initiate:
+		s.initiateMultipartUpload(w, bucket, key)
+	case req.Method == http.MethodPut && uploadId != "":
+		s.uploadPart(w, req, uploadId, query.Get("partNumber"))
+	case req.Method == http.MethodPost && uploadId != "":
+		s.completeMultipartUpload(w, uploadId)
+	case req.Method == http.MethodDelete && uploadId != "":
+		delete(s.uploads, uploadId)
+		w.WriteHeader(http.StatusNoContent)
+	case req.Method == http.MethodPut:
+		content, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+		s.putObject(bucket, key, content)
+		w.Header().Set("ETag", etag(content))
+		w.WriteHeader(http.StatusOK)
+	case req.Method == http.MethodHead:
+		content, ok := s.objects[objectName(bucket, key)]
+		if !ok {
+			// the response of HEAD has no body
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("ETag", etag(content))
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("Last-Modified", s.modTimes[objectName(bucket, key)].UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+	case req.Method == http.MethodGet:
+		content, ok := s.objects[objectName(bucket, key)]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey", fmt.Sprintf("key %s does not exist", key))
+			return
+		}
+		w.Header().Set("ETag", etag(content))
+		_, _ = w.Write(content)
+	case req.Method == http.MethodDelete:
+		delete(s.objects, objectName(bucket, key))
+		delete(s.modTimes, objectName(bucket, key))
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", fmt.Sprintf("%s is not supported", req.Method))
+	}
+}
+
+func (s *Server) initiateMultipartUpload(w http.ResponseWriter, bucket string, key string) {
+	uploadId := strconv.Itoa(s.newID())
+	s.uploads[uploadId] = &multipartUpload{bucket: bucket, key: key, parts: make(map[int][]byte)}
+	writeXML(w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+		Bucket   string
+		Key      string
+		UploadId string
+	}{Bucket: bucket, Key: key, UploadId: uploadId})
+}
+
+func (s *Server) uploadPart(w http.ResponseWriter, req *http.Request, uploadId string, partNumber string) {
+	upload, ok := s.uploads[uploadId]
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchUpload", fmt.Sprintf("upload %s does not exist", uploadId))
+		return
+	}
+	number, err := strconv.Atoi(partNumber)
+	if err != nil {
+		writeS3Error(w, http.StatusBadRequest, "InvalidArgument", fmt.Sprintf("part number %q is not valid", partNumber))
+		return
+	}
+	content, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		writeS3Error(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+		return
+	}
+	upload.parts[number] = content
+	w.Header().Set("ETag", etag(content))
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) completeMultipartUpload(w http.ResponseWriter, uploadId string) {
+	upload, ok := s.uploads[uploadId]
+	if !ok {
+		writeS3Error(w, http.StatusNotFound, "NoSuchUpload", fmt.Sprintf("upload %s does not exist", uploadId))
+		return
+	}
+
+	numbers := make([]int, 0, len(upload.parts))
+	for number := range upload.parts {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	var content []byte
+	for _, number := range numbers {
+		content = append(content, upload.parts[number]...)
+	}
+	s.putObject(upload.bucket, upload.key, content)
+	delete(s.uploads, uploadId)
+
+	writeXML(w, http.StatusOK, struct {
+		XMLName  xml.Name `xml:"CompleteMultipartUploadResult"`
+		Location string
+		Bucket   string
+		Key      string
+		ETag     string
+	}{
+		Location: fmt.Sprintf("%s/%s/%s", s.URL, upload.bucket, upload.key),
+		Bucket:   upload.bucket,
+		Key:      upload.key,
+		ETag:     etag(content),
+	})
+}
+
+func (s *Server) putObject(bucket string, key string, content []byte) {
+	s.objects[objectName(bucket, key)] = content
+	s.modTimes[objectName(bucket, key)] = time.Now()
+}
+
+func objectName(bucket string, key string) string {
+	return bucket + "/" + key
+}
+
+func etag(content []byte) string {
+	sum := md5.Sum(content)
+	return `"` + hex.EncodeToString(sum[:]) + `"`
+}
+
+func writeXML(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+	_ = xml.NewEncoder(w).Encode(body)
+}
+
+func writeS3Error(w http.ResponseWriter, statusCode int, code string, message string) {
+	writeXML(w, statusCode, struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+		Message string
+	}{Code: code, Message: message})
+}
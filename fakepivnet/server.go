@@ -0,0 +1,903 @@
+package fakepivnet
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	apiPrefix = "/api/v2"
+
+	// DefaultRefreshToken is longer than a legacy api token, so that it is exchanged
+	// for DefaultAccessToken like a uaa refresh token
+	DefaultRefreshToken = "fake-pivnet-refresh-token"
+	DefaultAccessToken  = "fake-pivnet-access-token"
+	DefaultEULASlug     = "pivotal_software_eula"
+	DefaultBucket       = "fake-pivnet-bucket"
+	DefaultRegion       = "us-east-1"
+
+	FileTransferInProgress = "in_progress"
+	FileTransferComplete   = "complete"
+)
+
+var availabilities = []string{"Admins Only", "Selected User Groups Only", "All Users"}
+
+// Server is an in-process fake of the pivnet api, the objects are kept in memory,
+// so that the real pivnet client can talk to it without network access. It also
+// serves the federation token bucket as a s3 compatible endpoint in path style.
+type Server struct {
+	*httptest.Server
+
+	// RefreshToken is accepted as a legacy api token, or exchanged for AccessToken
+	RefreshToken string
+	AccessToken  string
+	Bucket       string
+	Region       string
+	// TransferPolls is the number of times a new product file is reported
+	// as in progress of the file transfer before it is complete
+	TransferPolls int
+
+	mu         sync.Mutex
+	nextID     int
+	products   map[string]*product
+	eulas      []pivnet.EULA
+	userGroups []pivnet.UserGroup
+	failures   []*failure
+	hooks      []*hook
+	requests   []string
+	objects    map[string][]byte
+	modTimes   map[string]time.Time
+	uploads    map[string]*multipartUpload
+}
+
+type product struct {
+	releases     map[int]*release
+	fileGroups   map[int]*fileGroup
+	productFiles map[int]*productFile
+}
+
+type release struct {
+	pivnet.Release
+	fileGroupIds   []int
+	productFileIds []int
+	userGroupIds   []int
+}
+
+type fileGroup struct {
+	pivnet.FileGroup
+	productFileIds []int
+}
+
+type productFile struct {
+	pivnet.ProductFile
+	transferPolls int
+}
+
+type hook struct {
+	method string
+	path   string
+	run    func()
+}
+
+type failure struct {
+	method     string
+	path       string
+	statusCode int
+	times      int
+}
+
+// NewServer starts a fake pivnet server with the default tokens, bucket and eula,
+// it should be closed after use.
+func NewServer() *Server {
+	s := &Server{
+		RefreshToken: DefaultRefreshToken,
+		AccessToken:  DefaultAccessToken,
+		Bucket:       DefaultBucket,
+		Region:       DefaultRegion,
+		nextID:       1,
+		products:     make(map[string]*product),
+		objects:      make(map[string][]byte),
+		modTimes:     make(map[string]time.Time),
+		uploads:      make(map[string]*multipartUpload),
+	}
+	s.AddEULA(DefaultEULASlug, "Pivotal Software EULA")
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// AddEULA adds the eula which can be referred by the releases
+func (s *Server) AddEULA(slug string, name string) pivnet.EULA {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	eula := pivnet.EULA{ID: s.newID(), Slug: slug, Name: name}
+	s.eulas = append(s.eulas, eula)
+	return eula
+}
+
+// AddUserGroup adds the user group which can be added to the releases
+func (s *Server) AddUserGroup(name string, description string) pivnet.UserGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group := pivnet.UserGroup{ID: s.newID(), Name: name, Description: description}
+	s.userGroups = append(s.userGroups, group)
+	return group
+}
+
+// AddRelease adds the release to the product as if it was created before
+func (s *Server) AddRelease(productSlug string, r pivnet.Release) pivnet.Release {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r.ID = s.newID()
+	if r.Availability == "" {
+		r.Availability = availabilities[0]
+	}
+	s.product(productSlug).releases[r.ID] = &release{Release: r}
+	return s.releaseView(s.product(productSlug), r.ID)
+}
+
+// Fail makes the next requests of the method and path fail with the status code,
+// the path is relative to /api/v2, e.g. "/products/slug/product_files".
+func (s *Server) Fail(method string, path string, statusCode int, times int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = append(s.failures, &failure{method: method, path: path, statusCode: statusCode, times: times})
+}
+
+// Before runs the function once before the next request of the method and path is
+// handled, e.g. to cancel the client or to slow down the request. The path is
+// relative to /api/v2.
+func (s *Server) Before(method string, path string, run func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hooks = append(s.hooks, &hook{method: method, path: path, run: run})
+}
+
+// Requests returns the pivnet api requests received, in the form of "METHOD /path"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) Releases(productSlug string) []pivnet.Release {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.product(productSlug)
+	releases := make([]pivnet.Release, 0, len(p.releases))
+	for _, id := range sortedIds(p.releases) {
+		releases = append(releases, s.releaseView(p, id))
+	}
+	return releases
+}
+
+func (s *Server) FileGroups(productSlug string) []pivnet.FileGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.product(productSlug)
+	return p.fileGroupViews(sortedIds(p.fileGroups))
+}
+
+func (s *Server) ProductFiles(productSlug string) []pivnet.ProductFile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.product(productSlug)
+	return p.productFileViews(sortedIds(p.productFiles))
+}
+
+func (s *Server) ReleaseFileGroups(productSlug string, releaseId int) []pivnet.FileGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.product(productSlug)
+	if r, ok := p.releases[releaseId]; ok {
+		return p.fileGroupViews(r.fileGroupIds)
+	}
+	return nil
+}
+
+func (s *Server) ReleaseProductFiles(productSlug string, releaseId int) []pivnet.ProductFile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := s.product(productSlug)
+	if r, ok := p.releases[releaseId]; ok {
+		return p.productFileViews(r.productFileIds)
+	}
+	return nil
+}
+
+func (s *Server) ReleaseUserGroups(productSlug string, releaseId int) []pivnet.UserGroup {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.product(productSlug).releases[releaseId]; ok {
+		return s.userGroupViews(r.userGroupIds)
+	}
+	return nil
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	if !strings.HasPrefix(req.URL.Path, apiPrefix+"/") {
+		s.serveS3(w, req)
+		return
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, apiPrefix)
+
+	// the hook runs without the lock, so that the other requests are not blocked
+	if run := s.takeHook(req.Method, path); run != nil {
+		run()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// the refresh token is exchanged before every request, it is not recorded
+	if req.Method == http.MethodPost && path == "/authentication/access_tokens" {
+		s.createAccessToken(w, req)
+		return
+	}
+
+	s.requests = append(s.requests, req.Method+" "+path)
+
+	if statusCode, failed := s.injectedFailure(req.Method, path); failed {
+		writeError(w, statusCode, "injected failure of fake pivnet")
+		return
+	}
+	if !s.authorized(req.Header.Get("Authorization")) {
+		writeError(w, http.StatusUnauthorized, "invalid api token")
+		return
+	}
+
+	for _, route := range routes {
+		if params, ok := route.match(req.Method, path); ok {
+			route.handle(s, w, req, params)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("no route matches %s %s", req.Method, path))
+}
+
+func (s *Server) takeHook(method string, path string) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, h := range s.hooks {
+		if h.method == method && h.path == path {
+			s.hooks = append(s.hooks[:i], s.hooks[i+1:]...)
+			return h.run
+		}
+	}
+	return nil
+}
+
+func (s *Server) injectedFailure(method string, path string) (int, bool) {
+	for i, f := range s.failures {
+		if f.method == method && f.path == path {
+			f.times--
+			if f.times <= 0 {
+				s.failures = append(s.failures[:i], s.failures[i+1:]...)
+			}
+			return f.statusCode, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Server) authorized(header string) bool {
+	return header == "Bearer "+s.AccessToken || header == "Token "+s.RefreshToken
+}
+
+func (s *Server) createAccessToken(w http.ResponseWriter, req *http.Request) {
+	var body pivnet.AuthBody
+	if !decodeBody(w, req, &body) {
+		return
+	}
+	if body.RefreshToken != s.RefreshToken {
+		writeError(w, http.StatusUnauthorized, "invalid refresh token")
+		return
+	}
+	writeJSON(w, http.StatusOK, pivnet.AuthResp{Token: s.AccessToken})
+}
+
+type route struct {
+	method  string
+	pattern []string
+	handle  func(s *Server, w http.ResponseWriter, req *http.Request, params params)
+}
+
+// params are the values of ":name" segments in the route pattern
+type params map[string]string
+
+func (p params) id(name string) int {
+	id, _ := strconv.Atoi(p[name])
+	return id
+}
+
+func (r route) match(method string, path string) (params, bool) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if method != r.method || len(segments) != len(r.pattern) {
+		return nil, false
+	}
+
+	matched := params{}
+	for i, pattern := range r.pattern {
+		if strings.HasPrefix(pattern, ":") {
+			name := pattern[1:]
+			if name != "slug" {
+				if _, err := strconv.Atoi(segments[i]); err != nil {
+					return nil, false
+				}
+			}
+			matched[name] = segments[i]
+		} else if pattern != segments[i] {
+			return nil, false
+		}
+	}
+	return matched, true
+}
+
+func newRoute(method string, pattern string, handle func(*Server, http.ResponseWriter, *http.Request, params)) route {
+	return route{method: method, pattern: strings.Split(strings.Trim(pattern, "/"), "/"), handle: handle}
+}
+
+var routes = []route{
+	newRoute("GET", "/products/:slug/releases", (*Server).listReleases),
+	newRoute("POST", "/products/:slug/releases", (*Server).createRelease),
+	newRoute("GET", "/products/:slug/releases/:release", (*Server).getRelease),
+	newRoute("PATCH", "/products/:slug/releases/:release", (*Server).updateRelease),
+	newRoute("DELETE", "/products/:slug/releases/:release", (*Server).deleteRelease),
+	newRoute("GET", "/products/:slug/releases/:release/file_groups", (*Server).listReleaseFileGroups),
+	newRoute("PATCH", "/products/:slug/releases/:release/add_file_group", (*Server).addFileGroupToRelease),
+	newRoute("GET", "/products/:slug/releases/:release/product_files", (*Server).listReleaseProductFiles),
+	newRoute("PATCH", "/products/:slug/releases/:release/add_product_file", (*Server).addProductFileToRelease),
+	newRoute("GET", "/products/:slug/releases/:release/user_groups", (*Server).listReleaseUserGroups),
+	newRoute("PATCH", "/products/:slug/releases/:release/add_user_group", (*Server).addUserGroupToRelease),
+	newRoute("GET", "/products/:slug/file_groups", (*Server).listFileGroups),
+	newRoute("POST", "/products/:slug/file_groups", (*Server).createFileGroup),
+	newRoute("GET", "/products/:slug/file_groups/:group", (*Server).getFileGroup),
+	newRoute("DELETE", "/products/:slug/file_groups/:group", (*Server).deleteFileGroup),
+	newRoute("PATCH", "/products/:slug/file_groups/:group/add_product_file", (*Server).addProductFileToFileGroup),
+	newRoute("GET", "/products/:slug/product_files", (*Server).listProductFiles),
+	newRoute("POST", "/products/:slug/product_files", (*Server).createProductFile),
+	newRoute("GET", "/products/:slug/product_files/:file", (*Server).getProductFile),
+	newRoute("DELETE", "/products/:slug/product_files/:file", (*Server).deleteProductFile),
+	newRoute("POST", "/federation_token", (*Server).createFederationToken),
+	newRoute("GET", "/user_groups", (*Server).listUserGroups),
+	newRoute("GET", "/eulas", (*Server).listEULAs),
+	newRoute("GET", "/eulas/:slug", (*Server).getEULA),
+}
+
+func (s *Server) listReleases(w http.ResponseWriter, req *http.Request, params params) {
+	p := s.product(params["slug"])
+	response := pivnet.ReleasesResponse{Releases: []pivnet.Release{}}
+	for _, id := range sortedIds(p.releases) {
+		response.Releases = append(response.Releases, s.releaseView(p, id))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (s *Server) createRelease(w http.ResponseWriter, req *http.Request, params params) {
+	var body struct {
+		Release pivnet.Release `json:"release"`
+	}
+	if !decodeBody(w, req, &body) {
+		return
+	}
+
+	p := s.product(params["slug"])
+	r := body.Release
+	if r.Version == "" {
+		writeError(w, http.StatusUnprocessableEntity, "version can't be blank")
+		return
+	}
+	for _, existing := range p.releases {
+		if existing.Version == r.Version {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("version %s has already been taken", r.Version))
+			return
+		}
+	}
+	if !s.validEULA(r.EULA) {
+		writeError(w, http.StatusUnprocessableEntity, "eula can not be found")
+		return
+	}
+	if !validAvailability(r.Availability) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("availability %q is not valid", r.Availability))
+		return
+	}
+
+	r.ID = s.newID()
+	p.releases[r.ID] = &release{Release: r}
+	writeJSON(w, http.StatusCreated, pivnet.CreateReleaseResponse{Release: s.releaseView(p, r.ID)})
+}
+
+func (s *Server) getRelease(w http.ResponseWriter, req *http.Request, params params) {
+	p, r := s.findRelease(w, params)
+	if r == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.releaseView(p, r.ID))
+}
+
+func (s *Server) updateRelease(w http.ResponseWriter, req *http.Request, params params) {
+	p, r := s.findRelease(w, params)
+	if r == nil {
+		return
+	}
+
+	var body struct {
+		Release pivnet.Release `json:"release"`
+	}
+	if !decodeBody(w, req, &body) {
+		return
+	}
+	updated := body.Release
+	if !validAvailability(updated.Availability) {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("availability %q is not valid", updated.Availability))
+		return
+	}
+	if updated.EULA == nil || updated.EULA.Slug == "" {
+		updated.EULA = r.EULA
+	} else if !s.validEULA(updated.EULA) {
+		writeError(w, http.StatusUnprocessableEntity, "eula can not be found")
+		return
+	}
+
+	updated.ID = r.ID
+	r.Release = updated
+	writeJSON(w, http.StatusOK, pivnet.CreateReleaseResponse{Release: s.releaseView(p, r.ID)})
+}
+
+func (s *Server) deleteRelease(w http.ResponseWriter, req *http.Request, params params) {
+	p, r := s.findRelease(w, params)
+	if r == nil {
+		return
+	}
+	delete(p.releases, r.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listReleaseFileGroups(w http.ResponseWriter, req *http.Request, params params) {
+	p, r := s.findRelease(w, params)
+	if r == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, pivnet.FileGroupsResponse{FileGroups: p.fileGroupViews(r.fileGroupIds)})
+}
+
+func (s *Server) addFileGroupToRelease(w http.ResponseWriter, req *http.Request, params params) {
+	p, r := s.findRelease(w, params)
+	if r == nil {
+		return
+	}
+	var body struct {
+		FileGroup pivnet.FileGroup `json:"file_group"`
+	}
+	if !decodeBody(w, req, &body) {
+		return
+	}
+	if _, ok := p.fileGroups[body.FileGroup.ID]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("file group %d can not be found", body.FileGroup.ID))
+		return
+	}
+	r.fileGroupIds = addId(r.fileGroupIds, body.FileGroup.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listReleaseProductFiles(w http.ResponseWriter, req *http.Request, params params) {
+	p, r := s.findRelease(w, params)
+	if r == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, pivnet.ProductFilesResponse{ProductFiles: p.productFileViews(r.productFileIds)})
+}
+
+func (s *Server) addProductFileToRelease(w http.ResponseWriter, req *http.Request, params params) {
+	p, r := s.findRelease(w, params)
+	if r == nil {
+		return
+	}
+	id, ok := s.decodeProductFileId(w, req, p)
+	if !ok {
+		return
+	}
+	r.productFileIds = addId(r.productFileIds, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listReleaseUserGroups(w http.ResponseWriter, req *http.Request, params params) {
+	_, r := s.findRelease(w, params)
+	if r == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, pivnet.UserGroupsResponse{UserGroups: s.userGroupViews(r.userGroupIds)})
+}
+
+func (s *Server) addUserGroupToRelease(w http.ResponseWriter, req *http.Request, params params) {
+	_, r := s.findRelease(w, params)
+	if r == nil {
+		return
+	}
+	var body struct {
+		UserGroup pivnet.UserGroup `json:"user_group"`
+	}
+	if !decodeBody(w, req, &body) {
+		return
+	}
+	if len(s.userGroupViews([]int{body.UserGroup.ID})) == 0 {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("user group %d can not be found", body.UserGroup.ID))
+		return
+	}
+	r.userGroupIds = addId(r.userGroupIds, body.UserGroup.ID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listFileGroups(w http.ResponseWriter, req *http.Request, params params) {
+	p := s.product(params["slug"])
+	writeJSON(w, http.StatusOK, pivnet.FileGroupsResponse{FileGroups: p.fileGroupViews(sortedIds(p.fileGroups))})
+}
+
+func (s *Server) createFileGroup(w http.ResponseWriter, req *http.Request, params params) {
+	var body struct {
+		FileGroup pivnet.FileGroup `json:"file_group"`
+	}
+	if !decodeBody(w, req, &body) {
+		return
+	}
+	if body.FileGroup.Name == "" {
+		writeError(w, http.StatusUnprocessableEntity, "name can't be blank")
+		return
+	}
+
+	p := s.product(params["slug"])
+	group := &fileGroup{FileGroup: pivnet.FileGroup{ID: s.newID(), Name: body.FileGroup.Name}}
+	p.fileGroups[group.ID] = group
+	writeJSON(w, http.StatusCreated, p.fileGroupView(group.ID))
+}
+
+func (s *Server) getFileGroup(w http.ResponseWriter, req *http.Request, params params) {
+	p, group := s.findFileGroup(w, params)
+	if group == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, p.fileGroupView(group.ID))
+}
+
+func (s *Server) deleteFileGroup(w http.ResponseWriter, req *http.Request, params params) {
+	p, group := s.findFileGroup(w, params)
+	if group == nil {
+		return
+	}
+	deleted := p.fileGroupView(group.ID)
+	delete(p.fileGroups, group.ID)
+	for _, r := range p.releases {
+		r.fileGroupIds = removeId(r.fileGroupIds, group.ID)
+	}
+	writeJSON(w, http.StatusOK, deleted)
+}
+
+func (s *Server) addProductFileToFileGroup(w http.ResponseWriter, req *http.Request, params params) {
+	p, group := s.findFileGroup(w, params)
+	if group == nil {
+		return
+	}
+	id, ok := s.decodeProductFileId(w, req, p)
+	if !ok {
+		return
+	}
+	group.productFileIds = addId(group.productFileIds, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listProductFiles(w http.ResponseWriter, req *http.Request, params params) {
+	p := s.product(params["slug"])
+	writeJSON(w, http.StatusOK, pivnet.ProductFilesResponse{ProductFiles: p.productFileViews(sortedIds(p.productFiles))})
+}
+
+func (s *Server) createProductFile(w http.ResponseWriter, req *http.Request, params params) {
+	var body struct {
+		ProductFile pivnet.ProductFile `json:"product_file"`
+	}
+	if !decodeBody(w, req, &body) {
+		return
+	}
+	pf := body.ProductFile
+	if pf.AWSObjectKey == "" {
+		writeError(w, http.StatusUnprocessableEntity, "aws object key can't be blank")
+		return
+	}
+
+	p := s.product(params["slug"])
+	pf.ID = s.newID()
+	pf.FileTransferStatus = FileTransferComplete
+	if s.TransferPolls > 0 {
+		pf.FileTransferStatus = FileTransferInProgress
+	}
+	p.productFiles[pf.ID] = &productFile{ProductFile: pf, transferPolls: s.TransferPolls}
+	writeJSON(w, http.StatusCreated, pivnet.ProductFileResponse{ProductFile: pf})
+}
+
+func (s *Server) getProductFile(w http.ResponseWriter, req *http.Request, params params) {
+	_, pf := s.findProductFile(w, params)
+	if pf == nil {
+		return
+	}
+	view := pf.ProductFile
+	if pf.transferPolls > 0 {
+		pf.transferPolls--
+		if pf.transferPolls == 0 {
+			pf.FileTransferStatus = FileTransferComplete
+		}
+	}
+	writeJSON(w, http.StatusOK, pivnet.ProductFileResponse{ProductFile: view})
+}
+
+func (s *Server) deleteProductFile(w http.ResponseWriter, req *http.Request, params params) {
+	p, pf := s.findProductFile(w, params)
+	if pf == nil {
+		return
+	}
+	delete(p.productFiles, pf.ID)
+	for _, group := range p.fileGroups {
+		group.productFileIds = removeId(group.productFileIds, pf.ID)
+	}
+	for _, r := range p.releases {
+		r.productFileIds = removeId(r.productFileIds, pf.ID)
+	}
+	writeJSON(w, http.StatusOK, pivnet.ProductFileResponse{ProductFile: pf.ProductFile})
+}
+
+func (s *Server) createFederationToken(w http.ResponseWriter, req *http.Request, params params) {
+	var body struct {
+		ProductID string `json:"product_id"`
+	}
+	if !decodeBody(w, req, &body) {
+		return
+	}
+	if body.ProductID == "" {
+		writeError(w, http.StatusUnprocessableEntity, "product id can't be blank")
+		return
+	}
+	writeJSON(w, http.StatusOK, pivnet.FederationToken{
+		AccessKeyID:     "fake-access-key-id",
+		SecretAccessKey: "fake-secret-access-key",
+		SessionToken:    fmt.Sprintf("fake-session-token-%d", s.newID()),
+		Bucket:          s.Bucket,
+		Region:          s.Region,
+	})
+}
+
+func (s *Server) listUserGroups(w http.ResponseWriter, req *http.Request, params params) {
+	writeJSON(w, http.StatusOK, pivnet.UserGroupsResponse{UserGroups: append([]pivnet.UserGroup{}, s.userGroups...)})
+}
+
+func (s *Server) listEULAs(w http.ResponseWriter, req *http.Request, params params) {
+	writeJSON(w, http.StatusOK, pivnet.EULAsResponse{EULAs: append([]pivnet.EULA{}, s.eulas...)})
+}
+
+func (s *Server) getEULA(w http.ResponseWriter, req *http.Request, params params) {
+	for _, eula := range s.eulas {
+		if eula.Slug == params["slug"] {
+			writeJSON(w, http.StatusOK, eula)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("eula %s can not be found", params["slug"]))
+}
+
+func (s *Server) newID() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+func (s *Server) product(slug string) *product {
+	p, ok := s.products[slug]
+	if !ok {
+		p = &product{
+			releases:     make(map[int]*release),
+			fileGroups:   make(map[int]*fileGroup),
+			productFiles: make(map[int]*productFile),
+		}
+		s.products[slug] = p
+	}
+	return p
+}
+
+func (s *Server) findRelease(w http.ResponseWriter, params params) (*product, *release) {
+	p := s.product(params["slug"])
+	r, ok := p.releases[params.id("release")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("release %s can not be found", params["release"]))
+		return p, nil
+	}
+	return p, r
+}
+
+func (s *Server) findFileGroup(w http.ResponseWriter, params params) (*product, *fileGroup) {
+	p := s.product(params["slug"])
+	group, ok := p.fileGroups[params.id("group")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("file group %s can not be found", params["group"]))
+		return p, nil
+	}
+	return p, group
+}
+
+func (s *Server) findProductFile(w http.ResponseWriter, params params) (*product, *productFile) {
+	p := s.product(params["slug"])
+	pf, ok := p.productFiles[params.id("file")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("product file %s can not be found", params["file"]))
+		return p, nil
+	}
+	return p, pf
+}
+
+func (s *Server) decodeProductFileId(w http.ResponseWriter, req *http.Request, p *product) (int, bool) {
+	var body struct {
+		ProductFile pivnet.ProductFile `json:"product_file"`
+	}
+	if !decodeBody(w, req, &body) {
+		return 0, false
+	}
+	if _, ok := p.productFiles[body.ProductFile.ID]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("product file %d can not be found", body.ProductFile.ID))
+		return 0, false
+	}
+	return body.ProductFile.ID, true
+}
+
+func (s *Server) validEULA(eula *pivnet.EULA) bool {
+	if eula == nil || eula.Slug == "" {
+		return false
+	}
+	for _, existing := range s.eulas {
+		if existing.Slug == eula.Slug {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) releaseView(p *product, id int) pivnet.Release {
+	r := p.releases[id].Release
+	if r.EULA != nil {
+		for _, eula := range s.eulas {
+			if eula.Slug == r.EULA.Slug {
+				eula := eula
+				r.EULA = &eula
+			}
+		}
+	}
+	return r
+}
+
+func (s *Server) userGroupViews(ids []int) []pivnet.UserGroup {
+	groups := []pivnet.UserGroup{}
+	for _, id := range ids {
+		for _, group := range s.userGroups {
+			if group.ID == id {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
+
+func (p *product) fileGroupView(id int) pivnet.FileGroup {
+	group := p.fileGroups[id]
+	view := group.FileGroup
+	view.ProductFiles = p.productFileViews(group.productFileIds)
+	return view
+}
+
+func (p *product) fileGroupViews(ids []int) []pivnet.FileGroup {
+	groups := []pivnet.FileGroup{}
+	for _, id := range ids {
+		if _, ok := p.fileGroups[id]; ok {
+			groups = append(groups, p.fileGroupView(id))
+		}
+	}
+	return groups
+}
+
+func (p *product) productFileViews(ids []int) []pivnet.ProductFile {
+	productFiles := []pivnet.ProductFile{}
+	for _, id := range ids {
+		if pf, ok := p.productFiles[id]; ok {
+			productFiles = append(productFiles, pf.ProductFile)
+		}
+	}
+	return productFiles
+}
+
+func validAvailability(availability string) bool {
+	for _, valid := range availabilities {
+		if availability == valid {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedIds(objects interface{}) []int {
+	var ids []int
+	switch m := objects.(type) {
+	case map[int]*release:
+		for id := range m {
+			ids = append(ids, id)
+		}
+	case map[int]*fileGroup:
+		for id := range m {
+			ids = append(ids, id)
+		}
+	case map[int]*productFile:
+		for id := range m {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func addId(ids []int, id int) []int {
+	for _, existing := range ids {
+		if existing == id {
+			return ids
+		}
+	}
+	return append(ids, id)
+}
+
+func removeId(ids []int, id int) []int {
+	var result []int
+	for _, existing := range ids {
+		if existing != id {
+			result = append(result, existing)
+		}
+	}
+	return result
+}
+
+func decodeBody(w http.ResponseWriter, req *http.Request, body interface{}) bool {
+	err := json.NewDecoder(req.Body).Decode(body)
+	if err != nil && err != io.EOF {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("can not decode request body: %s", err.Error()))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError responds in the error format of pivnet, which is different for 500
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	if statusCode == http.StatusInternalServerError {
+		writeJSON(w, statusCode, map[string]string{"error": message})
+		return
+	}
+	writeJSON(w, statusCode, map[string]interface{}{"message": message, "errors": []string{message}})
+}
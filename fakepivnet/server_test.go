@@ -0,0 +1,287 @@
+package fakepivnet_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	. "github.com/baotingfang/go-pivnet-client/fakepivnet"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	"github.com/baotingfang/go-pivnet-client/service"
+	"github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/baotingfang/go-pivnet-client/wrapper"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"github.com/pivotal-cf/go-pivnet/v4/logshim"
+)
+
+var _ = Describe("Server", func() {
+	var (
+		server *Server
+		client wrapper.PivnetClient
+	)
+
+	newClient := func(token string) wrapper.PivnetClient {
+		logger := logshim.NewLogShim(log.New(os.Stdout, "", 0), log.New(os.Stderr, "", 0), false)
+		tokenService := pivnet.NewAccessTokenOrLegacyToken(token, server.URL, false)
+		return wrapper.NewClient(tokenService, pivnet.ClientConfig{Host: server.URL}, logger)
+	}
+
+	createRelease := func(version string) pivnet.Release {
+		release, err := client.CreateRelease(ctx, pivnet.CreateReleaseConfig{
+			ProductSlug: "fakeslug",
+			Version:     version,
+			EULASlug:    DefaultEULASlug,
+			ReleaseType: "Major Release",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return release
+	}
+
+	createProductFile := func(name string) pivnet.ProductFile {
+		productFile, err := client.CreateProductFile(ctx, pivnet.CreateProductFileConfig{
+			ProductSlug:  "fakeslug",
+			Name:         name,
+			AWSObjectKey: "6.6.0/" + name,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return productFile
+	}
+
+	BeforeEach(func() {
+		server = NewServer()
+		client = newClient(server.RefreshToken)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("authentication", func() {
+		It("accepts the refresh token as a legacy api token", func() {
+			server.RefreshToken = "legacy-token"
+			client = newClient("legacy-token")
+
+			_, err := client.GetAllReleases(ctx, "fakeslug")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects an invalid api token", func() {
+			client = newClient("invalid-token")
+
+			_, err := client.GetAllReleases(ctx, "fakeslug")
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrUnauthorized{}))
+		})
+	})
+
+	Context("releases", func() {
+		It("creates, updates and deletes the release", func() {
+			release := createRelease("6.6.0")
+			Expect(release.ID).NotTo(BeZero())
+			Expect(release.Availability).To(Equal("Admins Only"))
+			Expect(release.EULA.Slug).To(Equal(DefaultEULASlug))
+
+			release.Availability = "All Users"
+			updated, err := client.UpdateRelease(ctx, "fakeslug", release)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated.Availability).To(Equal("All Users"))
+
+			releases, err := client.GetAllReleases(ctx, "fakeslug")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(releases).To(HaveLen(1))
+			Expect(releases[0].Availability).To(Equal("All Users"))
+
+			Expect(client.DeleteRelease(ctx, "fakeslug", release)).To(Succeed())
+			Expect(server.Releases("fakeslug")).To(BeEmpty())
+		})
+
+		It("rejects the release whose version is already taken", func() {
+			server.AddRelease("fakeslug", pivnet.Release{Version: "6.6.0"})
+
+			_, err := client.CreateRelease(ctx, pivnet.CreateReleaseConfig{
+				ProductSlug: "fakeslug",
+				Version:     "6.6.0",
+				EULASlug:    DefaultEULASlug,
+			})
+			Expect(err).To(MatchError(ContainSubstring("version 6.6.0 has already been taken")))
+		})
+
+		It("rejects the release whose eula can not be found", func() {
+			_, err := client.CreateRelease(ctx, pivnet.CreateReleaseConfig{
+				ProductSlug: "fakeslug",
+				Version:     "6.6.0",
+				EULASlug:    "unknown_eula",
+			})
+			Expect(err).To(MatchError(ContainSubstring("eula can not be found")))
+		})
+
+		It("returns not found for the release which does not exist", func() {
+			err := client.DeleteRelease(ctx, "fakeslug", pivnet.Release{ID: 404})
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrNotFound{}))
+		})
+	})
+
+	Context("file groups and product files", func() {
+		It("attaches the product files to the file group and release", func() {
+			release := createRelease("6.6.0")
+			group, err := client.CreateFileGroup(ctx, "fakeslug", "server")
+			Expect(err).NotTo(HaveOccurred())
+			rhel7 := createProductFile("rhel7.zip")
+			osl := createProductFile("osl.txt")
+
+			Expect(client.AddProductFileToFileGroup(ctx, "fakeslug", rhel7.ID, group.ID)).To(Succeed())
+			Expect(client.AddFileGroupToRelease(ctx, "fakeslug", group.ID, release.ID)).To(Succeed())
+			Expect(client.AddProductFileToRelease(ctx, "fakeslug", osl.ID, release.ID)).To(Succeed())
+
+			groups, err := client.GetFileGroupsForRelease(ctx, "fakeslug", release.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(HaveLen(1))
+			Expect(groups[0].Name).To(Equal("server"))
+			Expect(groups[0].ProductFiles).To(ConsistOf(rhel7))
+
+			productFiles, err := client.GetProductFilesForRelease(ctx, "fakeslug", release.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(productFiles).To(ConsistOf(osl))
+
+			_, err = client.DeleteProductFile(ctx, "fakeslug", rhel7.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReleaseFileGroups("fakeslug", release.ID)[0].ProductFiles).To(BeEmpty())
+
+			_, err = client.DeleteFileGroup(ctx, "fakeslug", group.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.ReleaseFileGroups("fakeslug", release.ID)).To(BeEmpty())
+			Expect(server.ProductFiles("fakeslug")).To(ConsistOf(osl))
+		})
+
+		It("does not attach the product file which does not exist", func() {
+			release := createRelease("6.6.0")
+
+			err := client.AddProductFileToRelease(ctx, "fakeslug", 404, release.ID)
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrNotFound{}))
+		})
+
+		It("reports the file transfer in progress for the polls", func() {
+			server.TransferPolls = 2
+			productFile := createProductFile("rhel7.zip")
+			Expect(productFile.FileTransferStatus).To(Equal(FileTransferInProgress))
+
+			var statuses []string
+			for i := 0; i < 3; i++ {
+				pf, err := client.GetProductFile(ctx, "fakeslug", productFile.ID)
+				Expect(err).NotTo(HaveOccurred())
+				statuses = append(statuses, pf.FileTransferStatus)
+			}
+			Expect(statuses).To(Equal([]string{FileTransferInProgress, FileTransferInProgress, FileTransferComplete}))
+		})
+	})
+
+	Context("user groups and eulas", func() {
+		It("adds the user group to the release", func() {
+			group := server.AddUserGroup("beta", "beta testers")
+			release := createRelease("6.6.0")
+
+			groups, err := client.GetAllUserGroups(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(ConsistOf(group))
+
+			Expect(client.AddUserGroupToRelease(ctx, "fakeslug", group.ID, release.ID)).To(Succeed())
+			groups, err = client.GetUserGroupsForRelease(ctx, "fakeslug", release.ID)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(groups).To(ConsistOf(group))
+		})
+
+		It("gets the eula by slug", func() {
+			server.AddEULA("other_eula", "Other EULA")
+
+			req, err := http.NewRequest("GET", server.URL+"/api/v2/eulas/other_eula", nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Authorization", "Bearer "+server.AccessToken)
+			resp, err := http.DefaultClient.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+
+			var eula pivnet.EULA
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			Expect(json.NewDecoder(resp.Body).Decode(&eula)).To(Succeed())
+			Expect(eula.Name).To(Equal("Other EULA"))
+		})
+	})
+
+	Context("failures", func() {
+		It("fails the requests for the times", func() {
+			server.Fail("GET", "/products/fakeslug/releases", http.StatusInternalServerError, 1)
+
+			_, err := client.GetAllReleases(ctx, "fakeslug")
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrPivnetOther{}))
+			Expect(err.(pivnet.ErrPivnetOther).ResponseCode).To(Equal(http.StatusInternalServerError))
+
+			_, err = client.GetAllReleases(ctx, "fakeslug")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(server.Requests()).To(Equal([]string{
+				"GET /products/fakeslug/releases",
+				"GET /products/fakeslug/releases",
+			}))
+		})
+	})
+
+	Context("s3", func() {
+		var store service.ObjectStore
+
+		BeforeEach(func() {
+			store = service.NewS3ObjectStore(gp.S3Config{Endpoint: server.S3Endpoint(), ForcePathStyle: true}, nil)
+		})
+
+		It("puts and deletes the object in the bucket of federation token", func() {
+			token, err := client.CreateFederationToken(ctx, "fakeslug")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(token.Bucket).To(Equal(DefaultBucket))
+
+			_, err = store.Put(ctx, token, "6.6.0/file.txt", bytes.NewReader([]byte("hello")))
+			Expect(err).NotTo(HaveOccurred())
+			content, ok := server.Object(DefaultBucket, "6.6.0/file.txt")
+			Expect(ok).To(BeTrue())
+			Expect(string(content)).To(Equal("hello"))
+
+			Expect(store.Delete(ctx, token, "6.6.0/file.txt")).To(Succeed())
+			Expect(server.ObjectKeys(DefaultBucket)).To(BeEmpty())
+		})
+
+		It("returns the last modified time of the object", func() {
+			token, err := client.CreateFederationToken(ctx, "fakeslug")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = store.Put(ctx, token, "6.6.0/file.txt", bytes.NewReader([]byte("hello")))
+			Expect(err).NotTo(HaveOccurred())
+
+			lastModified := time.Date(2020, 5, 19, 10, 0, 0, 0, time.UTC)
+			server.SetObjectLastModified(DefaultBucket, "6.6.0/file.txt", lastModified)
+			modified, err := store.LastModified(ctx, token, "6.6.0/file.txt")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(modified.Equal(lastModified)).To(BeTrue())
+
+			_, err = store.LastModified(ctx, token, "6.6.0/missing.txt")
+			Expect(err).To(BeAssignableToTypeOf(utils.NotFoundError{}))
+		})
+
+		It("puts the object in multiple parts", func() {
+			store = service.NewS3ObjectStore(gp.S3Config{
+				Endpoint:       server.S3Endpoint(),
+				ForcePathStyle: true,
+				PartSize:       s3manager.MinUploadPartSize,
+			}, nil)
+			token, err := client.CreateFederationToken(ctx, "fakeslug")
+			Expect(err).NotTo(HaveOccurred())
+
+			body := bytes.Repeat([]byte("0123456789"), int(s3manager.MinUploadPartSize)/10+1)
+			_, err = store.Put(ctx, token, "6.6.0/large.bin", bytes.NewReader(body))
+			Expect(err).NotTo(HaveOccurred())
+			content, ok := server.Object(DefaultBucket, "6.6.0/large.bin")
+			Expect(ok).To(BeTrue())
+			Expect(content).To(Equal(body))
+		})
+	})
+})
@@ -1,11 +1,19 @@
 package gp
 
 import (
+	"github.com/baotingfang/go-pivnet-client/utils"
 	"github.com/baotingfang/go-pivnet-client/wrapper"
 	"github.com/pivotal-cf/go-pivnet/v4"
 	"github.com/pivotal-cf/go-pivnet/v4/logshim"
 	"log"
 	"os"
+	"strings"
+)
+
+const (
+	EnvPivnetEndpoint     = "PIVNET_ENDPOINT"
+	EnvPivnetProductSlug  = "PIVNET_PRODUCT_SLUG"
+	EnvPivnetRefreshToken = "PIVNET_REFRESH_TOKEN"
 )
 
 type Context struct {
@@ -15,7 +23,13 @@ type Context struct {
 	UserAgent         string
 	SkipSSLValidation bool
 	Verbose           bool
+	S3                S3Config
+	Retry             wrapper.RetryPolicy
+	RequestsPerSecond float64
+	Metrics           *wrapper.Metrics
 	Client            wrapper.PivnetClient
+
+	pivnetClient wrapper.PivnetClient
 }
 
 func NewContext(pivnetBaseUrl, productSlug, uaaFreshToken string, skipSSLValidation bool, verbose bool) Context {
@@ -33,16 +47,68 @@ func NewContext(pivnetBaseUrl, productSlug, uaaFreshToken string, skipSSLValidat
 	stderrLogger := log.New(os.Stderr, "[apiClient]", log.LstdFlags)
 	logger := logshim.NewLogShim(stdoutLogger, stderrLogger, verbose)
 
-	pivnetClient := wrapper.NewClient(tokenService, pivnetConfig, logger)
-
-	return Context{
+	context := Context{
 		BaseUrl:           pivnetBaseUrl,
 		Slug:              productSlug,
 		UaaFreshToken:     uaaFreshToken,
 		UserAgent:         userAgent,
 		SkipSSLValidation: skipSSLValidation,
 		Verbose:           verbose,
+		Retry:             wrapper.DefaultRetryPolicy(),
+		RequestsPerSecond: wrapper.DefaultRequestsPerSecond,
+		Metrics:           wrapper.NewMetrics(),
+
+		pivnetClient: wrapper.NewClient(tokenService, pivnetConfig, logger),
+	}
+	context.Client = context.newClient()
+	return context
+}
 
-		Client: pivnetClient,
+// newClient decorates the pivnet client, every attempt of a call is rate limited
+// and recorded in the metrics, and the failed calls are retried by the policy.
+func (c Context) newClient() wrapper.PivnetClient {
+	if c.pivnetClient == nil {
+		return c.Client
 	}
+	client := wrapper.NewInstrumentedClient(c.pivnetClient, c.Metrics)
+	client = wrapper.NewRateLimitClient(client, c.RequestsPerSecond)
+	return wrapper.NewRetryClient(client, c.Retry)
+}
+
+// NewContextFromEnv builds a Context from the PIVNET_* environment variables,
+// which are the same ones exported by .envrc
+func NewContextFromEnv(skipSSLValidation bool, verbose bool) (Context, error) {
+	var missing []string
+
+	lookup := func(name string) string {
+		value := strings.TrimSpace(os.Getenv(name))
+		if value == "" {
+			missing = append(missing, name)
+		}
+		return value
+	}
+
+	baseUrl := lookup(EnvPivnetEndpoint)
+	slug := lookup(EnvPivnetProductSlug)
+	refreshToken := lookup(EnvPivnetRefreshToken)
+
+	if len(missing) > 0 {
+		return Context{}, utils.NewValidationError("environment variables are not set: %s", strings.Join(missing, ", "))
+	}
+
+	return NewContext(baseUrl, slug, refreshToken, skipSSLValidation, verbose), nil
+}
+
+// WithRetryPolicy returns the context whose pivnet client retries the failed calls by the policy
+func (c Context) WithRetryPolicy(policy wrapper.RetryPolicy) Context {
+	c.Retry = policy
+	c.Client = c.newClient()
+	return c
+}
+
+// WithRateLimit returns the context whose pivnet client sends at most the requests per second
+func (c Context) WithRateLimit(requestsPerSecond float64) Context {
+	c.RequestsPerSecond = requestsPerSecond
+	c.Client = c.newClient()
+	return c
 }
@@ -0,0 +1,41 @@
+package gp
+
+import (
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/baotingfang/go-pivnet-client/utils"
+	"net/url"
+)
+
+// S3Config is how the product files are uploaded to s3,
+// the zero values use the defaults of aws sdk.
+type S3Config struct {
+	// Endpoint is the url of a s3 compatible service, instead of aws s3
+	Endpoint string
+	// ForcePathStyle puts the bucket in the path of url, instead of the host name
+	ForcePathStyle bool
+	// PartSize is the size in bytes of the parts uploaded for a file
+	PartSize int64
+	// Concurrency is the number of parts uploaded at the same time for a file
+	Concurrency int
+	// MaxRetries is the max number of retries of a failed s3 request
+	MaxRetries int
+}
+
+func (c S3Config) Validate() error {
+	if c.Endpoint != "" {
+		u, err := url.Parse(c.Endpoint)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return utils.NewValidationError("s3 endpoint is not a valid url: %s", c.Endpoint)
+		}
+	}
+	if c.PartSize != 0 && c.PartSize < s3manager.MinUploadPartSize {
+		return utils.NewValidationError("s3 part size %d is less than the min part size %d", c.PartSize, s3manager.MinUploadPartSize)
+	}
+	if c.Concurrency < 0 {
+		return utils.NewValidationError("s3 concurrency %d is less than 0", c.Concurrency)
+	}
+	if c.MaxRetries < 0 {
+		return utils.NewValidationError("s3 max retries %d is less than 0", c.MaxRetries)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/api"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"strings"
+	"time"
+)
+
+// Orphans are the product files and file groups which are not attached to any release
+type Orphans struct {
+	FileGroups   []pivnet.FileGroup
+	ProductFiles []pivnet.ProductFile
+}
+
+func (o Orphans) Empty() bool {
+	return len(o.FileGroups) == 0 && len(o.ProductFiles) == 0
+}
+
+type Cleaner struct {
+	Context          gp.Context
+	Client           api.AccessClient
+	FederationTokens *FederationTokenProvider
+	ObjectStore      ObjectStore
+}
+
+func NewCleaner(context gp.Context) Cleaner {
+	client := api.NewApiClient(context)
+	federationTokens := NewFederationTokenProvider(client)
+
+	return Cleaner{
+		Context:          context,
+		Client:           client,
+		FederationTokens: federationTokens,
+		ObjectStore:      NewS3ObjectStore(context.S3, federationTokens),
+	}
+}
+
+// FindOrphans returns the file groups and product files which are not attached to any
+// release. The product files which pivnet is still transferring from s3 are being
+// uploaded, they are skipped together with their file groups.
+func (c Cleaner) FindOrphans(ctx context.Context) (Orphans, error) {
+	releases, err := c.Client.GetAllReleases(ctx)
+	if err != nil {
+		return Orphans{}, err
+	}
+
+	attachedFileGroups, attachedProductFiles, err := attachedObjects(ctx, c.Client, releases)
+	if err != nil {
+		return Orphans{}, err
+	}
+
+	allFileGroups, err := c.Client.GetAllFileGroups(ctx)
+	if err != nil {
+		return Orphans{}, err
+	}
+
+	allProductFiles, err := c.Client.GetAllProductFiles(ctx)
+	if err != nil {
+		return Orphans{}, err
+	}
+
+	transferring := make(map[int]bool)
+	var orphans Orphans
+	for _, pf := range allProductFiles {
+		if attachedProductFiles[pf.ID] {
+			continue
+		}
+		if pf.FileTransferStatus == api.FileTransferInProgress {
+			vlog.Info("skipping product file in transfer: %s (id=%d)", pf.Name, pf.ID)
+			transferring[pf.ID] = true
+			continue
+		}
+		orphans.ProductFiles = append(orphans.ProductFiles, pf)
+	}
+	for _, group := range allFileGroups {
+		if attachedFileGroups[group.ID] {
+			continue
+		}
+		if containsProductFile(group, transferring) {
+			vlog.Info("skipping file group with product files in transfer: %s (id=%d)", group.Name, group.ID)
+			continue
+		}
+		orphans.FileGroups = append(orphans.FileGroups, group)
+	}
+
+	return orphans, nil
+}
+
+func containsProductFile(group pivnet.FileGroup, ids map[int]bool) bool {
+	for _, pf := range group.ProductFiles {
+		if ids[pf.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// OlderThan keeps the orphans uploaded before the age, such as +7d. Pivnet does not
+// expose when a product file was created, so the age of a product file is the last
+// modified time of its object on s3, and the product files without objects are kept
+// out since their ages are unknown. File groups have no object, so a file group is
+// kept only when it has product files and all of them are older than the age.
+func (c Cleaner) OlderThan(ctx context.Context, orphans Orphans, age string) (Orphans, error) {
+	federationToken, err := c.FederationTokens.Token(ctx)
+	if err != nil {
+		return Orphans{}, err
+	}
+
+	now := time.Now()
+	orphanedProductFiles := make(map[int]pivnet.ProductFile)
+	for _, pf := range orphans.ProductFiles {
+		orphanedProductFiles[pf.ID] = pf
+	}
+
+	checked := make(map[int]bool)
+	isOld := func(pf pivnet.ProductFile) (bool, error) {
+		if old, ok := checked[pf.ID]; ok {
+			return old, nil
+		}
+		// the product files in file groups may not have the object keys
+		if orphaned, ok := orphanedProductFiles[pf.ID]; ok {
+			pf = orphaned
+		}
+		if Empty(pf.AWSObjectKey) {
+			vlog.Debug("age of product file %s (id=%d) is unknown, it has no aws object key", pf.Name, pf.ID)
+			checked[pf.ID] = false
+			return false, nil
+		}
+
+		lastModified, err := c.ObjectStore.LastModified(ctx, federationToken, pf.AWSObjectKey)
+		if err != nil {
+			var notFoundErr NotFoundError
+			if errors.As(err, &notFoundErr) {
+				vlog.Warn("age of product file %s (id=%d) is unknown: %s", pf.Name, pf.ID, err.Error())
+				checked[pf.ID] = false
+				return false, nil
+			}
+			return false, NewStorageError(err, "can not get the last modified time of product file %s (id=%d)", pf.Name, pf.ID)
+		}
+
+		old := !Date{Time: lastModified}.Offset(age).After(now)
+		checked[pf.ID] = old
+		return old, nil
+	}
+
+	var result Orphans
+	for _, pf := range orphans.ProductFiles {
+		old, err := isOld(pf)
+		if err != nil {
+			return Orphans{}, err
+		}
+		if old {
+			result.ProductFiles = append(result.ProductFiles, pf)
+		}
+	}
+
+	for _, group := range orphans.FileGroups {
+		allOld := len(group.ProductFiles) > 0
+		for _, pf := range group.ProductFiles {
+			old, err := isOld(pf)
+			if err != nil {
+				return Orphans{}, err
+			}
+			if !old {
+				allOld = false
+				break
+			}
+		}
+		if allOld {
+			result.FileGroups = append(result.FileGroups, group)
+		}
+	}
+
+	return result, nil
+}
+
+// Clean deletes the orphaned product files and then the orphaned file groups.
+// It tries to delete all of them, and reports all the failures at the end.
+func (c Cleaner) Clean(ctx context.Context, orphans Orphans) error {
+	var messages []string
+
+	for _, pf := range orphans.ProductFiles {
+		vlog.Info("deleting product file: %s (id=%d)", pf.Name, pf.ID)
+		_, err := c.Client.DeleteProductFile(ctx, pf.ID)
+		if err != nil {
+			messages = append(messages,
+				fmt.Sprintf("delete product file %s (id=%d) failed: %s", pf.Name, pf.ID, err.Error()))
+		}
+	}
+
+	for _, group := range orphans.FileGroups {
+		vlog.Info("deleting file group: %s (id=%d)", group.Name, group.ID)
+		_, err := c.Client.DeleteFileGroup(ctx, group.ID)
+		if err != nil {
+			messages = append(messages,
+				fmt.Sprintf("delete file group %s (id=%d) failed: %s", group.Name, group.ID, err.Error()))
+		}
+	}
+
+	if len(messages) > 0 {
+		return NewRemoteApiError(nil, "clean failed:\n%s", strings.Join(messages, "\n"))
+	}
+	return nil
+}
+
+// attachedObjects returns the ids of the file groups and product files attached to the
+// releases, the product files in the attached file groups are attached too.
+func attachedObjects(ctx context.Context, client api.AccessClient, releases []pivnet.Release) (map[int]bool, map[int]bool, error) {
+	attachedFileGroups := make(map[int]bool)
+	attachedProductFiles := make(map[int]bool)
+
+	for _, release := range releases {
+		vlog.Debug("collecting file groups and product files of release %s", release.Version)
+		fileGroups, err := client.GetFileGroupsForRelease(ctx, release.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, group := range fileGroups {
+			attachedFileGroups[group.ID] = true
+			for _, pf := range group.ProductFiles {
+				attachedProductFiles[pf.ID] = true
+			}
+		}
+
+		productFiles, err := client.GetProductFilesForRelease(ctx, release.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, pf := range productFiles {
+			attachedProductFiles[pf.ID] = true
+		}
+	}
+
+	return attachedFileGroups, attachedProductFiles, nil
+}
@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/baotingfang/go-pivnet-client/api/apifakes"
+	"github.com/baotingfang/go-pivnet-client/service/servicefakes"
+	"github.com/baotingfang/go-pivnet-client/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"time"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("Cleaner", func() {
+	var (
+		fakeClient *apifakes.FakeAccessClient
+		cleaner    Cleaner
+	)
+
+	BeforeEach(func() {
+		fakeClient = &apifakes.FakeAccessClient{}
+		cleaner = Cleaner{Client: fakeClient}
+	})
+
+	Context("FindOrphans", func() {
+		It("finds product files and file groups not attached to any release", func() {
+			fakeClient.GetAllReleasesReturns([]pivnet.Release{{ID: 1}, {ID: 2}}, nil)
+			fakeClient.GetFileGroupsForReleaseReturnsOnCall(0, []pivnet.FileGroup{
+				{ID: 10, ProductFiles: []pivnet.ProductFile{{ID: 100}}},
+			}, nil)
+			fakeClient.GetFileGroupsForReleaseReturnsOnCall(1, nil, nil)
+			fakeClient.GetProductFilesForReleaseReturnsOnCall(0, []pivnet.ProductFile{{ID: 101}}, nil)
+			fakeClient.GetProductFilesForReleaseReturnsOnCall(1, []pivnet.ProductFile{{ID: 102}}, nil)
+			fakeClient.GetAllFileGroupsReturns([]pivnet.FileGroup{{ID: 10}, {ID: 11}}, nil)
+			fakeClient.GetAllProductFilesReturns([]pivnet.ProductFile{
+				{ID: 100}, {ID: 101}, {ID: 102}, {ID: 103},
+			}, nil)
+
+			orphans, err := cleaner.FindOrphans(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphans.FileGroups).To(Equal([]pivnet.FileGroup{{ID: 11}}))
+			Expect(orphans.ProductFiles).To(Equal([]pivnet.ProductFile{{ID: 103}}))
+		})
+
+		It("skips the product files in transfer and their file groups", func() {
+			fakeClient.GetAllReleasesReturns(nil, nil)
+			fakeClient.GetAllFileGroupsReturns([]pivnet.FileGroup{
+				{ID: 10, ProductFiles: []pivnet.ProductFile{{ID: 100}}},
+				{ID: 11, ProductFiles: []pivnet.ProductFile{{ID: 101}}},
+			}, nil)
+			fakeClient.GetAllProductFilesReturns([]pivnet.ProductFile{
+				{ID: 100, FileTransferStatus: "in_progress"},
+				{ID: 101, FileTransferStatus: "complete"},
+			}, nil)
+
+			orphans, err := cleaner.FindOrphans(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(orphans.FileGroups).To(HaveLen(1))
+			Expect(orphans.FileGroups[0].ID).To(Equal(11))
+			Expect(orphans.ProductFiles).To(Equal([]pivnet.ProductFile{{ID: 101, FileTransferStatus: "complete"}}))
+		})
+
+		It("GetAllReleases failed", func() {
+			fakeClient.GetAllReleasesReturns(nil, errors.New("failed get all releases"))
+
+			_, err := cleaner.FindOrphans(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(fakeClient.GetAllProductFilesCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("OlderThan", func() {
+		var fakeObjectStore *servicefakes.FakeObjectStore
+
+		BeforeEach(func() {
+			fakeObjectStore = &servicefakes.FakeObjectStore{}
+			fakeClient.CreateFederationTokenReturns(pivnet.FederationToken{Bucket: "bucket"}, nil)
+			cleaner.FederationTokens = NewFederationTokenProvider(fakeClient)
+			cleaner.ObjectStore = fakeObjectStore
+		})
+
+		It("keeps the orphans whose objects are uploaded before the age", func() {
+			lastModified := map[string]time.Time{
+				"old":   time.Now().AddDate(0, 0, -8),
+				"new":   time.Now().AddDate(0, 0, -6),
+				"group": time.Now().AddDate(0, -1, 0),
+			}
+			fakeObjectStore.LastModifiedStub = func(_ context.Context, token pivnet.FederationToken, key string) (time.Time, error) {
+				Expect(token.Bucket).To(Equal("bucket"))
+				if t, ok := lastModified[key]; ok {
+					return t, nil
+				}
+				return time.Time{}, utils.NewNotFoundError("can not find s3 object %s", key)
+			}
+
+			orphans := Orphans{
+				ProductFiles: []pivnet.ProductFile{
+					{ID: 100, AWSObjectKey: "old"},
+					{ID: 101, AWSObjectKey: "new"},
+					{ID: 102, AWSObjectKey: "missing"},
+					{ID: 103},
+					{ID: 104, AWSObjectKey: "group"},
+				},
+				FileGroups: []pivnet.FileGroup{
+					{ID: 10},
+					{ID: 11, ProductFiles: []pivnet.ProductFile{{ID: 104}}},
+					{ID: 12, ProductFiles: []pivnet.ProductFile{{ID: 100}, {ID: 101}}},
+				},
+			}
+
+			result, err := cleaner.OlderThan(ctx, orphans, "+7d")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.ProductFiles).To(Equal([]pivnet.ProductFile{
+				{ID: 100, AWSObjectKey: "old"},
+				{ID: 104, AWSObjectKey: "group"},
+			}))
+			Expect(result.FileGroups).To(HaveLen(1))
+			Expect(result.FileGroups[0].ID).To(Equal(11))
+			Expect(fakeObjectStore.LastModifiedCallCount()).To(Equal(4))
+		})
+
+		It("returns the storage error when the object store failed", func() {
+			fakeObjectStore.LastModifiedReturns(time.Time{}, errors.New("access denied"))
+
+			_, err := cleaner.OlderThan(ctx, Orphans{ProductFiles: []pivnet.ProductFile{{ID: 100, Name: "osl", AWSObjectKey: "old"}}}, "+7d")
+			Expect(err).To(MatchError("can not get the last modified time of product file osl (id=100): access denied"))
+			Expect(err).To(BeAssignableToTypeOf(utils.StorageError{}))
+		})
+	})
+
+	Context("Clean", func() {
+		It("deletes all orphans and reports all failures", func() {
+			fakeClient.DeleteProductFileReturnsOnCall(0, pivnet.ProductFile{}, errors.New("server error"))
+
+			err := cleaner.Clean(ctx, Orphans{
+				ProductFiles: []pivnet.ProductFile{{ID: 100, Name: "a"}, {ID: 101, Name: "b"}},
+				FileGroups:   []pivnet.FileGroup{{ID: 10, Name: "g"}},
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("clean failed:\ndelete product file a (id=100) failed: server error"))
+			Expect(err).To(BeAssignableToTypeOf(utils.RemoteApiError{}))
+			Expect(fakeClient.DeleteProductFileCallCount()).To(Equal(2))
+			Expect(fakeClient.DeleteFileGroupCallCount()).To(Equal(1))
+			_, fileGroupId := fakeClient.DeleteFileGroupArgsForCall(0)
+			Expect(fileGroupId).To(Equal(10))
+		})
+	})
+})
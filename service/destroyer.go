@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/api"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"strings"
+)
+
+// DestroyPlan is the release with the file groups and product files to delete. The
+// shared file groups and product files are also attached to other releases of the
+// product, they are kept, and are only detached when the release is deleted.
+type DestroyPlan struct {
+	Release      pivnet.Release
+	FileGroups   []pivnet.FileGroup
+	ProductFiles []pivnet.ProductFile
+
+	SharedFileGroups   []pivnet.FileGroup
+	SharedProductFiles []pivnet.ProductFile
+}
+
+type Destroyer struct {
+	GpdbVersion string
+
+	Context gp.Context
+	Client  api.AccessClient
+}
+
+func NewDestroyer(context gp.Context, gpdbVersion string) Destroyer {
+	return Destroyer{
+		GpdbVersion: gpdbVersion,
+
+		Context: context,
+		Client:  api.NewApiClient(context),
+	}
+}
+
+// Plan collects the release of the gpdb version together with its file groups
+// and product files, and sets aside the ones still attached to other releases.
+func (d Destroyer) Plan(ctx context.Context) (DestroyPlan, error) {
+	release, err := d.Client.GetReleaseByVersion(ctx, d.GpdbVersion)
+	if err != nil {
+		return DestroyPlan{}, err
+	}
+
+	fileGroups, productFiles, err := releaseObjects(ctx, d.Client, release)
+	if err != nil {
+		return DestroyPlan{}, err
+	}
+
+	releases, err := d.Client.GetAllReleases(ctx)
+	if err != nil {
+		return DestroyPlan{}, err
+	}
+	var otherReleases []pivnet.Release
+	for _, r := range releases {
+		if r.ID != release.ID {
+			otherReleases = append(otherReleases, r)
+		}
+	}
+	attachedFileGroups, attachedProductFiles, err := attachedObjects(ctx, d.Client, otherReleases)
+	if err != nil {
+		return DestroyPlan{}, err
+	}
+
+	plan := DestroyPlan{Release: release}
+	for _, group := range fileGroups {
+		if attachedFileGroups[group.ID] {
+			plan.SharedFileGroups = append(plan.SharedFileGroups, group)
+		} else {
+			plan.FileGroups = append(plan.FileGroups, group)
+		}
+	}
+	for _, pf := range productFiles {
+		if attachedProductFiles[pf.ID] {
+			plan.SharedProductFiles = append(plan.SharedProductFiles, pf)
+		} else {
+			plan.ProductFiles = append(plan.ProductFiles, pf)
+		}
+	}
+
+	return plan, nil
+}
+
+// releaseObjects returns the file groups and product files of the release. The product
+// files of a file group are included, and each product file only appears once.
+func releaseObjects(ctx context.Context, client api.AccessClient, release pivnet.Release) ([]pivnet.FileGroup, []pivnet.ProductFile, error) {
+	fileGroups, err := client.GetFileGroupsForRelease(ctx, release.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	releaseProductFiles, err := client.GetProductFilesForRelease(ctx, release.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var productFiles []pivnet.ProductFile
+	seen := make(map[int]bool)
+	addProductFile := func(pf pivnet.ProductFile) {
+		if seen[pf.ID] {
+			return
+		}
+		seen[pf.ID] = true
+		productFiles = append(productFiles, pf)
+	}
+
+	for _, group := range fileGroups {
+		for _, pf := range group.ProductFiles {
+			addProductFile(pf)
+		}
+	}
+	for _, pf := range releaseProductFiles {
+		addProductFile(pf)
+	}
+
+	return fileGroups, productFiles, nil
+}
+
+// Destroy deletes the product files first, then the file groups and the release at last.
+// The shared file groups and product files are not deleted. It tries to delete all of
+// the objects, and reports all the failures at the end, the objects which are failed to
+// delete are orphaned when the release is deleted, and can be removed by clean.
+func (d Destroyer) Destroy(ctx context.Context, plan DestroyPlan) error {
+	var messages []string
+
+	for _, pf := range plan.SharedProductFiles {
+		vlog.Info("keeping product file attached to other releases: %s (id=%d)", pf.Name, pf.ID)
+	}
+	for _, group := range plan.SharedFileGroups {
+		vlog.Info("keeping file group attached to other releases: %s (id=%d)", group.Name, group.ID)
+	}
+
+	for _, pf := range plan.ProductFiles {
+		vlog.Info("deleting product file: %s (id=%d)", pf.Name, pf.ID)
+		_, err := d.Client.DeleteProductFile(ctx, pf.ID)
+		if err != nil {
+			messages = append(messages,
+				fmt.Sprintf("delete product file %s (id=%d) failed: %s", pf.Name, pf.ID, err.Error()))
+		}
+	}
+
+	for _, group := range plan.FileGroups {
+		vlog.Info("deleting file group: %s (id=%d)", group.Name, group.ID)
+		_, err := d.Client.DeleteFileGroup(ctx, group.ID)
+		if err != nil {
+			messages = append(messages,
+				fmt.Sprintf("delete file group %s (id=%d) failed: %s", group.Name, group.ID, err.Error()))
+		}
+	}
+
+	vlog.Info("deleting release: %s (id=%d)", plan.Release.Version, plan.Release.ID)
+	err := d.Client.DeleteRelease(ctx, plan.Release)
+	if err != nil {
+		messages = append(messages,
+			fmt.Sprintf("delete release %s (id=%d) failed: %s", plan.Release.Version, plan.Release.ID, err.Error()))
+	}
+
+	if len(messages) > 0 {
+		return NewRemoteApiError(nil, "destroy failed:\n%s", strings.Join(messages, "\n"))
+	}
+	return nil
+}
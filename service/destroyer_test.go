@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"errors"
+	"github.com/baotingfang/go-pivnet-client/api/apifakes"
+	"github.com/baotingfang/go-pivnet-client/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet/v4"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("Destroyer", func() {
+	var (
+		fakeClient *apifakes.FakeAccessClient
+		destroyer  Destroyer
+	)
+
+	BeforeEach(func() {
+		fakeClient = &apifakes.FakeAccessClient{}
+		destroyer = Destroyer{
+			GpdbVersion: "6.6.0",
+			Client:      fakeClient,
+		}
+	})
+
+	Context("Plan", func() {
+		It("collects file groups and product files of the release", func() {
+			fakeClient.GetReleaseByVersionReturns(pivnet.Release{ID: 10, Version: "6.6.0"}, nil)
+			fakeClient.GetFileGroupsForReleaseReturns([]pivnet.FileGroup{
+				{
+					ID:   20,
+					Name: "Greenplum Database Server",
+					ProductFiles: []pivnet.ProductFile{
+						{ID: 30, Name: "rhel6"},
+						{ID: 31, Name: "rhel7"},
+					},
+				},
+			}, nil)
+			fakeClient.GetProductFilesForReleaseReturns([]pivnet.ProductFile{
+				{ID: 31, Name: "rhel7"},
+				{ID: 32, Name: "osl"},
+			}, nil)
+
+			fakeClient.GetAllReleasesReturns([]pivnet.Release{{ID: 10, Version: "6.6.0"}}, nil)
+
+			plan, err := destroyer.Plan(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			_, version := fakeClient.GetReleaseByVersionArgsForCall(0)
+			Expect(version).To(Equal("6.6.0"))
+			_, releaseId := fakeClient.GetFileGroupsForReleaseArgsForCall(0)
+			Expect(releaseId).To(Equal(10))
+			_, releaseId = fakeClient.GetProductFilesForReleaseArgsForCall(0)
+			Expect(releaseId).To(Equal(10))
+			Expect(plan.Release.ID).To(Equal(10))
+			Expect(plan.FileGroups).To(HaveLen(1))
+			Expect(plan.ProductFiles).To(Equal([]pivnet.ProductFile{
+				{ID: 30, Name: "rhel6"},
+				{ID: 31, Name: "rhel7"},
+				{ID: 32, Name: "osl"},
+			}))
+			Expect(plan.SharedFileGroups).To(BeEmpty())
+			Expect(plan.SharedProductFiles).To(BeEmpty())
+		})
+
+		It("keeps the file groups and product files attached to other releases", func() {
+			fakeClient.GetReleaseByVersionReturns(pivnet.Release{ID: 10, Version: "6.6.0"}, nil)
+			fakeClient.GetAllReleasesReturns([]pivnet.Release{{ID: 10, Version: "6.6.0"}, {ID: 11, Version: "6.7.0"}}, nil)
+			fakeClient.GetFileGroupsForReleaseReturnsOnCall(0, []pivnet.FileGroup{
+				{ID: 20, Name: "server", ProductFiles: []pivnet.ProductFile{{ID: 30, Name: "rhel7"}}},
+				{ID: 21, Name: "clients", ProductFiles: []pivnet.ProductFile{{ID: 31, Name: "clients"}}},
+			}, nil)
+			fakeClient.GetProductFilesForReleaseReturnsOnCall(0, []pivnet.ProductFile{{ID: 32, Name: "osl"}, {ID: 33, Name: "notes"}}, nil)
+			fakeClient.GetFileGroupsForReleaseReturnsOnCall(1, []pivnet.FileGroup{
+				{ID: 21, Name: "clients", ProductFiles: []pivnet.ProductFile{{ID: 31, Name: "clients"}}},
+			}, nil)
+			fakeClient.GetProductFilesForReleaseReturnsOnCall(1, []pivnet.ProductFile{{ID: 32, Name: "osl"}}, nil)
+
+			plan, err := destroyer.Plan(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.GetFileGroupsForReleaseCallCount()).To(Equal(2))
+			_, releaseId := fakeClient.GetFileGroupsForReleaseArgsForCall(1)
+			Expect(releaseId).To(Equal(11))
+			Expect(plan.FileGroups).To(HaveLen(1))
+			Expect(plan.FileGroups[0].ID).To(Equal(20))
+			Expect(plan.SharedFileGroups).To(HaveLen(1))
+			Expect(plan.SharedFileGroups[0].ID).To(Equal(21))
+			Expect(plan.ProductFiles).To(Equal([]pivnet.ProductFile{{ID: 30, Name: "rhel7"}, {ID: 33, Name: "notes"}}))
+			Expect(plan.SharedProductFiles).To(Equal([]pivnet.ProductFile{{ID: 31, Name: "clients"}, {ID: 32, Name: "osl"}}))
+		})
+
+		It("release does not exist", func() {
+			fakeClient.GetReleaseByVersionReturns(pivnet.Release{}, errors.New("can not find release. version: 6.6.0"))
+
+			_, err := destroyer.Plan(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("can not find release. version: 6.6.0"))
+			Expect(fakeClient.GetFileGroupsForReleaseCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("Destroy", func() {
+		var plan DestroyPlan
+
+		BeforeEach(func() {
+			plan = DestroyPlan{
+				Release:    pivnet.Release{ID: 10, Version: "6.6.0"},
+				FileGroups: []pivnet.FileGroup{{ID: 20, Name: "server"}},
+				ProductFiles: []pivnet.ProductFile{
+					{ID: 30, Name: "rhel6"},
+					{ID: 32, Name: "osl"},
+				},
+				SharedFileGroups:   []pivnet.FileGroup{{ID: 21, Name: "clients"}},
+				SharedProductFiles: []pivnet.ProductFile{{ID: 31, Name: "clients"}},
+			}
+		})
+
+		It("deletes product files, file groups and the release", func() {
+			err := destroyer.Destroy(ctx, plan)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.DeleteProductFileCallCount()).To(Equal(2))
+			_, productFileId := fakeClient.DeleteProductFileArgsForCall(0)
+			Expect(productFileId).To(Equal(30))
+			_, productFileId = fakeClient.DeleteProductFileArgsForCall(1)
+			Expect(productFileId).To(Equal(32))
+			Expect(fakeClient.DeleteFileGroupCallCount()).To(Equal(1))
+			_, fileGroupId := fakeClient.DeleteFileGroupArgsForCall(0)
+			Expect(fileGroupId).To(Equal(20))
+			Expect(fakeClient.DeleteReleaseCallCount()).To(Equal(1))
+			_, release := fakeClient.DeleteReleaseArgsForCall(0)
+			Expect(release.ID).To(Equal(10))
+		})
+
+		It("deletes the other objects and reports all the failures", func() {
+			fakeClient.DeleteProductFileReturnsOnCall(0, pivnet.ProductFile{}, errors.New("server error"))
+			fakeClient.DeleteReleaseReturns(errors.New("release error"))
+
+			err := destroyer.Destroy(ctx, plan)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("destroy failed:\n" +
+				"delete product file rhel6 (id=30) failed: server error\n" +
+				"delete release 6.6.0 (id=10) failed: release error"))
+			Expect(err).To(BeAssignableToTypeOf(utils.RemoteApiError{}))
+			Expect(fakeClient.DeleteProductFileCallCount()).To(Equal(2))
+			Expect(fakeClient.DeleteFileGroupCallCount()).To(Equal(1))
+			Expect(fakeClient.DeleteReleaseCallCount()).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/baotingfang/go-pivnet-client/api"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"sync"
+	"time"
+)
+
+const (
+	FederationTokenProviderName = "PivnetFederationTokenProvider"
+
+	// pivnet does not return the expiration of the federation token,
+	// it is assumed to be valid for an hour.
+	DefaultFederationTokenTTL           = time.Hour
+	DefaultFederationTokenRefreshBefore = 5 * time.Minute
+)
+
+// FederationTokenProvider requests the federation token from pivnet, and requests
+// a new one before it expires. It is also an aws credentials provider, aws sdk
+// retrieves the credentials again when s3 rejects a request with ExpiredToken,
+// then the request is retried with the new token.
+type FederationTokenProvider struct {
+	Client        api.AccessClient
+	TTL           time.Duration
+	RefreshBefore time.Duration
+
+	mu         sync.Mutex
+	token      pivnet.FederationToken
+	expiration time.Time
+	retrieved  bool
+}
+
+func NewFederationTokenProvider(client api.AccessClient) *FederationTokenProvider {
+	return &FederationTokenProvider{
+		Client:        client,
+		TTL:           DefaultFederationTokenTTL,
+		RefreshBefore: DefaultFederationTokenRefreshBefore,
+	}
+}
+
+// Token returns the current federation token, a new one is requested when
+// there is no token yet or the current one is about to expire.
+func (p *FederationTokenProvider) Token(ctx context.Context) (pivnet.FederationToken, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.expiringLocked() {
+		if err := p.refreshLocked(ctx); err != nil {
+			return pivnet.FederationToken{}, err
+		}
+	}
+	return p.token, nil
+}
+
+// Retrieve implements credentials.Provider
+func (p *FederationTokenProvider) Retrieve() (credentials.Value, error) {
+	return p.RetrieveWithContext(context.Background())
+}
+
+// RetrieveWithContext implements credentials.ProviderWithContext, ctx is the context of
+// the s3 request. The current token is used for the first time, after that it is only
+// called when the credentials are expired or rejected, so a new token is always requested.
+func (p *FederationTokenProvider) RetrieveWithContext(ctx credentials.Context) (credentials.Value, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.retrieved || p.expiringLocked() {
+		if err := p.refreshLocked(ctx); err != nil {
+			return credentials.Value{ProviderName: FederationTokenProviderName}, err
+		}
+	}
+	p.retrieved = true
+
+	return credentials.Value{
+		AccessKeyID:     p.token.AccessKeyID,
+		SecretAccessKey: p.token.SecretAccessKey,
+		SessionToken:    p.token.SessionToken,
+		ProviderName:    FederationTokenProviderName,
+	}, nil
+}
+
+// IsExpired implements credentials.Provider
+func (p *FederationTokenProvider) IsExpired() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.expiringLocked()
+}
+
+func (p *FederationTokenProvider) expiringLocked() bool {
+	return time.Now().Add(p.RefreshBefore).After(p.expiration)
+}
+
+func (p *FederationTokenProvider) refreshLocked(ctx context.Context) error {
+	token, err := p.Client.CreateFederationToken(ctx)
+	if err != nil {
+		return err
+	}
+	p.token = token
+	p.expiration = time.Now().Add(p.TTL)
+	return nil
+}
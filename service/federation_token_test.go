@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/api/apifakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"time"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("FederationTokenProvider", func() {
+	var (
+		fakeClient *apifakes.FakeAccessClient
+		provider   *FederationTokenProvider
+	)
+
+	BeforeEach(func() {
+		fakeClient = &apifakes.FakeAccessClient{}
+		fakeClient.CreateFederationTokenStub = func(context.Context) (pivnet.FederationToken, error) {
+			n := fakeClient.CreateFederationTokenCallCount()
+			return pivnet.FederationToken{
+				AccessKeyID: fmt.Sprintf("key%d", n),
+				Bucket:      "fakebucket",
+			}, nil
+		}
+		provider = NewFederationTokenProvider(fakeClient)
+	})
+
+	It("reuses the token before it expires", func() {
+		token, err := provider.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessKeyID).To(Equal("key1"))
+
+		token, err = provider.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessKeyID).To(Equal("key1"))
+		Expect(provider.IsExpired()).To(BeFalse())
+		Expect(fakeClient.CreateFederationTokenCallCount()).To(Equal(1))
+	})
+
+	It("requests a new token before the token expires", func() {
+		provider.TTL = 10 * time.Millisecond
+		provider.RefreshBefore = 5 * time.Millisecond
+
+		_, err := provider.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(provider.IsExpired).Should(BeTrue())
+
+		token, err := provider.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessKeyID).To(Equal("key2"))
+	})
+
+	It("uses the current token for the first retrieve, and a new one after that", func() {
+		_, err := provider.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		value, err := provider.Retrieve()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value.AccessKeyID).To(Equal("key1"))
+		Expect(value.ProviderName).To(Equal(FederationTokenProviderName))
+
+		value, err = provider.Retrieve()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value.AccessKeyID).To(Equal("key2"))
+
+		token, err := provider.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(token.AccessKeyID).To(Equal("key2"))
+	})
+
+	It("returns error when the token can not be requested", func() {
+		fakeClient.CreateFederationTokenStub = nil
+		fakeClient.CreateFederationTokenReturns(pivnet.FederationToken{}, errors.New("server error"))
+
+		_, err := provider.Token(ctx)
+		Expect(err).To(MatchError("server error"))
+		_, err = provider.Retrieve()
+		Expect(err).To(MatchError("server error"))
+	})
+})
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/api"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"time"
+)
+
+const (
+	DefaultFileTransferInterval    = 5 * time.Second
+	DefaultFileTransferMaxInterval = time.Minute
+	DefaultFileTransferTimeout     = 30 * time.Minute
+)
+
+// FileTransferWait is how to poll the file transfer status of a product file,
+// the interval is doubled after every poll until it reaches the max interval.
+type FileTransferWait struct {
+	Interval    time.Duration
+	MaxInterval time.Duration
+	Timeout     time.Duration
+}
+
+func NewFileTransferWait() FileTransferWait {
+	return FileTransferWait{
+		Interval:    DefaultFileTransferInterval,
+		MaxInterval: DefaultFileTransferMaxInterval,
+		Timeout:     DefaultFileTransferTimeout,
+	}
+}
+
+// WaitFor polls the product file until pivnet has completed the file transfer from s3,
+// or ctx is done
+func (w FileTransferWait) WaitFor(ctx context.Context, client api.AccessClient, productFileId int) error {
+	w = w.withDefaults()
+
+	deadline := time.Now().Add(w.Timeout)
+	interval := w.Interval
+	for {
+		inProgress, err := client.FileTransferStatusInProgress(ctx, productFileId)
+		if err != nil {
+			return fmt.Errorf("can not get file transfer status of product file (id=%d): %w", productFileId, err)
+		}
+		if !inProgress {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return NewTimeoutError("file transfer of product file (id=%d) is still in progress after %s", productFileId, w.Timeout)
+		}
+		if interval > remaining {
+			interval = remaining
+		}
+
+		vlog.Debug("file transfer of product file (id=%d) is in progress, check again after %s", productFileId, interval)
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		interval *= 2
+		if interval > w.MaxInterval {
+			interval = w.MaxInterval
+		}
+	}
+}
+
+func (w FileTransferWait) withDefaults() FileTransferWait {
+	if w.Interval <= 0 {
+		w.Interval = DefaultFileTransferInterval
+	}
+	if w.MaxInterval <= 0 {
+		w.MaxInterval = DefaultFileTransferMaxInterval
+	}
+	if w.MaxInterval < w.Interval {
+		w.MaxInterval = w.Interval
+	}
+	if w.Timeout <= 0 {
+		w.Timeout = DefaultFileTransferTimeout
+	}
+	return w
+}
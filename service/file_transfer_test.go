@@ -0,0 +1,58 @@
+package service_test
+
+import (
+	"errors"
+	"github.com/baotingfang/go-pivnet-client/api/apifakes"
+	"github.com/baotingfang/go-pivnet-client/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("FileTransferWait", func() {
+	var (
+		fakeClient *apifakes.FakeAccessClient
+		wait       FileTransferWait
+	)
+
+	BeforeEach(func() {
+		fakeClient = &apifakes.FakeAccessClient{}
+		wait = FileTransferWait{
+			Interval:    time.Millisecond,
+			MaxInterval: 4 * time.Millisecond,
+			Timeout:     time.Second,
+		}
+	})
+
+	It("returns when the file transfer is completed", func() {
+		fakeClient.FileTransferStatusInProgressReturnsOnCall(0, true, nil)
+		fakeClient.FileTransferStatusInProgressReturnsOnCall(1, true, nil)
+		fakeClient.FileTransferStatusInProgressReturnsOnCall(2, false, nil)
+
+		err := wait.WaitFor(ctx, fakeClient, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.FileTransferStatusInProgressCallCount()).To(Equal(3))
+		_, productFileId := fakeClient.FileTransferStatusInProgressArgsForCall(2)
+		Expect(productFileId).To(Equal(10))
+	})
+
+	It("returns error when the status can not be got", func() {
+		fakeClient.FileTransferStatusInProgressReturns(false, errors.New("server error"))
+
+		err := wait.WaitFor(ctx, fakeClient, 10)
+		Expect(err).To(MatchError("can not get file transfer status of product file (id=10): server error"))
+	})
+
+	It("returns error when the file transfer is not completed before timeout", func() {
+		fakeClient.FileTransferStatusInProgressReturns(true, nil)
+		wait.Timeout = 20 * time.Millisecond
+
+		err := wait.WaitFor(ctx, fakeClient, 10)
+		Expect(err).To(MatchError("file transfer of product file (id=10) is still in progress after 20ms"))
+		Expect(err).To(BeAssignableToTypeOf(utils.TimeoutError{}))
+		// 1ms, 2ms, then 4ms until timeout
+		Expect(fakeClient.FileTransferStatusInProgressCallCount()).To(BeNumerically("<=", 8))
+	})
+})
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"fmt"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"sync"
+)
+
+type JournalEntryKind int
+
+const (
+	JournalEntryRelease JournalEntryKind = iota
+	JournalEntryFileGroup
+	JournalEntryProductFile
+	JournalEntryS3Object
+)
+
+type JournalEntry struct {
+	Kind JournalEntryKind
+	ID   int
+	Name string
+
+	Release pivnet.Release
+	Bucket  string
+	Key     string
+}
+
+func (e JournalEntry) String() string {
+	switch e.Kind {
+	case JournalEntryRelease:
+		return fmt.Sprintf("release %s (id=%d)", e.Name, e.ID)
+	case JournalEntryFileGroup:
+		return fmt.Sprintf("file group %s (id=%d)", e.Name, e.ID)
+	case JournalEntryProductFile:
+		return fmt.Sprintf("product file %s (id=%d)", e.Name, e.ID)
+	case JournalEntryS3Object:
+		return fmt.Sprintf("s3 object s3://%s/%s", e.Bucket, e.Key)
+	default:
+		return fmt.Sprintf("unknown journal entry kind: %d", e.Kind)
+	}
+}
+
+// RollbackStatus is what has been done to the created objects after the upload failed
+type RollbackStatus int
+
+const (
+	// RollbackNotRun is the status when the upload has not failed
+	RollbackNotRun RollbackStatus = iota
+	// RollbackDisabled is the status when the rollback is disabled by the user
+	RollbackDisabled
+	// RollbackSkipped is the status when the upload progress is saved for resume
+	RollbackSkipped
+	RolledBack
+	RollbackFailed
+)
+
+// Journal records every remote object created by the uploader, so that
+// they can be removed in the reverse order when the upload failed, and
+// records the result of the rollback.
+// All methods are safe to be called on a nil Journal, which records nothing.
+type Journal struct {
+	mu      sync.Mutex
+	entries []JournalEntry
+
+	rollbackStatus   RollbackStatus
+	rollbackFailures []JournalEntry
+}
+
+func NewJournal() *Journal {
+	return &Journal{}
+}
+
+func (j *Journal) RecordRelease(release pivnet.Release) {
+	j.record(JournalEntry{
+		Kind:    JournalEntryRelease,
+		ID:      release.ID,
+		Name:    release.Version,
+		Release: release,
+	})
+}
+
+func (j *Journal) RecordFileGroup(fileGroup pivnet.FileGroup) {
+	j.record(JournalEntry{
+		Kind: JournalEntryFileGroup,
+		ID:   fileGroup.ID,
+		Name: fileGroup.Name,
+	})
+}
+
+func (j *Journal) RecordProductFile(productFile pivnet.ProductFile) {
+	j.record(JournalEntry{
+		Kind: JournalEntryProductFile,
+		ID:   productFile.ID,
+		Name: productFile.Name,
+	})
+}
+
+func (j *Journal) RecordS3Object(bucket, key string) {
+	j.record(JournalEntry{
+		Kind:   JournalEntryS3Object,
+		Bucket: bucket,
+		Key:    key,
+	})
+}
+
+func (j *Journal) record(entry JournalEntry) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, entry)
+}
+
+// Entries returns the recorded entries in the order they were created
+func (j *Journal) Entries() []JournalEntry {
+	if j == nil {
+		return nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]JournalEntry{}, j.entries...)
+}
+
+// RecordRollback records the result of the rollback, and the entries which are failed to remove
+func (j *Journal) RecordRollback(status RollbackStatus, failures []JournalEntry) {
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.rollbackStatus = status
+	j.rollbackFailures = append([]JournalEntry{}, failures...)
+}
+
+// Rollback returns the result of the rollback, and the entries which are failed to remove
+func (j *Journal) Rollback() (RollbackStatus, []JournalEntry) {
+	if j == nil {
+		return RollbackNotRun, nil
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.rollbackStatus, append([]JournalEntry{}, j.rollbackFailures...)
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/config"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// the placeholders can be used in the aws_object_key template of metadata or product file
+const (
+	PlaceholderProductSlug  = "${PRODUCT_SLUG}"
+	PlaceholderGpdbVersion  = "${GPDB_VERSION}"
+	PlaceholderFileGroup    = "${FILE_GROUP}"
+	PlaceholderFileName     = "${FILE_NAME}"
+	PlaceholderVersionRegex = "${VERSION_REGEX}"
+
+	DefaultAwsObjectKeyTemplate = PlaceholderFileName
+)
+
+var placeholderRegexp = regexp.MustCompile(`\$\{[^}]*}`)
+
+// awsObjectKeyTemplate returns the template of the product file, or the template in metadata
+func (u Uploader) awsObjectKeyTemplate(productFile config.ProductFile) string {
+	if !Empty(productFile.AWSObjectKey) {
+		return productFile.AWSObjectKey
+	}
+	if !Empty(u.Metadata.AwsObjectKey) {
+		return u.Metadata.AwsObjectKey
+	}
+	return DefaultAwsObjectKeyTemplate
+}
+
+// awsObjectKey expands the aws object key template of the product file in the file group,
+// the group name is empty for the product files of the release.
+func (u Uploader) awsObjectKey(groupName string, productFile config.ProductFile, rv ResolvedFile) (string, error) {
+	template := u.awsObjectKeyTemplate(productFile)
+
+	if strings.Contains(template, PlaceholderVersionRegex) && rv.ResolvedVersion.Empty() {
+		return "", NewValidationError("can not expand %s in aws object key %s, no version is resolved from file %s",
+			PlaceholderVersionRegex, template, rv.LocalFilePath)
+	}
+	if strings.Contains(template, PlaceholderFileGroup) && Empty(groupName) {
+		return "", NewValidationError("can not expand %s in aws object key %s, file %s is not in a file group",
+			PlaceholderFileGroup, template, productFile.File)
+	}
+
+	replacer := strings.NewReplacer(
+		PlaceholderProductSlug, u.Context.Slug,
+		PlaceholderGpdbVersion, u.GpdbVersion,
+		PlaceholderFileGroup, groupName,
+		PlaceholderFileName, rv.LocalFileName,
+		PlaceholderVersionRegex, rv.ResolvedVersion.AsString(),
+	)
+	key := replacer.Replace(template)
+
+	if unknown := placeholderRegexp.FindString(key); unknown != "" {
+		return "", NewValidationError("not support placeholder %s in aws object key %s", unknown, template)
+	}
+
+	key = strings.TrimPrefix(path.Join(u.AwsObjectPrefix, key), "/")
+	if Empty(key) || key == "." || strings.HasSuffix(template, "/") {
+		return "", NewValidationError("aws object key %s of file %s is not a valid object key", template, productFile.File)
+	}
+	return key, nil
+}
+
+// validateAwsObjectKeys checks that no two product files of the release are uploaded to the same key
+func (u Uploader) validateAwsObjectKeys() error {
+	owners := make(map[string]string)
+	var duplicates []string
+
+	check := func(groupName string, productFile config.ProductFile) error {
+		rv, err := u.Resolver.Resolve(productFile.File)
+		if err != nil {
+			return err
+		}
+		key, err := u.awsObjectKey(groupName, productFile, rv)
+		if err != nil {
+			return err
+		}
+
+		owner := path.Join(groupName, productFile.UploadAs)
+		if previous, ok := owners[key]; ok {
+			duplicates = append(duplicates,
+				fmt.Sprintf("%s: %s and %s", key, previous, owner))
+			return nil
+		}
+		owners[key] = owner
+		return nil
+	}
+
+	for _, group := range u.Metadata.FileGroups {
+		for _, productFile := range group.ProductFiles {
+			if err := check(group.Name, productFile); err != nil {
+				return err
+			}
+		}
+	}
+	for _, productFile := range u.Metadata.ProductFiles {
+		if err := check("", productFile); err != nil {
+			return err
+		}
+	}
+
+	if len(duplicates) > 0 {
+		return NewConflictError("product files are uploaded to the same aws object key:\n%s", strings.Join(duplicates, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"github.com/baotingfang/go-pivnet-client/api/apifakes"
+	"github.com/baotingfang/go-pivnet-client/config"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"strings"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("AwsObjectKey", func() {
+	var uploader Uploader
+
+	BeforeEach(func() {
+		metadata, err := config.MetadataFrom(strings.NewReader(metadataYaml), "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+
+		uploader = Uploader{
+			GpdbVersion: "6.6.0",
+			Metadata:    metadata,
+			Context:     gp.Context{Slug: "pivotal-gpdb"},
+			Client:      &apifakes.FakeAccessClient{},
+			Resolver:    newFakeResolver("/tmp/path/file.txt"),
+		}
+	})
+
+	awsObjectKeys := func(plan UploadPlan) []string {
+		var keys []string
+		for _, group := range plan.FileGroups {
+			for _, pf := range group.ProductFiles {
+				keys = append(keys, pf.AwsObjectKey)
+			}
+		}
+		for _, pf := range plan.ProductFiles {
+			keys = append(keys, pf.AwsObjectKey)
+		}
+		return keys
+	}
+
+	It("uploads to the file name by default", func() {
+		plan, err := uploader.Plan(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(awsObjectKeys(plan)).To(Equal([]string{
+			"server-rhel6.txt",
+			"server-rhel7.txt",
+			"gpdb-osl.txt",
+			"pl-extensions-osl.txt",
+		}))
+	})
+
+	It("expands the placeholders of the template in metadata", func() {
+		uploader.Metadata.AwsObjectKey = "/${PRODUCT_SLUG}/${GPDB_VERSION}/${VERSION_REGEX}/${FILE_NAME}"
+
+		plan, err := uploader.Plan(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(awsObjectKeys(plan)).To(Equal([]string{
+			"pivotal-gpdb/6.6.0/6.6.0/server-rhel6.txt",
+			"pivotal-gpdb/6.6.0/6.6.0/server-rhel7.txt",
+			"pivotal-gpdb/6.6.0/6.6.0/gpdb-osl.txt",
+			"pivotal-gpdb/6.6.0/6.6.0/pl-extensions-osl.txt",
+		}))
+		Expect(plan.FileGroups[0].ProductFiles[0].ProductFile.AWSObjectKey).To(Equal("pivotal-gpdb/6.6.0/6.6.0/server-rhel6.txt"))
+	})
+
+	It("the template of product file overrides the template in metadata", func() {
+		uploader.Metadata.AwsObjectKey = "${GPDB_VERSION}/${FILE_NAME}"
+		uploader.Metadata.FileGroups[0].ProductFiles[0].AWSObjectKey = "${FILE_GROUP}/rhel6.rpm"
+		uploader.Metadata.ProductFiles[0].AWSObjectKey = "osl/${FILE_NAME}"
+
+		plan, err := uploader.Plan(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(awsObjectKeys(plan)).To(Equal([]string{
+			"Greenplum Database Server/rhel6.rpm",
+			"6.6.0/server-rhel7.txt",
+			"osl/gpdb-osl.txt",
+			"6.6.0/pl-extensions-osl.txt",
+		}))
+	})
+
+	It("unknown placeholder", func() {
+		uploader.Metadata.AwsObjectKey = "${GPDB_VERSION}/${PLATFORM}/${FILE_NAME}"
+
+		_, err := uploader.Plan(ctx)
+		Expect(err).To(MatchError("not support placeholder ${PLATFORM} in aws object key ${GPDB_VERSION}/${PLATFORM}/${FILE_NAME}"))
+	})
+
+	It("file group placeholder for the product file not in a file group", func() {
+		uploader.Metadata.AwsObjectKey = "${FILE_GROUP}/${FILE_NAME}"
+
+		_, err := uploader.Plan(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("can not expand ${FILE_GROUP} in aws object key ${FILE_GROUP}/${FILE_NAME}, file file://gpdb-osl/"))
+	})
+
+	It("template is a directory", func() {
+		uploader.Metadata.AwsObjectKey = "${GPDB_VERSION}/"
+
+		_, err := uploader.Plan(ctx)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("aws object key ${GPDB_VERSION}/ of file"))
+	})
+
+	It("two product files have the same aws object key", func() {
+		uploader.Metadata.AwsObjectKey = "${GPDB_VERSION}/installer.rpm"
+		uploader.Metadata.ProductFiles[0].AWSObjectKey = "osl.txt"
+		uploader.Metadata.ProductFiles[1].AWSObjectKey = "pl-osl.txt"
+
+		_, err := uploader.Plan(ctx)
+		Expect(err).To(MatchError("product files are uploaded to the same aws object key:\n" +
+			"6.6.0/installer.rpm: Greenplum Database Server/Greenplum Database ${VERSION_REGEX} Installer for RHEL 6 " +
+			"and Greenplum Database Server/Greenplum Database ${VERSION_REGEX} Installer for RHEL 7"))
+	})
+})
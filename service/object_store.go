@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+//go:generate counterfeiter . ObjectStore
+
+// ObjectStore is where the product files are uploaded to, the bucket and
+// the credentials are in the federation token generated by pivnet.
+type ObjectStore interface {
+	Put(ctx context.Context, federationToken pivnet.FederationToken, key string, body io.Reader) (location string, err error)
+	Delete(ctx context.Context, federationToken pivnet.FederationToken, key string) error
+	// LastModified returns when the object was uploaded, or a NotFoundError when it does not exist
+	LastModified(ctx context.Context, federationToken pivnet.FederationToken, key string) (time.Time, error)
+}
+
+type S3ObjectStore struct {
+	Config gp.S3Config
+
+	// credentials is shared by all requests, so that the federation token is only
+	// refreshed once when it expires. The credentials of the federation token passed
+	// to Put and Delete are used when it is nil.
+	credentials *credentials.Credentials
+}
+
+func NewS3ObjectStore(config gp.S3Config, tokens *FederationTokenProvider) ObjectStore {
+	store := S3ObjectStore{Config: config}
+	if tokens != nil {
+		store.credentials = credentials.NewCredentials(tokens)
+	}
+	return store
+}
+
+func (s S3ObjectStore) Put(ctx context.Context, federationToken pivnet.FederationToken, key string, body io.Reader) (string, error) {
+	uploader := s3manager.NewUploader(s.newSession(federationToken), func(u *s3manager.Uploader) {
+		if s.Config.PartSize > 0 {
+			u.PartSize = s.Config.PartSize
+		}
+		if s.Config.Concurrency > 0 {
+			u.Concurrency = s.Config.Concurrency
+		}
+	})
+	result, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(federationToken.Bucket),
+		Key:    aws.String(key),
+		Body:   body,
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.Location, nil
+}
+
+func (s S3ObjectStore) Delete(ctx context.Context, federationToken pivnet.FederationToken, key string) error {
+	_, err := s3.New(s.newSession(federationToken)).DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(federationToken.Bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s S3ObjectStore) LastModified(ctx context.Context, federationToken pivnet.FederationToken, key string) (time.Time, error) {
+	output, err := s3.New(s.newSession(federationToken)).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(federationToken.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if failure, ok := err.(awserr.RequestFailure); ok && failure.StatusCode() == http.StatusNotFound {
+			return time.Time{}, NewNotFoundError("can not find s3 object %s", key)
+		}
+		return time.Time{}, err
+	}
+	return aws.TimeValue(output.LastModified), nil
+}
+
+func (s S3ObjectStore) newSession(federationToken pivnet.FederationToken) *session.Session {
+	creds := s.credentials
+	if creds == nil {
+		creds = credentials.NewStaticCredentials(
+			federationToken.AccessKeyID,
+			federationToken.SecretAccessKey,
+			federationToken.SessionToken,
+		)
+	}
+
+	config := &aws.Config{
+		Region:           aws.String(federationToken.Region),
+		Credentials:      creds,
+		S3ForcePathStyle: aws.Bool(s.Config.ForcePathStyle),
+	}
+	if s.Config.Endpoint != "" {
+		config.Endpoint = aws.String(s.Config.Endpoint)
+	}
+	if s.Config.MaxRetries > 0 {
+		config.MaxRetries = aws.Int(s.Config.MaxRetries)
+	}
+	return session.Must(session.NewSession(config))
+}
+
+// LocalObjectStore saves the objects to <root>/<bucket>/<key> on local filesystem,
+// it is used to run the upload without s3.
+type LocalObjectStore struct {
+	Root string
+}
+
+func NewLocalObjectStore(root string) ObjectStore {
+	return LocalObjectStore{Root: root}
+}
+
+func (s LocalObjectStore) Put(ctx context.Context, federationToken pivnet.FederationToken, key string, body io.Reader) (string, error) {
+	objectPath := s.objectPath(federationToken, key)
+	if err := os.MkdirAll(filepath.Dir(objectPath), 0755); err != nil {
+		return "", err
+	}
+
+	f, err := os.Create(objectPath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, contextReader{ctx: ctx, reader: body}); err != nil {
+		_ = f.Close()
+		return "", NewStorageError(err, "can not write %s", objectPath)
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+	return "file://" + objectPath, nil
+}
+
+func (s LocalObjectStore) Delete(ctx context.Context, federationToken pivnet.FederationToken, key string) error {
+	err := os.Remove(s.objectPath(federationToken, key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s LocalObjectStore) LastModified(ctx context.Context, federationToken pivnet.FederationToken, key string) (time.Time, error) {
+	info, err := os.Stat(s.objectPath(federationToken, key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, NewNotFoundError("can not find object %s", key)
+		}
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
+
+func (s LocalObjectStore) objectPath(federationToken pivnet.FederationToken, key string) string {
+	return filepath.Join(s.Root, federationToken.Bucket, filepath.FromSlash(key))
+}
+
+// contextReader stops reading when ctx is done
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
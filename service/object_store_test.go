@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/api/apifakes"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("LocalObjectStore", func() {
+	var (
+		root  string
+		store ObjectStore
+		token pivnet.FederationToken
+	)
+
+	BeforeEach(func() {
+		var err error
+		root, err = ioutil.TempDir("", "object-store-test")
+		Expect(err).NotTo(HaveOccurred())
+		store = NewLocalObjectStore(root)
+		token = pivnet.FederationToken{Bucket: "fakebucket"}
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(root)
+	})
+
+	It("puts the object to the bucket directory", func() {
+		location, err := store.Put(ctx, token, "6.6.0/file.txt", strings.NewReader("hello"))
+		Expect(err).NotTo(HaveOccurred())
+
+		objectPath := filepath.Join(root, "fakebucket", "6.6.0", "file.txt")
+		Expect(location).To(Equal("file://" + objectPath))
+		content, err := ioutil.ReadFile(objectPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("hello"))
+	})
+
+	It("deletes the object", func() {
+		_, err := store.Put(ctx, token, "file.txt", strings.NewReader("hello"))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(store.Delete(ctx, token, "file.txt")).To(Succeed())
+		_, err = os.Stat(filepath.Join(root, "fakebucket", "file.txt"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		By("deleting the object which does not exist")
+		Expect(store.Delete(ctx, token, "file.txt")).To(Succeed())
+	})
+})
+
+var _ = Describe("S3ObjectStore", func() {
+	var (
+		server *ghttp.Server
+		token  pivnet.FederationToken
+	)
+
+	BeforeEach(func() {
+		server = ghttp.NewServer()
+		token = pivnet.FederationToken{
+			AccessKeyID:     "fakekey",
+			SecretAccessKey: "fakesecret",
+			SessionToken:    "faketoken",
+			Bucket:          "fakebucket",
+			Region:          "us-east-1",
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("puts the object to the custom endpoint with path style", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/fakebucket/6.6.0/file.txt"),
+				ghttp.VerifyBody([]byte("hello")),
+				ghttp.RespondWith(http.StatusOK, nil),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("DELETE", "/fakebucket/6.6.0/file.txt"),
+				ghttp.RespondWith(http.StatusNoContent, nil),
+			),
+		)
+
+		store := NewS3ObjectStore(gp.S3Config{Endpoint: server.URL(), ForcePathStyle: true}, nil)
+
+		location, err := store.Put(ctx, token, "6.6.0/file.txt", strings.NewReader("hello"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(location).To(Equal(server.URL() + "/fakebucket/6.6.0/file.txt"))
+
+		Expect(store.Delete(ctx, token, "6.6.0/file.txt")).To(Succeed())
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+	})
+
+	It("retries the failed request", func() {
+		server.AppendHandlers(
+			ghttp.RespondWith(http.StatusInternalServerError, nil),
+			ghttp.RespondWith(http.StatusInternalServerError, nil),
+			ghttp.RespondWith(http.StatusNoContent, nil),
+		)
+
+		store := NewS3ObjectStore(gp.S3Config{Endpoint: server.URL(), ForcePathStyle: true, MaxRetries: 2}, nil)
+
+		Expect(store.Delete(ctx, token, "file.txt")).To(Succeed())
+		Expect(server.ReceivedRequests()).To(HaveLen(3))
+	})
+
+	It("retries with a new federation token when the token is expired", func() {
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/fakebucket/file.txt"),
+				ghttp.RespondWith(http.StatusBadRequest,
+					`<Error><Code>ExpiredToken</Code><Message>The provided token has expired.</Message></Error>`),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("PUT", "/fakebucket/file.txt"),
+				func(w http.ResponseWriter, req *http.Request) {
+					Expect(req.Header.Get("Authorization")).To(ContainSubstring("Credential=key2/"))
+					Expect(req.Header.Get("X-Amz-Security-Token")).To(Equal("session2"))
+				},
+				ghttp.VerifyBody([]byte("hello")),
+				ghttp.RespondWith(http.StatusOK, nil),
+			),
+		)
+
+		fakeClient := &apifakes.FakeAccessClient{}
+		fakeClient.CreateFederationTokenStub = func(context.Context) (pivnet.FederationToken, error) {
+			n := fakeClient.CreateFederationTokenCallCount()
+			return pivnet.FederationToken{
+				AccessKeyID:     fmt.Sprintf("key%d", n),
+				SecretAccessKey: "fakesecret",
+				SessionToken:    fmt.Sprintf("session%d", n),
+				Bucket:          "fakebucket",
+				Region:          "us-east-1",
+			}, nil
+		}
+		tokens := NewFederationTokenProvider(fakeClient)
+		token, err := tokens.Token(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		store := NewS3ObjectStore(gp.S3Config{Endpoint: server.URL(), ForcePathStyle: true}, tokens)
+		_, err = store.Put(ctx, token, "file.txt", strings.NewReader("hello"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(server.ReceivedRequests()).To(HaveLen(2))
+		Expect(fakeClient.CreateFederationTokenCallCount()).To(Equal(2))
+	})
+})
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"github.com/baotingfang/go-pivnet-client/config"
+	"github.com/pivotal-cf/go-pivnet/v4"
+)
+
+// UploadPlan is everything the uploader would send to pivnet, it is computed
+// without creating anything on pivnet or s3.
+type UploadPlan struct {
+	Release      ReleasePlan       `json:"release"`
+	FileGroups   []FileGroupPlan   `json:"file_groups,omitempty"`
+	ProductFiles []ProductFilePlan `json:"product_files,omitempty"`
+}
+
+// ReleasePlan is the pivnet.CreateReleaseConfig of the release, the plan is printed
+// as json, so its fields are tagged like the pivnet api.
+type ReleasePlan struct {
+	ProductSlug           string `json:"product_slug"`
+	Version               string `json:"version"`
+	ReleaseType           string `json:"release_type"`
+	ReleaseDate           string `json:"release_date"`
+	EULASlug              string `json:"eula_slug"`
+	Description           string `json:"description"`
+	ReleaseNotesURL       string `json:"release_notes_url"`
+	Controlled            bool   `json:"controlled"`
+	ECCN                  string `json:"eccn"`
+	LicenseException      string `json:"license_exception"`
+	EndOfSupportDate      string `json:"end_of_support_date"`
+	EndOfGuidanceDate     string `json:"end_of_guidance_date"`
+	EndOfAvailabilityDate string `json:"end_of_availability_date"`
+	CopyMetadata          bool   `json:"copy_metadata"`
+}
+
+func NewReleasePlan(c pivnet.CreateReleaseConfig) ReleasePlan {
+	return ReleasePlan{
+		ProductSlug:           c.ProductSlug,
+		Version:               c.Version,
+		ReleaseType:           c.ReleaseType,
+		ReleaseDate:           c.ReleaseDate,
+		EULASlug:              c.EULASlug,
+		Description:           c.Description,
+		ReleaseNotesURL:       c.ReleaseNotesURL,
+		Controlled:            c.Controlled,
+		ECCN:                  c.ECCN,
+		LicenseException:      c.LicenseException,
+		EndOfSupportDate:      c.EndOfSupportDate,
+		EndOfGuidanceDate:     c.EndOfGuidanceDate,
+		EndOfAvailabilityDate: c.EndOfAvailabilityDate,
+		CopyMetadata:          c.CopyMetadata,
+	}
+}
+
+type FileGroupPlan struct {
+	Name         string            `json:"name"`
+	ProductFiles []ProductFilePlan `json:"product_files,omitempty"`
+}
+
+type ProductFilePlan struct {
+	File          string            `json:"file"`
+	LocalFilePath string            `json:"local_file_path"`
+	AwsObjectKey  string            `json:"aws_object_key"`
+	ProductFile   ProductFileConfig `json:"product_file"`
+}
+
+// ProductFileConfig is the pivnet.CreateProductFileConfig of the product file,
+// tagged like the pivnet api.
+type ProductFileConfig struct {
+	ProductSlug        string   `json:"product_slug"`
+	AWSObjectKey       string   `json:"aws_object_key"`
+	Description        string   `json:"description"`
+	DocsURL            string   `json:"docs_url"`
+	FileType           string   `json:"file_type"`
+	FileVersion        string   `json:"file_version"`
+	IncludedFiles      []string `json:"included_files"`
+	SHA256             string   `json:"sha256"`
+	MD5                string   `json:"md5"`
+	Name               string   `json:"name"`
+	Platforms          []string `json:"platforms"`
+	ReleasedAt         string   `json:"released_at"`
+	SystemRequirements []string `json:"system_requirements"`
+}
+
+func NewProductFileConfig(c pivnet.CreateProductFileConfig) ProductFileConfig {
+	return ProductFileConfig{
+		ProductSlug:        c.ProductSlug,
+		AWSObjectKey:       c.AWSObjectKey,
+		Description:        c.Description,
+		DocsURL:            c.DocsURL,
+		FileType:           c.FileType,
+		FileVersion:        c.FileVersion,
+		IncludedFiles:      c.IncludedFiles,
+		SHA256:             c.SHA256,
+		MD5:                c.MD5,
+		Name:               c.Name,
+		Platforms:          c.Platforms,
+		ReleasedAt:         c.ReleasedAt,
+		SystemRequirements: c.SystemRequirements,
+	}
+}
+
+func (u Uploader) Plan(ctx context.Context) (UploadPlan, error) {
+	err := u.validate()
+	if err != nil {
+		return UploadPlan{}, err
+	}
+
+	crc, err := u.NewCreateReleaseConfig(ctx, u.Metadata.Release)
+	if err != nil {
+		return UploadPlan{}, err
+	}
+
+	plan := UploadPlan{Release: NewReleasePlan(crc)}
+
+	for _, group := range u.Metadata.FileGroups {
+		groupPlan := FileGroupPlan{Name: group.Name}
+		for _, productFile := range group.ProductFiles {
+			pfPlan, err := u.planProductFile(group.Name, productFile)
+			if err != nil {
+				return UploadPlan{}, err
+			}
+			groupPlan.ProductFiles = append(groupPlan.ProductFiles, pfPlan)
+		}
+		plan.FileGroups = append(plan.FileGroups, groupPlan)
+	}
+
+	for _, productFile := range u.Metadata.ProductFiles {
+		pfPlan, err := u.planProductFile("", productFile)
+		if err != nil {
+			return UploadPlan{}, err
+		}
+		plan.ProductFiles = append(plan.ProductFiles, pfPlan)
+	}
+
+	return plan, nil
+}
+
+func (u Uploader) planProductFile(groupName string, productFile config.ProductFile) (ProductFilePlan, error) {
+	rv, err := u.Resolver.Resolve(productFile.File)
+	if err != nil {
+		return ProductFilePlan{}, err
+	}
+
+	productFile.AWSObjectKey, err = u.awsObjectKey(groupName, productFile, rv)
+	if err != nil {
+		return ProductFilePlan{}, err
+	}
+	cpfc, err := u.NewCreateProductFileConfig(productFile)
+	if err != nil {
+		return ProductFilePlan{}, err
+	}
+
+	return ProductFilePlan{
+		File:          productFile.File,
+		LocalFilePath: rv.LocalFilePath,
+		AwsObjectKey:  productFile.AWSObjectKey,
+		ProductFile:   NewProductFileConfig(cpfc),
+	}, nil
+}
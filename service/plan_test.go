@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"errors"
+	"github.com/baotingfang/go-pivnet-client/api/apifakes"
+	"github.com/baotingfang/go-pivnet-client/config"
+	"github.com/baotingfang/go-pivnet-client/service/servicefakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"strings"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("Plan", func() {
+	var (
+		fakeClient   *apifakes.FakeAccessClient
+		fakeResolver *servicefakes.FakeResolver
+		uploader     Uploader
+	)
+
+	BeforeEach(func() {
+		fakeResolver = newFakeResolver("/tmp/path/file.txt")
+
+		fakeClient = &apifakes.FakeAccessClient{}
+
+		metadata, err := config.MetadataFrom(strings.NewReader(metadataYaml), "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+
+		uploader = Uploader{
+			GpdbVersion:     "6.6.0",
+			Metadata:        metadata,
+			AwsObjectPrefix: "product-files/gpdb",
+			Client:          fakeClient,
+			Resolver:        fakeResolver,
+		}
+	})
+
+	It("computes the release, file groups and product files", func() {
+		plan, err := uploader.Plan(ctx)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(plan.Release.Version).To(Equal("6.6.0"))
+		Expect(plan.Release.ReleaseType).To(Equal("Major Release"))
+		Expect(plan.Release.ReleaseNotesURL).To(Equal("http://example.com/notes/url"))
+		Expect(plan.Release.EndOfSupportDate).To(Equal("2025-01-01"))
+
+		Expect(plan.FileGroups).To(HaveLen(1))
+		Expect(plan.FileGroups[0].Name).To(Equal("Greenplum Database Server"))
+		Expect(plan.FileGroups[0].ProductFiles).To(HaveLen(2))
+		Expect(plan.ProductFiles).To(HaveLen(2))
+
+		pf := plan.FileGroups[0].ProductFiles[0]
+		Expect(pf.LocalFilePath).To(Equal("/tmp/path/file.txt"))
+		Expect(pf.AwsObjectKey).To(Equal("product-files/gpdb/server-rhel6.txt"))
+		Expect(pf.ProductFile.AWSObjectKey).To(Equal(pf.AwsObjectKey))
+		Expect(pf.ProductFile.Description).To(Equal("Greenplum Database 6.6.0 Installer for RHEL 6"))
+		Expect(pf.ProductFile.FileVersion).To(Equal("6.6.0"))
+	})
+
+	It("does not create anything on pivnet", func() {
+		_, err := uploader.Plan(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.CreateReleaseCallCount()).To(Equal(0))
+		Expect(fakeClient.CreateFileGroupCallCount()).To(Equal(0))
+		Expect(fakeClient.CreateProductFileCallCount()).To(Equal(0))
+		Expect(fakeClient.CreateFederationTokenCallCount()).To(Equal(0))
+	})
+
+	It("returns the error when a file can not be resolved", func() {
+		fakeResolver.ResolveReturns(ResolvedFile{}, errors.New("file not found"))
+		_, err := uploader.Plan(ctx)
+		Expect(err).To(MatchError("file not found"))
+	})
+})
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultProgressBarInterval = 200 * time.Millisecond
+	DefaultProgressLogInterval = 10 * time.Second
+
+	progressBarWidth = 30
+)
+
+// ProgressReporter reports the bytes uploaded for each product file and for the
+// whole release. The files which are not uploaded again are reported by Skip.
+type ProgressReporter interface {
+	Start(totalBytes int64)
+	Track(name string, size int64, r io.Reader) io.Reader
+	Skip(name string, size int64)
+	Stop()
+}
+
+// NewProgressReporter shows progress bars when out is a terminal,
+// otherwise the progress is logged periodically.
+func NewProgressReporter(out io.Writer) ProgressReporter {
+	if f, ok := out.(*os.File); ok && isTerminal(f) {
+		return NewProgressBars(out, DefaultProgressBarInterval)
+	}
+	return NewProgressLog(DefaultProgressLogInterval, vlog.Info)
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+type NoProgress struct{}
+
+func (NoProgress) Start(totalBytes int64) {}
+
+func (NoProgress) Track(name string, size int64, r io.Reader) io.Reader { return r }
+
+func (NoProgress) Skip(name string, size int64) {}
+
+func (NoProgress) Stop() {}
+
+type fileProgress struct {
+	name    string
+	size    int64
+	done    int64
+	started time.Time
+}
+
+type progressReader struct {
+	reader  io.Reader
+	file    *fileProgress
+	overall *int64
+}
+
+func (r progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	atomic.AddInt64(&r.file.done, int64(n))
+	atomic.AddInt64(r.overall, int64(n))
+	return n, err
+}
+
+// progress is the state shared by the progress bars and the progress log,
+// render is called periodically until it is stopped.
+type progress struct {
+	interval time.Duration
+	render   func(p *progress, final bool)
+
+	mu       sync.Mutex
+	total    int64
+	done     int64
+	uploaded int64
+	started  time.Time
+	files    []*fileProgress
+	stop     chan struct{}
+	stopped  sync.WaitGroup
+}
+
+func (p *progress) Start(totalBytes int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stop != nil {
+		return
+	}
+
+	p.total = totalBytes
+	p.started = time.Now()
+	p.stop = make(chan struct{})
+	p.stopped.Add(1)
+	go func() {
+		defer p.stopped.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				p.render(p, false)
+			case <-p.stop:
+				p.render(p, true)
+				return
+			}
+		}
+	}()
+}
+
+func (p *progress) Track(name string, size int64, r io.Reader) io.Reader {
+	file := &fileProgress{name: name, size: size, started: time.Now()}
+	p.mu.Lock()
+	p.files = append(p.files, file)
+	p.mu.Unlock()
+	return progressReader{reader: r, file: file, overall: &p.uploaded}
+}
+
+func (p *progress) Skip(name string, size int64) {
+	atomic.AddInt64(&p.done, size)
+}
+
+func (p *progress) Stop() {
+	p.mu.Lock()
+	stop := p.stop
+	p.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	p.stopped.Wait()
+}
+
+type progressSnapshot struct {
+	name    string
+	size    int64
+	done    int64
+	elapsed time.Duration
+}
+
+// snapshot returns the progress of the files being uploaded, and the progress of the release at last
+func (p *progress) snapshot(includeCompleted bool) []progressSnapshot {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var snapshots []progressSnapshot
+	for _, f := range p.files {
+		done := atomic.LoadInt64(&f.done)
+		if !includeCompleted && done >= f.size {
+			continue
+		}
+		snapshots = append(snapshots, progressSnapshot{
+			name: f.name, size: f.size, done: done, elapsed: now.Sub(f.started),
+		})
+	}
+
+	// the skipped files are completed, but they take no time
+	uploaded := atomic.LoadInt64(&p.uploaded)
+	snapshots = append(snapshots, progressSnapshot{
+		name:    "total",
+		size:    p.total,
+		done:    uploaded + atomic.LoadInt64(&p.done),
+		elapsed: now.Sub(p.started),
+	})
+	return snapshots
+}
+
+// rate returns the bytes per second, and the remaining time
+func (s progressSnapshot) rate(uploaded int64) (float64, time.Duration) {
+	seconds := s.elapsed.Seconds()
+	if seconds <= 0 || uploaded <= 0 {
+		return 0, -1
+	}
+	rate := float64(uploaded) / seconds
+	remaining := s.size - s.done
+	if remaining < 0 {
+		remaining = 0
+	}
+	return rate, time.Duration(float64(remaining)/rate) * time.Second
+}
+
+func (s progressSnapshot) percent() float64 {
+	if s.size <= 0 {
+		return 100
+	}
+	percent := float64(s.done) * 100 / float64(s.size)
+	if percent > 100 {
+		percent = 100
+	}
+	return percent
+}
+
+func (s progressSnapshot) String() string {
+	return s.format(s.done)
+}
+
+func (s progressSnapshot) format(uploaded int64) string {
+	rate, eta := s.rate(uploaded)
+	etaText := "--"
+	if eta >= 0 {
+		etaText = eta.Round(time.Second).String()
+	}
+	return fmt.Sprintf("%5.1f%% %s / %s, %s/s, ETA %s",
+		s.percent(), formatBytes(s.done), formatBytes(s.size), formatBytes(int64(rate)), etaText)
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// NewProgressBars redraws a progress bar for each file being uploaded and for the release
+func NewProgressBars(out io.Writer, interval time.Duration) ProgressReporter {
+	lines := 0
+	return &progress{
+		interval: interval,
+		render: func(p *progress, final bool) {
+			snapshots := p.snapshot(final)
+			total := snapshots[len(snapshots)-1]
+			uploaded := atomic.LoadInt64(&p.uploaded)
+
+			var b strings.Builder
+			if lines > 0 {
+				// move the cursor back to the first progress bar
+				_, _ = fmt.Fprintf(&b, "\033[%dA", lines)
+			}
+			for _, s := range snapshots[:len(snapshots)-1] {
+				_, _ = fmt.Fprintf(&b, "\033[2K%s %s %s\n", progressBar(s.percent()), s, s.name)
+			}
+			_, _ = fmt.Fprintf(&b, "\033[2K%s %s %s\n", progressBar(total.percent()), total.format(uploaded), total.name)
+			// clear the lines of the files which are completed since last time
+			for i := len(snapshots); i < lines; i++ {
+				b.WriteString("\033[2K\n")
+			}
+			if len(snapshots) < lines {
+				_, _ = fmt.Fprintf(&b, "\033[%dA", lines-len(snapshots))
+			}
+			lines = len(snapshots)
+			_, _ = io.WriteString(out, b.String())
+		},
+	}
+}
+
+func progressBar(percent float64) string {
+	filled := int(percent * progressBarWidth / 100)
+	return "[" + strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled) + "]"
+}
+
+// NewProgressLog logs the progress of the files being uploaded and the release periodically
+func NewProgressLog(interval time.Duration, logf func(format string, args ...interface{})) ProgressReporter {
+	return &progress{
+		interval: interval,
+		render: func(p *progress, final bool) {
+			snapshots := p.snapshot(false)
+			total := snapshots[len(snapshots)-1]
+			if !final {
+				for _, s := range snapshots[:len(snapshots)-1] {
+					logf("uploading %s: %s", s.name, s)
+				}
+			}
+			logf("uploaded %s", total.format(atomic.LoadInt64(&p.uploaded)))
+		},
+	}
+}
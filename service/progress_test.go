@@ -0,0 +1,103 @@
+package service_test
+
+import (
+	"bytes"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("ProgressReporter", func() {
+	It("passes the content through", func() {
+		progress := NewProgressLog(time.Hour, func(format string, args ...interface{}) {})
+		progress.Start(5)
+		content, err := ioutil.ReadAll(progress.Track("file.txt", 5, strings.NewReader("hello")))
+		progress.Stop()
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("hello"))
+	})
+
+	It("logs the progress of files and the release", func() {
+		var lines []string
+		progress := NewProgressLog(time.Hour, func(format string, args ...interface{}) {
+			lines = append(lines, fmt.Sprintf(format, args...))
+		})
+
+		progress.Start(4096)
+		progress.Skip("skipped.txt", 1024)
+		_, err := ioutil.ReadAll(progress.Track("file.txt", 3072, bytes.NewReader(make([]byte, 3072))))
+		Expect(err).NotTo(HaveOccurred())
+		progress.Stop()
+
+		Expect(lines).To(HaveLen(1))
+		Expect(lines[0]).To(HavePrefix("uploaded 100.0% 4.0 KiB / 4.0 KiB"))
+	})
+
+	It("logs the files being uploaded periodically", func() {
+		lines := make(chan string, 100)
+		progress := NewProgressLog(time.Millisecond, func(format string, args ...interface{}) {
+			lines <- fmt.Sprintf(format, args...)
+		})
+
+		progress.Start(2048)
+		reader := progress.Track("file.txt", 2048, bytes.NewReader(make([]byte, 1024)))
+		_, err := ioutil.ReadAll(reader)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(lines).Should(Receive(HavePrefix("uploading file.txt:  50.0% 1.0 KiB / 2.0 KiB")))
+		progress.Stop()
+	})
+
+	It("draws progress bars", func() {
+		out := &bytes.Buffer{}
+		progress := NewProgressBars(out, time.Hour)
+
+		progress.Start(1024)
+		_, err := ioutil.ReadAll(progress.Track("file.txt", 1024, bytes.NewReader(make([]byte, 1024))))
+		Expect(err).NotTo(HaveOccurred())
+		progress.Stop()
+
+		bar := "[" + strings.Repeat("=", 30) + "]"
+		Expect(out.String()).To(ContainSubstring(bar + " 100.0% 1.0 KiB / 1.0 KiB"))
+		Expect(out.String()).To(ContainSubstring("file.txt\n"))
+		Expect(out.String()).To(ContainSubstring("total\n"))
+	})
+
+	It("redraws fewer progress bars after a file is completed", func() {
+		out := gbytes.NewBuffer()
+		progress := NewProgressBars(out, time.Millisecond)
+
+		a := progress.Track("a.txt", 1024, bytes.NewReader(make([]byte, 1024)))
+		b := progress.Track("b.txt", 1024, bytes.NewReader(make([]byte, 1024)))
+		_, err := a.Read(make([]byte, 512))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = b.Read(make([]byte, 512))
+		Expect(err).NotTo(HaveOccurred())
+
+		progress.Start(2048)
+		Eventually(out).Should(gbytes.Say(`a\.txt\n.*b\.txt\n.*total\n`))
+
+		// the cursor moves back over 3 lines until a.txt is completed, and then 2 lines
+		_, err = ioutil.ReadAll(a)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(out).Should(gbytes.Say("\033\\[2A\033\\[2K\\[=+ +\\] .* b\\.txt\n"))
+
+		_, err = ioutil.ReadAll(b)
+		Expect(err).NotTo(HaveOccurred())
+		progress.Stop()
+	})
+
+	It("does not draw anything when it is not started", func() {
+		out := &bytes.Buffer{}
+		progress := NewProgressBars(out, time.Hour)
+		progress.Stop()
+		Expect(out.String()).To(BeEmpty())
+	})
+})
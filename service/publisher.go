@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/baotingfang/go-pivnet-client/api"
+	"github.com/baotingfang/go-pivnet-client/config"
+	"github.com/baotingfang/go-pivnet-client/gp"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"strings"
+)
+
+const (
+	AvailabilityAdminsOnly         = config.AvailabilityAdminsOnly
+	AvailabilitySelectedUserGroups = config.AvailabilitySelectedUserGroups
+	AvailabilityAllUsers           = config.AvailabilityAllUsers
+)
+
+var Availabilities = config.Availabilities
+
+type Publisher struct {
+	GpdbVersion  string
+	Availability string
+	UserGroups   []string
+
+	Context gp.Context
+	Client  api.AccessClient
+}
+
+func NewPublisher(context gp.Context, gpdbVersion string, availability string, userGroups []string) Publisher {
+	return Publisher{
+		GpdbVersion:  gpdbVersion,
+		Availability: availability,
+		UserGroups:   userGroups,
+
+		Context: context,
+		Client:  api.NewApiClient(context),
+	}
+}
+
+// Publish changes the availability of the release after all of its product files
+// are transferred, the user groups are added to the release before that.
+func (p Publisher) Publish(ctx context.Context) (pivnet.Release, error) {
+	err := p.validate()
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	release, err := p.Client.GetReleaseByVersion(ctx, p.GpdbVersion)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	err = p.checkFileTransfers(ctx, release)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	err = p.addUserGroups(ctx, release)
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+
+	if release.Availability == p.Availability {
+		vlog.Info("availability of release %s is already %s", release.Version, release.Availability)
+		return release, nil
+	}
+
+	vlog.Info("changing availability of release %s from %s to %s", release.Version, release.Availability, p.Availability)
+	release.Availability = p.Availability
+	updated, err := p.Client.UpdateRelease(ctx, release)
+	if err != nil {
+		return pivnet.Release{}, fmt.Errorf("update release %s (id=%d) failed: %w", release.Version, release.ID, err)
+	}
+	return updated, nil
+}
+
+func (p Publisher) validate() error {
+	valid := false
+	for _, availability := range Availabilities {
+		if p.Availability == availability {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return NewValidationError("not support availability: %q, it should be one of: %s",
+			p.Availability, strings.Join(Availabilities, ", "))
+	}
+
+	if len(p.UserGroups) > 0 && p.Availability != AvailabilitySelectedUserGroups {
+		return NewValidationError("user groups can only be added when availability is %s", AvailabilitySelectedUserGroups)
+	}
+	return nil
+}
+
+func (p Publisher) checkFileTransfers(ctx context.Context, release pivnet.Release) error {
+	_, productFiles, err := releaseObjects(ctx, p.Client, release)
+	if err != nil {
+		return err
+	}
+
+	var inProgress []string
+	for _, pf := range productFiles {
+		transferring, err := p.Client.FileTransferStatusInProgress(ctx, pf.ID)
+		if err != nil {
+			return err
+		}
+		if transferring {
+			inProgress = append(inProgress, fmt.Sprintf("%s (id=%d)", pf.Name, pf.ID))
+		}
+	}
+
+	if len(inProgress) > 0 {
+		return NewConflictError("file transfer of the product files are still in progress:\n%s", strings.Join(inProgress, "\n"))
+	}
+	return nil
+}
+
+func (p Publisher) addUserGroups(ctx context.Context, release pivnet.Release) error {
+	if len(p.UserGroups) == 0 {
+		return nil
+	}
+
+	allUserGroups, err := p.Client.GetAllUserGroups(ctx)
+	if err != nil {
+		return err
+	}
+	userGroupsByName := make(map[string]pivnet.UserGroup)
+	for _, group := range allUserGroups {
+		userGroupsByName[group.Name] = group
+	}
+
+	var userGroups []pivnet.UserGroup
+	for _, name := range p.UserGroups {
+		group, ok := userGroupsByName[name]
+		if !ok {
+			return NewNotFoundError("can not found user group: %s", name)
+		}
+		userGroups = append(userGroups, group)
+	}
+
+	releaseUserGroups, err := p.Client.GetUserGroupsForRelease(ctx, release.ID)
+	if err != nil {
+		return err
+	}
+	added := make(map[int]bool)
+	for _, group := range releaseUserGroups {
+		added[group.ID] = true
+	}
+
+	for _, group := range userGroups {
+		if added[group.ID] {
+			vlog.Info("user group %s (id=%d) is already added to release %s", group.Name, group.ID, release.Version)
+			continue
+		}
+
+		vlog.Info("adding user group %s (id=%d) to release %s", group.Name, group.ID, release.Version)
+		err := p.Client.AddUserGroupToRelease(ctx, group.ID, release.ID)
+		if err != nil {
+			return fmt.Errorf("add user group %s (id=%d) to release failed: %w", group.Name, group.ID, err)
+		}
+		added[group.ID] = true
+	}
+	return nil
+}
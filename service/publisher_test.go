@@ -0,0 +1,117 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/baotingfang/go-pivnet-client/api/apifakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet/v4"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("Publisher", func() {
+	var (
+		fakeClient *apifakes.FakeAccessClient
+		publisher  Publisher
+	)
+
+	BeforeEach(func() {
+		fakeClient = &apifakes.FakeAccessClient{}
+		fakeClient.GetReleaseByVersionReturns(pivnet.Release{ID: 10, Version: "6.6.0", Availability: AvailabilityAdminsOnly}, nil)
+		fakeClient.GetFileGroupsForReleaseReturns([]pivnet.FileGroup{
+			{ID: 20, Name: "Greenplum Database Server", ProductFiles: []pivnet.ProductFile{{ID: 30, Name: "rhel6"}}},
+		}, nil)
+		fakeClient.GetProductFilesForReleaseReturns([]pivnet.ProductFile{{ID: 31, Name: "osl"}}, nil)
+		fakeClient.GetAllUserGroupsReturns([]pivnet.UserGroup{
+			{ID: 40, Name: "beta"},
+			{ID: 41, Name: "partners"},
+		}, nil)
+		fakeClient.UpdateReleaseStub = func(_ context.Context, release pivnet.Release) (pivnet.Release, error) {
+			return release, nil
+		}
+
+		publisher = Publisher{
+			GpdbVersion:  "6.6.0",
+			Availability: AvailabilityAllUsers,
+			Client:       fakeClient,
+		}
+	})
+
+	It("changes the availability of the release", func() {
+		release, err := publisher.Publish(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(release.Availability).To(Equal(AvailabilityAllUsers))
+		Expect(fakeClient.UpdateReleaseCallCount()).To(Equal(1))
+		_, updated := fakeClient.UpdateReleaseArgsForCall(0)
+		Expect(updated.ID).To(Equal(10))
+		Expect(fakeClient.FileTransferStatusInProgressCallCount()).To(Equal(2))
+		Expect(fakeClient.AddUserGroupToReleaseCallCount()).To(Equal(0))
+	})
+
+	It("adds the user groups which are not added to the release yet", func() {
+		publisher.Availability = AvailabilitySelectedUserGroups
+		publisher.UserGroups = []string{"beta", "partners"}
+		fakeClient.GetUserGroupsForReleaseReturns([]pivnet.UserGroup{{ID: 40, Name: "beta"}}, nil)
+
+		release, err := publisher.Publish(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(release.Availability).To(Equal(AvailabilitySelectedUserGroups))
+		Expect(fakeClient.AddUserGroupToReleaseCallCount()).To(Equal(1))
+		_, userGroupId, releaseId := fakeClient.AddUserGroupToReleaseArgsForCall(0)
+		Expect(userGroupId).To(Equal(41))
+		Expect(releaseId).To(Equal(10))
+	})
+
+	It("does not update the release when the availability is not changed", func() {
+		publisher.Availability = AvailabilityAdminsOnly
+
+		_, err := publisher.Publish(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.UpdateReleaseCallCount()).To(Equal(0))
+	})
+
+	It("unknown availability", func() {
+		publisher.Availability = "Everyone"
+
+		_, err := publisher.Publish(ctx)
+		Expect(err).To(MatchError(`not support availability: "Everyone", it should be one of: Admins Only, Selected User Groups Only, All Users`))
+		Expect(fakeClient.GetReleaseByVersionCallCount()).To(Equal(0))
+	})
+
+	It("user groups with availability other than selected user groups", func() {
+		publisher.UserGroups = []string{"beta"}
+
+		_, err := publisher.Publish(ctx)
+		Expect(err).To(MatchError("user groups can only be added when availability is Selected User Groups Only"))
+	})
+
+	It("unknown user group", func() {
+		publisher.Availability = AvailabilitySelectedUserGroups
+		publisher.UserGroups = []string{"beta", "unknown"}
+
+		_, err := publisher.Publish(ctx)
+		Expect(err).To(MatchError("can not found user group: unknown"))
+		Expect(fakeClient.AddUserGroupToReleaseCallCount()).To(Equal(0))
+		Expect(fakeClient.UpdateReleaseCallCount()).To(Equal(0))
+	})
+
+	It("file transfer is still in progress", func() {
+		fakeClient.FileTransferStatusInProgressStub = func(_ context.Context, productFileId int) (bool, error) {
+			return productFileId == 31, nil
+		}
+
+		_, err := publisher.Publish(ctx)
+		Expect(err).To(MatchError("file transfer of the product files are still in progress:\nosl (id=31)"))
+		Expect(fakeClient.UpdateReleaseCallCount()).To(Equal(0))
+	})
+
+	It("update release failed", func() {
+		fakeClient.UpdateReleaseStub = nil
+		fakeClient.UpdateReleaseReturns(pivnet.Release{}, errors.New("server error"))
+
+		_, err := publisher.Publish(ctx)
+		Expect(err).To(MatchError("update release 6.6.0 (id=10) failed: server error"))
+	})
+})
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"github.com/baotingfang/go-pivnet-client/api"
+	"github.com/pivotal-cf/go-pivnet/v4"
+)
+
+// RemoteObjects are the file groups and product files which already exist
+// in a release on pivnet, they are reused when uploading the same release again.
+type RemoteObjects struct {
+	FileGroups          map[string]pivnet.FileGroup
+	ReleaseProductFiles []pivnet.ProductFile
+}
+
+func NewRemoteObjects() RemoteObjects {
+	return RemoteObjects{
+		FileGroups: make(map[string]pivnet.FileGroup),
+	}
+}
+
+func LoadRemoteObjects(ctx context.Context, client api.AccessClient, releaseId int) (RemoteObjects, error) {
+	remote := NewRemoteObjects()
+
+	fileGroups, err := client.GetFileGroupsForRelease(ctx, releaseId)
+	if err != nil {
+		return RemoteObjects{}, err
+	}
+	for _, group := range fileGroups {
+		remote.FileGroups[group.Name] = group
+	}
+
+	remote.ReleaseProductFiles, err = client.GetProductFilesForRelease(ctx, releaseId)
+	if err != nil {
+		return RemoteObjects{}, err
+	}
+
+	return remote, nil
+}
+
+func (r RemoteObjects) FileGroup(name string) (pivnet.FileGroup, bool) {
+	group, ok := r.FileGroups[name]
+	return group, ok
+}
+
+// ProductFile finds the product file by aws object key in the file group,
+// or in the release when the group name is empty.
+func (r RemoteObjects) ProductFile(groupName string, awsObjectKey string) (pivnet.ProductFile, bool) {
+	productFiles := r.ReleaseProductFiles
+	if groupName != "" {
+		productFiles = r.FileGroups[groupName].ProductFiles
+	}
+
+	for _, pf := range productFiles {
+		if pf.AWSObjectKey == awsObjectKey {
+			return pf, true
+		}
+	}
+	return pivnet.ProductFile{}, false
+}
@@ -1,8 +1,6 @@
 package service
 
 import (
-	"errors"
-	"fmt"
 	"github.com/baotingfang/go-pivnet-client/utils"
 	semver "github.com/cppforlife/go-semi-semantic/version"
 	"net/url"
@@ -53,7 +51,7 @@ func (r ResourceResolver) Resolve(file string) (ResolvedFile, error) {
 	if schema == FileSchema {
 		return resolveLocalFile(r, resourceUrl)
 	} else {
-		return ResolvedFile{}, errors.New("not support schema:" + schema)
+		return ResolvedFile{}, utils.NewValidationError("not support schema:%s", schema)
 	}
 }
 
@@ -93,11 +91,11 @@ func resolveLocalFile(resolver ResourceResolver, resource *url.URL) (ResolvedFil
 	}
 
 	if len(matchedFiles) == 0 {
-		return ResolvedFile{}, fmt.Errorf("can not match file")
+		return ResolvedFile{}, utils.NewNotFoundError("can not match file")
 	}
 
 	if len(matchedFiles) > 1 {
-		return ResolvedFile{}, fmt.Errorf("match multiple files")
+		return ResolvedFile{}, utils.NewConflictError("match multiple files")
 	}
 
 	return matchedFiles[0], nil
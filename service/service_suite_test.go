@@ -1,12 +1,16 @@
 package service_test
 
 import (
+	"context"
 	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
 
+// ctx is the context of the calls in the tests, which is never cancelled
+var ctx = context.Background()
+
 func TestService(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Service Suite")
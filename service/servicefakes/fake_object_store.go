@@ -0,0 +1,284 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package servicefakes
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/baotingfang/go-pivnet-client/service"
+	pivnet "github.com/pivotal-cf/go-pivnet/v4"
+)
+
+type FakeObjectStore struct {
+	DeleteStub        func(context.Context, pivnet.FederationToken, string) error
+	deleteMutex       sync.RWMutex
+	deleteArgsForCall []struct {
+		arg1 context.Context
+		arg2 pivnet.FederationToken
+		arg3 string
+	}
+	deleteReturns struct {
+		result1 error
+	}
+	deleteReturnsOnCall map[int]struct {
+		result1 error
+	}
+	LastModifiedStub        func(context.Context, pivnet.FederationToken, string) (time.Time, error)
+	lastModifiedMutex       sync.RWMutex
+	lastModifiedArgsForCall []struct {
+		arg1 context.Context
+		arg2 pivnet.FederationToken
+		arg3 string
+	}
+	lastModifiedReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	lastModifiedReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
+	PutStub        func(context.Context, pivnet.FederationToken, string, io.Reader) (string, error)
+	putMutex       sync.RWMutex
+	putArgsForCall []struct {
+		arg1 context.Context
+		arg2 pivnet.FederationToken
+		arg3 string
+		arg4 io.Reader
+	}
+	putReturns struct {
+		result1 string
+		result2 error
+	}
+	putReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeObjectStore) Delete(arg1 context.Context, arg2 pivnet.FederationToken, arg3 string) error {
+	fake.deleteMutex.Lock()
+	ret, specificReturn := fake.deleteReturnsOnCall[len(fake.deleteArgsForCall)]
+	fake.deleteArgsForCall = append(fake.deleteArgsForCall, struct {
+		arg1 context.Context
+		arg2 pivnet.FederationToken
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("Delete", []interface{}{arg1, arg2, arg3})
+	fake.deleteMutex.Unlock()
+	if fake.DeleteStub != nil {
+		return fake.DeleteStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) DeleteCallCount() int {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	return len(fake.deleteArgsForCall)
+}
+
+func (fake *FakeObjectStore) DeleteCalls(stub func(context.Context, pivnet.FederationToken, string) error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = stub
+}
+
+func (fake *FakeObjectStore) DeleteArgsForCall(i int) (context.Context, pivnet.FederationToken, string) {
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	argsForCall := fake.deleteArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeObjectStore) DeleteReturns(result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	fake.deleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) DeleteReturnsOnCall(i int, result1 error) {
+	fake.deleteMutex.Lock()
+	defer fake.deleteMutex.Unlock()
+	fake.DeleteStub = nil
+	if fake.deleteReturnsOnCall == nil {
+		fake.deleteReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) LastModified(arg1 context.Context, arg2 pivnet.FederationToken, arg3 string) (time.Time, error) {
+	fake.lastModifiedMutex.Lock()
+	ret, specificReturn := fake.lastModifiedReturnsOnCall[len(fake.lastModifiedArgsForCall)]
+	fake.lastModifiedArgsForCall = append(fake.lastModifiedArgsForCall, struct {
+		arg1 context.Context
+		arg2 pivnet.FederationToken
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("LastModified", []interface{}{arg1, arg2, arg3})
+	fake.lastModifiedMutex.Unlock()
+	if fake.LastModifiedStub != nil {
+		return fake.LastModifiedStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.lastModifiedReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) LastModifiedCallCount() int {
+	fake.lastModifiedMutex.RLock()
+	defer fake.lastModifiedMutex.RUnlock()
+	return len(fake.lastModifiedArgsForCall)
+}
+
+func (fake *FakeObjectStore) LastModifiedCalls(stub func(context.Context, pivnet.FederationToken, string) (time.Time, error)) {
+	fake.lastModifiedMutex.Lock()
+	defer fake.lastModifiedMutex.Unlock()
+	fake.LastModifiedStub = stub
+}
+
+func (fake *FakeObjectStore) LastModifiedArgsForCall(i int) (context.Context, pivnet.FederationToken, string) {
+	fake.lastModifiedMutex.RLock()
+	defer fake.lastModifiedMutex.RUnlock()
+	argsForCall := fake.lastModifiedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeObjectStore) LastModifiedReturns(result1 time.Time, result2 error) {
+	fake.lastModifiedMutex.Lock()
+	defer fake.lastModifiedMutex.Unlock()
+	fake.LastModifiedStub = nil
+	fake.lastModifiedReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) LastModifiedReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.lastModifiedMutex.Lock()
+	defer fake.lastModifiedMutex.Unlock()
+	fake.LastModifiedStub = nil
+	if fake.lastModifiedReturnsOnCall == nil {
+		fake.lastModifiedReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.lastModifiedReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) Put(arg1 context.Context, arg2 pivnet.FederationToken, arg3 string, arg4 io.Reader) (string, error) {
+	fake.putMutex.Lock()
+	ret, specificReturn := fake.putReturnsOnCall[len(fake.putArgsForCall)]
+	fake.putArgsForCall = append(fake.putArgsForCall, struct {
+		arg1 context.Context
+		arg2 pivnet.FederationToken
+		arg3 string
+		arg4 io.Reader
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("Put", []interface{}{arg1, arg2, arg3, arg4})
+	fake.putMutex.Unlock()
+	if fake.PutStub != nil {
+		return fake.PutStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.putReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) PutCallCount() int {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	return len(fake.putArgsForCall)
+}
+
+func (fake *FakeObjectStore) PutCalls(stub func(context.Context, pivnet.FederationToken, string, io.Reader) (string, error)) {
+	fake.putMutex.Lock()
+	defer fake.putMutex.Unlock()
+	fake.PutStub = stub
+}
+
+func (fake *FakeObjectStore) PutArgsForCall(i int) (context.Context, pivnet.FederationToken, string, io.Reader) {
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	argsForCall := fake.putArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeObjectStore) PutReturns(result1 string, result2 error) {
+	fake.putMutex.Lock()
+	defer fake.putMutex.Unlock()
+	fake.PutStub = nil
+	fake.putReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) PutReturnsOnCall(i int, result1 string, result2 error) {
+	fake.putMutex.Lock()
+	defer fake.putMutex.Unlock()
+	fake.PutStub = nil
+	if fake.putReturnsOnCall == nil {
+		fake.putReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.putReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deleteMutex.RLock()
+	defer fake.deleteMutex.RUnlock()
+	fake.lastModifiedMutex.RLock()
+	defer fake.lastModifiedMutex.RUnlock()
+	fake.putMutex.RLock()
+	defer fake.putMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeObjectStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ service.ObjectStore = new(FakeObjectStore)
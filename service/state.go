@@ -0,0 +1,171 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+type FileGroupState struct {
+	ID                int  `json:"id,omitempty"`
+	AttachedToRelease bool `json:"attached_to_release,omitempty"`
+}
+
+type ProductFileState struct {
+	AwsObjectKey  string `json:"aws_object_key,omitempty"`
+	SHA256        string `json:"sha256,omitempty"`
+	MD5           string `json:"md5,omitempty"`
+	Uploaded      bool   `json:"uploaded,omitempty"`
+	ProductFileID int    `json:"product_file_id,omitempty"`
+	Attached      bool   `json:"attached,omitempty"`
+}
+
+// UploadState is the progress of an upload. When it has a path, it is saved
+// to that file after every step, so that a failed upload can be resumed.
+type UploadState struct {
+	GpdbVersion  string                       `json:"gpdb_version"`
+	ReleaseID    int                          `json:"release_id,omitempty"`
+	FileGroups   map[string]*FileGroupState   `json:"file_groups,omitempty"`
+	ProductFiles map[string]*ProductFileState `json:"product_files,omitempty"`
+
+	path string
+	mu   sync.Mutex
+}
+
+func NewUploadState(gpdbVersion string, path string) *UploadState {
+	return &UploadState{
+		GpdbVersion:  gpdbVersion,
+		FileGroups:   make(map[string]*FileGroupState),
+		ProductFiles: make(map[string]*ProductFileState),
+		path:         path,
+	}
+}
+
+func LoadUploadState(path string, gpdbVersion string) (*UploadState, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, NewStorageError(err, "can not read upload state file %s", path)
+	}
+
+	state := NewUploadState(gpdbVersion, path)
+	if err := json.Unmarshal(content, state); err != nil {
+		return nil, ValidationError{Message: fmt.Sprintf("can not parse upload state file %s", path), Err: err}
+	}
+
+	if state.GpdbVersion != gpdbVersion {
+		return nil, NewConflictError("upload state file %s is for gpdb %s, not for gpdb %s",
+			path, state.GpdbVersion, gpdbVersion)
+	}
+
+	if state.FileGroups == nil {
+		state.FileGroups = make(map[string]*FileGroupState)
+	}
+	if state.ProductFiles == nil {
+		state.ProductFiles = make(map[string]*ProductFileState)
+	}
+	return state, nil
+}
+
+func (s *UploadState) Path() string {
+	return s.path
+}
+
+func (s *UploadState) Persistent() bool {
+	return s.path != ""
+}
+
+func (s *UploadState) SetRelease(releaseId int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ReleaseID = releaseId
+	return s.save()
+}
+
+func (s *UploadState) FileGroup(name string) FileGroupState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fg, ok := s.FileGroups[name]; ok {
+		return *fg
+	}
+	return FileGroupState{}
+}
+
+func (s *UploadState) UpdateFileGroup(name string, update func(fg *FileGroupState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fg, ok := s.FileGroups[name]
+	if !ok {
+		fg = &FileGroupState{}
+		s.FileGroups[name] = fg
+	}
+	update(fg)
+	return s.save()
+}
+
+func (s *UploadState) ProductFile(key string) ProductFileState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pf, ok := s.ProductFiles[key]; ok {
+		return *pf
+	}
+	return ProductFileState{}
+}
+
+func (s *UploadState) UpdateProductFile(key string, update func(pf *ProductFileState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pf, ok := s.ProductFiles[key]
+	if !ok {
+		pf = &ProductFileState{}
+		s.ProductFiles[key] = pf
+	}
+	update(pf)
+	return s.save()
+}
+
+// Remove deletes the state file, it is called when the upload is completed
+func (s *UploadState) Remove() error {
+	if !s.Persistent() {
+		return nil
+	}
+	err := os.Remove(s.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (s *UploadState) save() error {
+	if !s.Persistent() {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return NewStorageError(err, "can not save upload state file %s", s.path)
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return NewStorageError(err, "can not save upload state file %s", s.path)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return NewStorageError(err, "can not save upload state file %s", s.path)
+	}
+
+	return os.Rename(tmpFile.Name(), s.path)
+}
+
+func productFileStateKey(groupName string, uploadAs string) string {
+	return groupName + "/" + uploadAs
+}
@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/baotingfang/go-pivnet-client/service"
+)
+
+var _ = Describe("UploadState", func() {
+	var (
+		tmpDir    string
+		statePath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "state-test")
+		Expect(err).NotTo(HaveOccurred())
+		statePath = filepath.Join(tmpDir, "state.json")
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(tmpDir)
+	})
+
+	It("saves the progress after every step", func() {
+		state := NewUploadState("6.6.0", statePath)
+		Expect(state.Persistent()).To(BeTrue())
+
+		Expect(state.SetRelease(10)).To(Succeed())
+		Expect(state.UpdateFileGroup("server", func(fg *FileGroupState) {
+			fg.ID = 20
+		})).To(Succeed())
+		Expect(state.UpdateProductFile("server/rhel7", func(pf *ProductFileState) {
+			pf.AwsObjectKey = "greenplum-db-6.6.0-rhel7-x86_64.rpm"
+			pf.Uploaded = true
+		})).To(Succeed())
+
+		loaded, err := LoadUploadState(statePath, "6.6.0")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.ReleaseID).To(Equal(10))
+		Expect(loaded.FileGroup("server")).To(Equal(FileGroupState{ID: 20}))
+		Expect(loaded.ProductFile("server/rhel7")).To(Equal(ProductFileState{
+			AwsObjectKey: "greenplum-db-6.6.0-rhel7-x86_64.rpm",
+			Uploaded:     true,
+		}))
+		Expect(loaded.ProductFile("server/rhel6")).To(Equal(ProductFileState{}))
+
+		Expect(loaded.Remove()).To(Succeed())
+		_, err = os.Stat(statePath)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("does not save the progress without path", func() {
+		state := NewUploadState("6.6.0", "")
+		Expect(state.Persistent()).To(BeFalse())
+		Expect(state.SetRelease(10)).To(Succeed())
+		Expect(state.ReleaseID).To(Equal(10))
+	})
+
+	It("state file is for another gpdb version", func() {
+		state := NewUploadState("6.6.0", statePath)
+		Expect(state.SetRelease(10)).To(Succeed())
+
+		_, err := LoadUploadState(statePath, "6.7.0")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HaveSuffix("is for gpdb 6.6.0, not for gpdb 6.7.0"))
+	})
+
+	It("state file does not exist", func() {
+		_, err := LoadUploadState(statePath, "6.6.0")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(HavePrefix("can not read upload state file"))
+	})
+})
@@ -0,0 +1,123 @@
+package service
+
+// UploadSummary is what has been completed by an upload, it is computed
+// from the upload state and the metadata.
+type UploadSummary struct {
+	ReleaseVersion string
+	ReleaseID      int
+	// ReleaseRolledBack is true when the release is created by the upload and deleted by the
+	// rollback, the release which is reused is kept.
+	ReleaseRolledBack bool
+	FileGroups        []FileGroupSummary
+	ProductFiles      []ProductFileSummary
+
+	// StatePath is the path of the state file when the progress is saved for resume
+	StatePath        string
+	Rollback         RollbackStatus
+	RollbackFailures []JournalEntry
+}
+
+type FileGroupSummary struct {
+	Name       string
+	ID         int
+	Attached   bool
+	RolledBack bool
+}
+
+type ProductFileSummary struct {
+	FileGroup     string
+	UploadAs      string
+	Uploaded      bool
+	ProductFileID int
+	Attached      bool
+	RolledBack    bool
+}
+
+func (s ProductFileSummary) Completed() bool {
+	return s.Uploaded && s.ProductFileID != 0 && s.Attached
+}
+
+// Completed returns true when the release is created, and all the file groups
+// and product files are attached to it.
+func (s UploadSummary) Completed() bool {
+	if s.ReleaseID == 0 {
+		return false
+	}
+	for _, group := range s.FileGroups {
+		if !group.Attached {
+			return false
+		}
+	}
+	for _, pf := range s.ProductFiles {
+		if !pf.Completed() {
+			return false
+		}
+	}
+	return true
+}
+
+func (u Uploader) Summary() UploadSummary {
+	if u.State == nil {
+		return UploadSummary{}
+	}
+
+	summary := UploadSummary{
+		ReleaseVersion: u.GpdbVersion,
+		ReleaseID:      u.State.ReleaseID,
+		StatePath:      u.State.Path(),
+	}
+	summary.Rollback, summary.RollbackFailures = u.Journal.Rollback()
+	rolledBack := rolledBackObjects(u.Journal.Entries(), summary.Rollback, summary.RollbackFailures)
+	summary.ReleaseRolledBack = rolledBack(JournalEntryRelease, summary.ReleaseID)
+
+	addProductFile := func(groupName string, uploadAs string) {
+		pf := u.State.ProductFile(productFileStateKey(groupName, uploadAs))
+		summary.ProductFiles = append(summary.ProductFiles, ProductFileSummary{
+			FileGroup:     groupName,
+			UploadAs:      uploadAs,
+			Uploaded:      pf.Uploaded,
+			ProductFileID: pf.ProductFileID,
+			Attached:      pf.Attached,
+			RolledBack:    rolledBack(JournalEntryProductFile, pf.ProductFileID),
+		})
+	}
+
+	for _, group := range u.Metadata.FileGroups {
+		fg := u.State.FileGroup(group.Name)
+		summary.FileGroups = append(summary.FileGroups, FileGroupSummary{
+			Name:       group.Name,
+			ID:         fg.ID,
+			Attached:   fg.AttachedToRelease,
+			RolledBack: rolledBack(JournalEntryFileGroup, fg.ID),
+		})
+		for _, productFile := range group.ProductFiles {
+			addProductFile(group.Name, productFile.UploadAs)
+		}
+	}
+	for _, productFile := range u.Metadata.ProductFiles {
+		addProductFile("", productFile.UploadAs)
+	}
+	return summary
+}
+
+// rolledBackObjects returns whether an object is deleted by the rollback. Only the objects
+// created by the upload are recorded in the journal, the reused ones are never rolled back.
+func rolledBackObjects(entries []JournalEntry, status RollbackStatus, failures []JournalEntry) func(JournalEntryKind, int) bool {
+	type object struct {
+		kind JournalEntryKind
+		id   int
+	}
+	deleted := make(map[object]bool)
+	if status == RolledBack || status == RollbackFailed {
+		for _, entry := range entries {
+			deleted[object{entry.Kind, entry.ID}] = true
+		}
+		for _, entry := range failures {
+			delete(deleted, object{entry.Kind, entry.ID})
+		}
+	}
+
+	return func(kind JournalEntryKind, id int) bool {
+		return id != 0 && deleted[object{kind, id}]
+	}
+}
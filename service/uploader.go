@@ -1,20 +1,15 @@
 package service
 
 import (
+	"context"
 	"fmt"
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/baotingfang/go-pivnet-client/api"
 	"github.com/baotingfang/go-pivnet-client/config"
 	"github.com/baotingfang/go-pivnet-client/gp"
 	. "github.com/baotingfang/go-pivnet-client/utils"
 	"github.com/baotingfang/go-pivnet-client/vlog"
 	"github.com/pivotal-cf/go-pivnet/v4"
-	"io"
 	"os"
-	"path"
 	"strings"
 )
 
@@ -23,80 +18,232 @@ type Uploader struct {
 	Metadata        config.Metadata
 	SearchPath      string
 	AwsObjectPrefix string
-
-	Context  gp.Context
-	Client   api.AccessClient
-	Resolver Resolver
+	NoRollback      bool
+	Parallel        int
+	TransferWait    FileTransferWait
+
+	Context          gp.Context
+	Client           api.AccessClient
+	Resolver         Resolver
+	FederationTokens *FederationTokenProvider
+	ObjectStore      ObjectStore
+	Progress         ProgressReporter
+	Journal          *Journal
+	State            *UploadState
+	Remote           RemoteObjects
 }
 
-func NewUploader(context gp.Context, gpdbVersion string, metadataReader io.Reader, searchPath string) (Uploader, error) {
-	metadata, err := config.MetadataFrom(metadataReader, gpdbVersion)
-	if err != nil {
-		return Uploader{}, err
-	}
+func NewUploader(context gp.Context, gpdbVersion string, metadata config.Metadata, searchPath string) Uploader {
 	metadata.Release.Version = gpdbVersion
 	client := api.NewApiClient(context)
+	federationTokens := NewFederationTokenProvider(client)
 
 	return Uploader{
 		GpdbVersion: gpdbVersion,
 		Metadata:    metadata,
 		SearchPath:  searchPath,
 
-		Context:  context,
-		Client:   client,
-		Resolver: NewResourceResolver(searchPath, FilesWalker{}),
-	}, nil
+		Context:          context,
+		Client:           client,
+		Resolver:         NewResourceResolver(searchPath, FilesWalker{}),
+		FederationTokens: federationTokens,
+		ObjectStore:      NewS3ObjectStore(context.S3, federationTokens),
+		TransferWait:     NewFileTransferWait(),
+		Progress:         NoProgress{},
+		Journal:          NewJournal(),
+		State:            NewUploadState(gpdbVersion, ""),
+	}
 }
 
-func (u Uploader) Run() error {
-	r := u.Metadata.Release
+func (u Uploader) Run(ctx context.Context) error {
+	err := u.validate()
+	if err != nil {
+		return err
+	}
+
+	crc, err := u.NewCreateReleaseConfig(ctx, u.Metadata.Release)
+	if err != nil {
+		return err
+	}
+
+	if u.Journal == nil {
+		u.Journal = NewJournal()
+	}
+	if u.State == nil {
+		u.State = NewUploadState(u.GpdbVersion, "")
+	}
+	if u.FederationTokens == nil {
+		u.FederationTokens = NewFederationTokenProvider(u.Client)
+	}
+	if u.ObjectStore == nil {
+		u.ObjectStore = NewS3ObjectStore(u.Context.S3, u.FederationTokens)
+	}
+	if u.Progress == nil {
+		u.Progress = NoProgress{}
+	}
+
+	federationToken, err := u.upload(ctx, crc)
+	if err != nil {
+		if u.State.Persistent() {
+			vlog.Warn("upload state is saved to %s, the upload can be resumed from it", u.State.Path())
+			vlog.Warn("rollback is skipped, because the upload progress is saved for resume, the following objects are kept:")
+			for _, entry := range u.Journal.Entries() {
+				vlog.Warn("\t%s", entry)
+			}
+			u.Journal.RecordRollback(RollbackSkipped, nil)
+			return err
+		}
+
+		if u.NoRollback {
+			u.Journal.RecordRollback(RollbackDisabled, nil)
+			vlog.Warn("rollback is disabled, the following objects are kept:")
+			for _, entry := range u.Journal.Entries() {
+				vlog.Warn("\t%s", entry)
+			}
+			return err
+		}
+
+		vlog.Error("upload failed, rolling back: %s", err.Error())
+		// the rollback is not cancelled with the upload, so that nothing is left on pivnet
+		rollbackErr := u.Rollback(context.Background(), federationToken)
+		if rollbackErr != nil {
+			return fmt.Errorf("%w. %s", err, rollbackErr.Error())
+		}
+		return err
+	}
+
+	return u.State.Remove()
+}
 
+func (u Uploader) validate() error {
 	mv := NewMetaDataValidator(u.Metadata)
 	if !mv.Validate() {
-		messages := mv.errorMessages
-		fmt.Println(strings.Join(messages, "\n"))
-		return fmt.Errorf("validate metata data failed")
+		return ValidationError{Message: "validate metata data failed", Err: mv.FieldErrors()}
 	}
+	return u.validateAwsObjectKeys()
+}
 
-	crc, err := u.NewCreateReleaseConfig(r)
+func (u Uploader) upload(ctx context.Context, crc pivnet.CreateReleaseConfig) (pivnet.FederationToken, error) {
+	release, created, err := u.handleRelease(ctx, crc)
 	if err != nil {
-		return err
+		return pivnet.FederationToken{}, err
 	}
 
-	release, err := u.Client.CreateRelease(crc)
+	u.Remote = NewRemoteObjects()
+	if !created {
+		u.Remote, err = LoadRemoteObjects(ctx, u.Client, release.ID)
+		if err != nil {
+			return pivnet.FederationToken{}, err
+		}
+	}
+
+	federationToken, err := u.FederationTokens.Token(ctx)
 	if err != nil {
-		return err
+		return pivnet.FederationToken{}, err
 	}
 
-	federationToken, err := u.Client.CreateFederationToken()
+	u.Progress.Start(u.totalBytes())
+	defer u.Progress.Stop()
+
+	err = u.HandleFileGroups(ctx, release, federationToken)
 	if err != nil {
-		return err
+		return federationToken, err
 	}
 
-	err = u.HandleFileGroups(release, federationToken)
+	err = u.HandleProductFiles(ctx, release, federationToken)
 	if err != nil {
-		return err
+		return federationToken, err
 	}
 
-	err = u.HandleProductFiles(release, federationToken)
+	return federationToken, nil
+}
+
+// totalBytes returns the size of all the local files in metadata, it is only used to report
+// the progress, so the files which can not be found are left to fail when they are uploaded.
+func (u Uploader) totalBytes() int64 {
+	var total int64
+	for _, group := range u.Metadata.FileGroups {
+		for _, productFile := range group.ProductFiles {
+			total += u.localFileSize(productFile)
+		}
+	}
+	for _, productFile := range u.Metadata.ProductFiles {
+		total += u.localFileSize(productFile)
+	}
+	return total
+}
+
+func (u Uploader) localFileSize(productFile config.ProductFile) int64 {
+	rv, err := u.Resolver.Resolve(productFile.File)
 	if err != nil {
-		return err
+		return 0
+	}
+	info, err := os.Stat(rv.LocalFilePath)
+	if err != nil {
+		return 0
 	}
+	return info.Size()
+}
+
+// skipProgress reports the file which is not uploaded again as completed
+func (u Uploader) skipProgress(productFile config.ProductFile) {
+	u.Progress.Skip(productFile.UploadAs, u.localFileSize(productFile))
+}
 
+// Rollback removes the objects recorded in the journal in the reverse order.
+// It tries to remove all of them, and reports all the failures at the end.
+// The result is recorded in the journal for the summary of the upload.
+func (u Uploader) Rollback(ctx context.Context, federationToken pivnet.FederationToken) error {
+	var messages []string
+	var failures []JournalEntry
+
+	entries := u.Journal.Entries()
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		vlog.Info("rolling back %s", entry)
+
+		var err error
+		switch entry.Kind {
+		case JournalEntryProductFile:
+			_, err = u.Client.DeleteProductFile(ctx, entry.ID)
+		case JournalEntryS3Object:
+			err = u.ObjectStore.Delete(ctx, federationToken, entry.Key)
+		case JournalEntryFileGroup:
+			_, err = u.Client.DeleteFileGroup(ctx, entry.ID)
+		case JournalEntryRelease:
+			err = u.Client.DeleteRelease(ctx, entry.Release)
+		}
+
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("rollback %s failed: %s", entry, err.Error()))
+			failures = append(failures, entry)
+		}
+	}
+
+	if len(messages) > 0 {
+		u.Journal.RecordRollback(RollbackFailed, failures)
+		return fmt.Errorf("rollback failed:\n%s", strings.Join(messages, "\n"))
+	}
+	u.Journal.RecordRollback(RolledBack, nil)
 	return nil
 }
 
-func (u Uploader) NewCreateReleaseConfig(r config.Release) (pivnet.CreateReleaseConfig, error) {
-	previousMajorRelease, err := u.Client.GetLatestPublicReleaseByReleaseType(
-		r.GpdbMajorVersion(),
+func (u Uploader) NewCreateReleaseConfig(ctx context.Context, r config.Release) (pivnet.CreateReleaseConfig, error) {
+	majorVersion, err := r.GpdbMajorVersion()
+	if err != nil {
+		return pivnet.CreateReleaseConfig{}, err
+	}
+
+	previousMajorRelease, err := u.Client.GetLatestPublicReleaseByReleaseType(ctx,
+		majorVersion,
 		config.MajorReleaseType,
 	)
 	if err != nil {
 		vlog.Warn(err.Error())
 	}
 
-	previousMinorRelease, err := u.Client.GetLatestPublicReleaseByReleaseType(
-		r.GpdbMajorVersion(),
+	previousMinorRelease, err := u.Client.GetLatestPublicReleaseByReleaseType(ctx,
+		majorVersion,
 		config.MinorReleaseType,
 	)
 	if err != nil {
@@ -121,6 +268,10 @@ func (u Uploader) NewCreateReleaseConfig(r config.Release) (pivnet.CreateRelease
 	if err != nil {
 		return pivnet.CreateReleaseConfig{}, err
 	}
+	endOfAvailabilityDate, err := r.ComputeEndOfAvailabilityDate()
+	if err != nil {
+		return pivnet.CreateReleaseConfig{}, err
+	}
 
 	return pivnet.CreateReleaseConfig{
 		ProductSlug:           u.Context.Slug,
@@ -134,7 +285,7 @@ func (u Uploader) NewCreateReleaseConfig(r config.Release) (pivnet.CreateRelease
 		LicenseException:      r.LicenseException,
 		EndOfSupportDate:      endOfSupportDate.String(),
 		EndOfGuidanceDate:     endOfGuidanceDate.String(),
-		EndOfAvailabilityDate: r.ComputeEndOfAvailabilityDate().String(),
+		EndOfAvailabilityDate: endOfAvailabilityDate.String(),
 		CopyMetadata:          false,
 	}, nil
 }
@@ -147,21 +298,31 @@ func (u Uploader) NewCreateProductFileConfig(f config.ProductFile) (pivnet.Creat
 
 	versionReplacer := NewVersionReplacer(resolvedFile)
 
-	description := versionReplacer.Replace(f.Description)
+	description, err := versionReplacer.Replace(f.Description)
+	if err != nil {
+		return pivnet.CreateProductFileConfig{}, err
+	}
 	if Empty(description) {
-		description = versionReplacer.Replace(f.UploadAs)
+		description, err = versionReplacer.Replace(f.UploadAs)
+		if err != nil {
+			return pivnet.CreateProductFileConfig{}, err
+		}
+	}
+	fileVersion, err := versionReplacer.Replace(f.FileVersion)
+	if err != nil {
+		return pivnet.CreateProductFileConfig{}, err
 	}
 
 	return pivnet.CreateProductFileConfig{
-		ProductSlug:   u.Context.Slug,
-		AWSObjectKey:  f.AWSObjectKey,
-		Description:   description,
-		DocsURL:       f.DocsURL,
-		FileType:      f.FileType,
-		FileVersion:   versionReplacer.Replace(f.FileVersion),
-		IncludedFiles: f.IncludedFiles,
-		SHA256:        f.SHA256,
-		//MD5:                f.MD5,
+		ProductSlug:        u.Context.Slug,
+		AWSObjectKey:       f.AWSObjectKey,
+		Description:        description,
+		DocsURL:            f.DocsURL,
+		FileType:           f.FileType,
+		FileVersion:        fileVersion,
+		IncludedFiles:      f.IncludedFiles,
+		SHA256:             f.SHA256,
+		MD5:                f.MD5,
 		Name:               f.Name,
 		Platforms:          f.Platforms,
 		ReleasedAt:         f.ReleasedAt,
@@ -169,100 +330,322 @@ func (u Uploader) NewCreateProductFileConfig(f config.ProductFile) (pivnet.Creat
 	}, nil
 }
 
-func (u Uploader) HandleFileGroups(release pivnet.Release, federationToken pivnet.FederationToken) error {
+// handleRelease returns the release in upload state, or the release of the same
+// version on pivnet, and only creates the release when it does not exist.
+func (u Uploader) handleRelease(ctx context.Context, crc pivnet.CreateReleaseConfig) (release pivnet.Release, created bool, err error) {
+	release, err = u.Client.GetReleaseByVersion(ctx, crc.Version)
+	if err == nil {
+		if u.State.ReleaseID != 0 && release.ID != u.State.ReleaseID {
+			return pivnet.Release{}, false, NewConflictError("release %s on pivnet (id=%d) is not the release in upload state (id=%d)",
+				crc.Version, release.ID, u.State.ReleaseID)
+		}
+		vlog.Info("reuse release: %s (id=%d)", release.Version, release.ID)
+		return release, false, u.State.SetRelease(release.ID)
+	}
+
+	if _, notFound := err.(api.NotFoundError); !notFound {
+		return pivnet.Release{}, false, err
+	}
+
+	if u.State.ReleaseID != 0 {
+		return pivnet.Release{}, false, NewNotFoundError("release in upload state (id=%d) does not exist on pivnet", u.State.ReleaseID)
+	}
+
+	release, err = u.Client.CreateRelease(ctx, crc)
+	if err != nil {
+		return pivnet.Release{}, false, err
+	}
+	u.Journal.RecordRelease(release)
+
+	return release, true, u.State.SetRelease(release.ID)
+}
+
+func (u Uploader) HandleFileGroups(ctx context.Context, release pivnet.Release, federationToken pivnet.FederationToken) error {
+	// the file groups are created one by one, then the product files of all groups are uploaded in parallel
+	var jobs []func() error
 	fileGroups := u.Metadata.FileGroups
 	for _, group := range fileGroups {
-		g, err := u.Client.CreateFileGroup(group.Name)
-		if err != nil {
-			return err
-		}
-		err = u.Client.AddFileGroupToRelease(g.ID, release.ID)
-		if err != nil {
-			return err
+		groupState := u.State.FileGroup(group.Name)
+
+		groupId := groupState.ID
+		if existingGroup, ok := u.Remote.FileGroup(group.Name); groupId == 0 && ok {
+			vlog.Info("reuse file group: %s (id=%d)", existingGroup.Name, existingGroup.ID)
+			groupId = existingGroup.ID
+			groupState.AttachedToRelease = true
+
+			err := u.State.UpdateFileGroup(group.Name, func(fg *FileGroupState) {
+				fg.ID = existingGroup.ID
+				fg.AttachedToRelease = true
+			})
+			if err != nil {
+				return err
+			}
 		}
 
-		for _, productFile := range group.ProductFiles {
-			updatedProductFile, err := u.uploadToS3(productFile, federationToken)
+		if groupId == 0 {
+			g, err := u.Client.CreateFileGroup(ctx, group.Name)
 			if err != nil {
 				return err
 			}
-			cpfc, err := u.NewCreateProductFileConfig(updatedProductFile)
+			u.Journal.RecordFileGroup(g)
+			groupId = g.ID
+
+			err = u.State.UpdateFileGroup(group.Name, func(fg *FileGroupState) {
+				fg.ID = g.ID
+			})
 			if err != nil {
 				return err
 			}
-			pf, err := u.Client.CreateProductFile(cpfc)
+		}
+
+		if !groupState.AttachedToRelease {
+			err := u.Client.AddFileGroupToRelease(ctx, groupId, release.ID)
 			if err != nil {
 				return err
 			}
-			err = u.Client.AddProductFileToFileGroup(pf.ID, g.ID)
+			err = u.State.UpdateFileGroup(group.Name, func(fg *FileGroupState) {
+				fg.AttachedToRelease = true
+			})
 			if err != nil {
 				return err
 			}
 		}
+
+		for _, productFile := range group.ProductFiles {
+			groupName, productFile := group.Name, productFile
+			jobs = append(jobs, func() error {
+				return u.handleProductFile(ctx, groupName, productFile, federationToken, func(productFileId int) error {
+					return u.Client.AddProductFileToFileGroup(ctx, productFileId, groupId)
+				})
+			})
+		}
 	}
-	return nil
+	return runParallel(ctx, u.Parallel, jobs)
 }
 
-func (u Uploader) HandleProductFiles(release pivnet.Release, federationToken pivnet.FederationToken) error {
+func (u Uploader) HandleProductFiles(ctx context.Context, release pivnet.Release, federationToken pivnet.FederationToken) error {
+	var jobs []func() error
 	for _, f := range u.Metadata.ProductFiles {
-		updatedPf, err := u.uploadToS3(f, federationToken)
+		f := f
+		jobs = append(jobs, func() error {
+			return u.handleProductFile(ctx, "", f, federationToken, func(productFileId int) error {
+				return u.Client.AddProductFileToRelease(ctx, productFileId, release.ID)
+			})
+		})
+	}
+	return runParallel(ctx, u.Parallel, jobs)
+}
+
+// handleProductFile uploads the file to s3, creates the product file and attaches it,
+// the steps which are already completed in the upload state are skipped.
+func (u Uploader) handleProductFile(ctx context.Context, groupName string, productFile config.ProductFile, federationToken pivnet.FederationToken,
+	attach func(productFileId int) error) error {
+
+	key := productFileStateKey(groupName, productFile.UploadAs)
+	pfState := u.State.ProductFile(key)
+
+	if pfState == (ProductFileState{}) {
+		existing, found, err := u.findExistingProductFile(ctx, groupName, productFile)
 		if err != nil {
 			return err
 		}
+		if found {
+			vlog.Info("reuse product file: %s (id=%d)", existing.Name, existing.ID)
+			u.skipProgress(productFile)
+			return u.State.UpdateProductFile(key, func(pf *ProductFileState) {
+				pf.AwsObjectKey = existing.AWSObjectKey
+				pf.Uploaded = true
+				pf.ProductFileID = existing.ID
+				pf.Attached = true
+			})
+		}
+	}
+
+	if !pfState.Uploaded {
+		updatedProductFile, err := u.uploadFile(ctx, groupName, productFile, federationToken)
+		if err != nil {
+			return err
+		}
+		pfState.AwsObjectKey = updatedProductFile.AWSObjectKey
+		pfState.SHA256 = updatedProductFile.SHA256
+		pfState.MD5 = updatedProductFile.MD5
+		pfState.Uploaded = true
+
+		err = u.State.UpdateProductFile(key, func(pf *ProductFileState) {
+			pf.AwsObjectKey = pfState.AwsObjectKey
+			pf.SHA256 = pfState.SHA256
+			pf.MD5 = pfState.MD5
+			pf.Uploaded = true
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		vlog.Info("skip uploading %s, it is already uploaded to %s", productFile.File, pfState.AwsObjectKey)
+		u.skipProgress(productFile)
+	}
+	productFile.AWSObjectKey = pfState.AwsObjectKey
+
+	if pfState.ProductFileID == 0 {
+		if Empty(pfState.SHA256) {
+			// the upload state is saved by an older version without checksums
+			sums, err := u.localChecksums(productFile)
+			if err != nil {
+				return err
+			}
+			pfState.SHA256 = sums.SHA256
+			pfState.MD5 = sums.MD5
+		}
+		productFile.SHA256 = pfState.SHA256
+		productFile.MD5 = pfState.MD5
 
-		cpfc, err := u.NewCreateProductFileConfig(updatedPf)
+		cpfc, err := u.NewCreateProductFileConfig(productFile)
+		if err != nil {
+			return err
+		}
+		pf, err := u.Client.CreateProductFile(ctx, cpfc)
 		if err != nil {
 			return err
 		}
+		u.Journal.RecordProductFile(pf)
+		pfState.ProductFileID = pf.ID
+
+		err = u.State.UpdateProductFile(key, func(pf *ProductFileState) {
+			pf.ProductFileID = pfState.ProductFileID
+		})
+		if err != nil {
+			return err
+		}
+	}
 
-		pf, err := u.Client.CreateProductFile(cpfc)
+	if !pfState.Attached {
+		// pivnet can not add the product file to file group or release until the file transfer is completed
+		err := u.TransferWait.WaitFor(ctx, u.Client, pfState.ProductFileID)
 		if err != nil {
 			return err
 		}
 
-		err = u.Client.AddProductFileToRelease(pf.ID, release.ID)
+		err = attach(pfState.ProductFileID)
 		if err != nil {
 			return err
 		}
+		return u.State.UpdateProductFile(key, func(pf *ProductFileState) {
+			pf.Attached = true
+		})
 	}
+
 	return nil
 }
 
-func (u Uploader) uploadToS3(productFile config.ProductFile, federationToken pivnet.FederationToken) (config.ProductFile, error) {
+// findExistingProductFile finds the product file on pivnet with the same aws object key,
+// it is an error if the sha256 of the existing product file is different from the local file.
+func (u Uploader) findExistingProductFile(ctx context.Context, groupName string, productFile config.ProductFile) (pivnet.ProductFile, bool, error) {
 	rv, err := u.Resolver.Resolve(productFile.File)
 	if err != nil {
-		return config.ProductFile{}, err
+		return pivnet.ProductFile{}, false, err
+	}
+
+	key, err := u.awsObjectKey(groupName, productFile, rv)
+	if err != nil {
+		return pivnet.ProductFile{}, false, err
+	}
+
+	existing, found := u.Remote.ProductFile(groupName, key)
+	if !found {
+		return pivnet.ProductFile{}, false, nil
 	}
 
-	sess := session.Must(session.NewSession(&aws.Config{
-		Region: aws.String(federationToken.Region),
-		Credentials: credentials.NewStaticCredentials(
-			federationToken.AccessKeyID,
-			federationToken.SecretAccessKey,
-			federationToken.SessionToken,
-		),
-	}))
+	if Empty(existing.SHA256) {
+		existing, err = u.Client.GetProductFile(ctx, existing.ID)
+		if err != nil {
+			return pivnet.ProductFile{}, false, err
+		}
+	}
 
-	uploader := s3manager.NewUploader(sess)
+	sums, err := u.localChecksums(productFile)
+	if err != nil {
+		return pivnet.ProductFile{}, false, err
+	}
+
+	if !strings.EqualFold(existing.SHA256, sums.SHA256) {
+		return pivnet.ProductFile{}, false,
+			NewConflictError("product file %s (id=%d) already exists with aws object key %s, but its sha256 is different from %s",
+				existing.Name, existing.ID, existing.AWSObjectKey, rv.LocalFilePath)
+	}
+
+	return existing, true, nil
+}
+
+// localChecksums computes the checksums of the resolved local file,
+// and verifies them against the checksums supplied in metadata.
+func (u Uploader) localChecksums(productFile config.ProductFile) (Checksums, error) {
+	rv, err := u.Resolver.Resolve(productFile.File)
+	if err != nil {
+		return Checksums{}, err
+	}
+
+	sums, err := FileChecksums(rv.LocalFilePath)
+	if err != nil {
+		return Checksums{}, err
+	}
+	return sums, verifyChecksums(productFile, rv.LocalFilePath, sums)
+}
+
+// verifyChecksums returns error if a checksum supplied in metadata is different
+// from the computed one, the checksums in metadata are optional.
+func verifyChecksums(productFile config.ProductFile, localFilePath string, sums Checksums) error {
+	if !Empty(productFile.SHA256) && !strings.EqualFold(productFile.SHA256, sums.SHA256) {
+		return NewConflictError("sha256 of %s is %s, but it is %s in metadata", localFilePath, sums.SHA256, productFile.SHA256)
+	}
+	if !Empty(productFile.MD5) && !strings.EqualFold(productFile.MD5, sums.MD5) {
+		return NewConflictError("md5 of %s is %s, but it is %s in metadata", localFilePath, sums.MD5, productFile.MD5)
+	}
+	return nil
+}
+
+// uploadFile uploads the local file of the product file to the object store
+func (u Uploader) uploadFile(ctx context.Context, groupName string, productFile config.ProductFile, federationToken pivnet.FederationToken) (config.ProductFile, error) {
+	rv, err := u.Resolver.Resolve(productFile.File)
+	if err != nil {
+		return config.ProductFile{}, err
+	}
+
+	AwsObjectKey, err := u.awsObjectKey(groupName, productFile, rv)
+	if err != nil {
+		return config.ProductFile{}, err
+	}
 
 	f, err := os.Open(rv.LocalFilePath)
 	if err != nil {
-		return config.ProductFile{}, fmt.Errorf("failed to open file %q, %v", rv.LocalFilePath, err)
+		return config.ProductFile{}, NewStorageError(err, "failed to open file %q", rv.LocalFilePath)
 	}
+	defer f.Close()
 
-	AwsObjectKey := path.Join(u.AwsObjectPrefix, rv.LocalFileName)
-	result, err := uploader.Upload(&s3manager.UploadInput{
-		Bucket: aws.String(federationToken.Bucket),
-		Key:    aws.String(AwsObjectKey),
-		Body:   f,
-	})
+	info, err := f.Stat()
 	if err != nil {
-		return config.ProductFile{}, fmt.Errorf("failed to upload file, %v", err)
+		return config.ProductFile{}, NewStorageError(err, "failed to stat file %q", rv.LocalFilePath)
 	}
 
-	vlog.Info("file uploaded to, %s\n", result.Location)
+	// the checksums are computed while the file is streamed to the object store,
+	// the reader is not seekable, so the parts of the file are read in order.
+	body := NewChecksumReader(u.Progress.Track(productFile.UploadAs, info.Size(), f))
+
+	location, err := u.ObjectStore.Put(ctx, federationToken, AwsObjectKey, body)
+	if err != nil {
+		return config.ProductFile{}, NewStorageError(err, "failed to upload file")
+	}
+
+	vlog.Info("file uploaded to, %s\n", location)
+	u.Journal.RecordS3Object(federationToken.Bucket, AwsObjectKey)
+
+	sums := body.Checksums()
+	if err := verifyChecksums(productFile, rv.LocalFilePath, sums); err != nil {
+		return config.ProductFile{}, err
+	}
 
 	productFile.AWSObjectKey = AwsObjectKey
+	productFile.SHA256 = sums.SHA256
+	productFile.MD5 = sums.MD5
 
 	return productFile, nil
 }
@@ -277,14 +660,14 @@ func NewVersionReplacer(resolvedFile ResolvedFile) VersionReplacer {
 	}
 }
 
-func (vr VersionReplacer) Replace(expression string) string {
+func (vr VersionReplacer) Replace(expression string) (string, error) {
 	if !strings.Contains(expression, `${VERSION_REGEX}`) {
-		return expression
+		return expression, nil
 	}
 
 	if vr.resolvedFile.ResolvedVersion.Empty() {
-		vlog.Fatal("resolved version is empty")
+		return "", NewValidationError("resolved version is empty. file: %s", vr.resolvedFile.LocalFilePath)
 	}
 
-	return strings.ReplaceAll(expression, `${VERSION_REGEX}`, vr.resolvedFile.ResolvedVersion.String())
+	return strings.ReplaceAll(expression, `${VERSION_REGEX}`, vr.resolvedFile.ResolvedVersion.String()), nil
 }
@@ -1,13 +1,47 @@
 package service_test
 
 import (
+	"context"
+	"errors"
+	"github.com/baotingfang/go-pivnet-client/api"
+	"github.com/baotingfang/go-pivnet-client/api/apifakes"
+	"github.com/baotingfang/go-pivnet-client/config"
+	"github.com/baotingfang/go-pivnet-client/service/servicefakes"
+	"github.com/baotingfang/go-pivnet-client/utils"
 	semver "github.com/cppforlife/go-semi-semantic/version"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	. "github.com/baotingfang/go-pivnet-client/service"
 )
 
+// newFakeResolver resolves every file to the same local file, but with different
+// file names, so that the product files are uploaded to different aws object keys.
+func newFakeResolver(localFile string) *servicefakes.FakeResolver {
+	fakeResolver := &servicefakes.FakeResolver{}
+	fakeResolver.ResolveStub = func(file string) (ResolvedFile, error) {
+		fileUrl, err := url.Parse(file)
+		if err != nil {
+			return ResolvedFile{}, err
+		}
+		return ResolvedFile{
+			LocalFilePath:   localFile,
+			LocalFileName:   fileUrl.Host + ".txt",
+			ResolvedVersion: semver.MustNewVersionFromString("6.6.0"),
+		}, nil
+	}
+	return fakeResolver
+}
+
 var _ = Describe("Uploader", func() {
 	Context("NewVersionReplacer", func() {
 		It("NewVersionReplacer: empty string", func() {
@@ -37,10 +71,9 @@ var _ = Describe("Uploader", func() {
 				ResolvedVersion: semver.Version{},
 			}
 			vr := NewVersionReplacer(resolvedFile)
-			f := func() {
-				vr.Replace("abc-${VERSION_REGEX}")
-			}
-			Expect(f).To(PanicWith(`[Default Logger][FATAL] resolved version is empty`))
+			_, err := vr.Replace("abc-${VERSION_REGEX}")
+			Expect(err).To(MatchError("resolved version is empty. file: /tmp/path/file-1.0.0.txt"))
+			Expect(err).To(BeAssignableToTypeOf(utils.ValidationError{}))
 		})
 
 		It("NewVersionReplacer: correct replace action", func() {
@@ -54,4 +87,484 @@ var _ = Describe("Uploader", func() {
 		})
 
 	})
+
+	Context("Rollback", func() {
+		var (
+			fakeClient *apifakes.FakeAccessClient
+			uploader   Uploader
+		)
+
+		BeforeEach(func() {
+			fakeClient = &apifakes.FakeAccessClient{}
+			uploader = Uploader{
+				Client:  fakeClient,
+				Journal: NewJournal(),
+			}
+			uploader.Journal.RecordRelease(pivnet.Release{ID: 1, Version: "6.6.0"})
+			uploader.Journal.RecordFileGroup(pivnet.FileGroup{ID: 2, Name: "server"})
+			uploader.Journal.RecordProductFile(pivnet.ProductFile{ID: 3, Name: "rhel6"})
+			uploader.Journal.RecordProductFile(pivnet.ProductFile{ID: 4, Name: "rhel7"})
+		})
+
+		It("removes the created objects in the reverse order", func() {
+			err := uploader.Rollback(ctx, pivnet.FederationToken{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeClient.Invocations()).To(HaveLen(3))
+			Expect(fakeClient.DeleteProductFileCallCount()).To(Equal(2))
+			_, productFileId := fakeClient.DeleteProductFileArgsForCall(0)
+			Expect(productFileId).To(Equal(4))
+			_, productFileId = fakeClient.DeleteProductFileArgsForCall(1)
+			Expect(productFileId).To(Equal(3))
+			_, fileGroupId := fakeClient.DeleteFileGroupArgsForCall(0)
+			Expect(fileGroupId).To(Equal(2))
+			_, release := fakeClient.DeleteReleaseArgsForCall(0)
+			Expect(release.ID).To(Equal(1))
+
+			status, failures := uploader.Journal.Rollback()
+			Expect(status).To(Equal(RolledBack))
+			Expect(failures).To(BeEmpty())
+		})
+
+		It("continues the rollback when it failed to remove an object", func() {
+			fakeClient.DeleteFileGroupReturns(pivnet.FileGroup{}, errors.New("server error"))
+
+			err := uploader.Rollback(ctx, pivnet.FederationToken{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("rollback failed:\nrollback file group server (id=2) failed: server error"))
+			Expect(fakeClient.DeleteReleaseCallCount()).To(Equal(1))
+
+			status, failures := uploader.Journal.Rollback()
+			Expect(status).To(Equal(RollbackFailed))
+			Expect(failures).To(HaveLen(1))
+			Expect(failures[0].String()).To(Equal("file group server (id=2)"))
+		})
+	})
+
+	Context("Resume", func() {
+		It("skips the completed steps in upload state", func() {
+			fakeClient := &apifakes.FakeAccessClient{}
+			fakeClient.GetReleaseByVersionReturns(pivnet.Release{ID: 10, Version: "6.6.0"}, nil)
+
+			metadata, err := config.MetadataFrom(strings.NewReader(metadataYaml), "6.6.0")
+			Expect(err).NotTo(HaveOccurred())
+
+			state := NewUploadState("6.6.0", "")
+			Expect(state.SetRelease(10)).To(Succeed())
+			Expect(state.UpdateFileGroup("Greenplum Database Server", func(fg *FileGroupState) {
+				fg.ID = 20
+				fg.AttachedToRelease = true
+			})).To(Succeed())
+
+			var id = 30
+			for _, group := range metadata.FileGroups {
+				for _, pf := range group.ProductFiles {
+					productFileId := id
+					Expect(state.UpdateProductFile(group.Name+"/"+pf.UploadAs, func(pfs *ProductFileState) {
+						pfs.Uploaded = true
+						pfs.ProductFileID = productFileId
+						pfs.Attached = productFileId != 31
+					})).To(Succeed())
+					id++
+				}
+			}
+			for _, pf := range metadata.ProductFiles {
+				Expect(state.UpdateProductFile("/"+pf.UploadAs, func(pfs *ProductFileState) {
+					pfs.Uploaded = true
+					pfs.ProductFileID = 40
+					pfs.Attached = true
+				})).To(Succeed())
+			}
+
+			uploader := Uploader{
+				GpdbVersion: "6.6.0",
+				Metadata:    metadata,
+				Client:      fakeClient,
+				Resolver:    newFakeResolver("/tmp/path/file.txt"),
+				State:       state,
+			}
+
+			err = uploader.Run(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.CreateReleaseCallCount()).To(Equal(0))
+			Expect(fakeClient.CreateFileGroupCallCount()).To(Equal(0))
+			Expect(fakeClient.AddFileGroupToReleaseCallCount()).To(Equal(0))
+			Expect(fakeClient.CreateProductFileCallCount()).To(Equal(0))
+			Expect(fakeClient.AddProductFileToReleaseCallCount()).To(Equal(0))
+			Expect(fakeClient.AddProductFileToFileGroupCallCount()).To(Equal(1))
+			_, productFileId, fileGroupId := fakeClient.AddProductFileToFileGroupArgsForCall(0)
+			Expect(productFileId).To(Equal(31))
+			Expect(fileGroupId).To(Equal(20))
+		})
+	})
+
+	Context("Parallel", func() {
+		var (
+			fakeClient *apifakes.FakeAccessClient
+			uploader   Uploader
+			tmpDir     string
+		)
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "uploader-test")
+			Expect(err).NotTo(HaveOccurred())
+			localFile := filepath.Join(tmpDir, "file.txt")
+			Expect(ioutil.WriteFile(localFile, []byte("hello"), 0644)).To(Succeed())
+
+			fakeClient = &apifakes.FakeAccessClient{}
+			fakeClient.GetReleaseByVersionReturns(pivnet.Release{ID: 10, Version: "6.6.0"}, nil)
+			fakeClient.CreateFileGroupReturns(pivnet.FileGroup{ID: 20}, nil)
+
+			metadata, err := config.MetadataFrom(strings.NewReader(metadataYaml), "6.6.0")
+			Expect(err).NotTo(HaveOccurred())
+
+			// all files are already uploaded to s3, only the product files are created
+			state := NewUploadState("6.6.0", "")
+			for _, group := range metadata.FileGroups {
+				for _, pf := range group.ProductFiles {
+					Expect(state.UpdateProductFile(group.Name+"/"+pf.UploadAs, func(pfs *ProductFileState) {
+						pfs.AwsObjectKey = "file.txt"
+						pfs.Uploaded = true
+					})).To(Succeed())
+				}
+			}
+			for _, pf := range metadata.ProductFiles {
+				Expect(state.UpdateProductFile("/"+pf.UploadAs, func(pfs *ProductFileState) {
+					pfs.AwsObjectKey = "file.txt"
+					pfs.Uploaded = true
+				})).To(Succeed())
+			}
+
+			uploader = Uploader{
+				GpdbVersion: "6.6.0",
+				Metadata:    metadata,
+				Parallel:    4,
+				Client:      fakeClient,
+				Resolver:    newFakeResolver(localFile),
+				State:       state,
+			}
+		})
+
+		AfterEach(func() {
+			_ = os.RemoveAll(tmpDir)
+		})
+
+		It("attaches the product files to their file group and release", func() {
+			var id int32 = 100
+			fakeClient.CreateProductFileStub = func(_ context.Context, config pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+				return pivnet.ProductFile{ID: int(atomic.AddInt32(&id, 1))}, nil
+			}
+
+			err := uploader.Run(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.CreateProductFileCallCount()).To(Equal(4))
+
+			Expect(fakeClient.AddProductFileToFileGroupCallCount()).To(Equal(2))
+			var productFileIds []int
+			for i := 0; i < 2; i++ {
+				_, productFileId, fileGroupId := fakeClient.AddProductFileToFileGroupArgsForCall(i)
+				Expect(fileGroupId).To(Equal(20))
+				productFileIds = append(productFileIds, productFileId)
+			}
+			Expect(fakeClient.AddProductFileToReleaseCallCount()).To(Equal(2))
+			for i := 0; i < 2; i++ {
+				_, productFileId, releaseId := fakeClient.AddProductFileToReleaseArgsForCall(i)
+				Expect(releaseId).To(Equal(10))
+				productFileIds = append(productFileIds, productFileId)
+			}
+			Expect(productFileIds).To(ConsistOf(101, 102, 103, 104))
+		})
+
+		It("aggregates the errors of the product files uploaded at the same time", func() {
+			// both product files of the file group fail after they are being created at the same time
+			var inFlight sync.WaitGroup
+			inFlight.Add(2)
+			allStarted := make(chan struct{})
+			go func() {
+				inFlight.Wait()
+				close(allStarted)
+			}()
+			fakeClient.CreateProductFileStub = func(_ context.Context, config pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+				inFlight.Done()
+				select {
+				case <-allStarted:
+				case <-time.After(5 * time.Second):
+				}
+				return pivnet.ProductFile{}, errors.New("server error: " + config.Description)
+			}
+
+			err := uploader.Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("2 product files failed:\n"))
+			Expect(err.Error()).To(ContainSubstring("server error: Greenplum Database 6.6.0 Installer for RHEL 6"))
+			Expect(err.Error()).To(ContainSubstring("server error: Greenplum Database 6.6.0 Installer for RHEL 7"))
+			Expect(fakeClient.AddProductFileToReleaseCallCount()).To(Equal(0))
+		})
+
+		It("attaches the product files after the file transfer is completed", func() {
+			uploader.Parallel = 1
+			uploader.TransferWait = FileTransferWait{Interval: time.Millisecond, Timeout: time.Second}
+			fakeClient.CreateProductFileReturns(pivnet.ProductFile{ID: 100}, nil)
+			fakeClient.FileTransferStatusInProgressReturnsOnCall(0, true, nil)
+			fakeClient.FileTransferStatusInProgressReturns(false, nil)
+
+			err := uploader.Run(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.FileTransferStatusInProgressCallCount()).To(Equal(5))
+			Expect(fakeClient.AddProductFileToFileGroupCallCount()).To(Equal(2))
+			Expect(fakeClient.AddProductFileToReleaseCallCount()).To(Equal(2))
+		})
+
+		It("does not attach the product file when the file transfer failed", func() {
+			fakeClient.CreateProductFileReturns(pivnet.ProductFile{ID: 100}, nil)
+			fakeClient.FileTransferStatusInProgressReturns(false, errors.New("server error"))
+
+			err := uploader.Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("can not get file transfer status of product file (id=100): server error"))
+			Expect(fakeClient.AddProductFileToFileGroupCallCount()).To(Equal(0))
+			Expect(fakeClient.AddProductFileToReleaseCallCount()).To(Equal(0))
+		})
+
+		It("stops at the first error when uploading one by one", func() {
+			uploader.Parallel = 1
+			fakeClient.CreateProductFileReturns(pivnet.ProductFile{}, errors.New("server error"))
+
+			err := uploader.Run(ctx)
+			Expect(err).To(MatchError("server error"))
+			Expect(fakeClient.CreateProductFileCallCount()).To(Equal(1))
+		})
+
+		It("stops uploading the product files when the context is cancelled", func() {
+			uploader.Parallel = 1
+			cancelCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			fakeClient.CreateProductFileStub = func(_ context.Context, config pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+				cancel()
+				return pivnet.ProductFile{ID: 100}, nil
+			}
+
+			err := uploader.Run(cancelCtx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(context.Canceled.Error()))
+			Expect(fakeClient.CreateProductFileCallCount()).To(Equal(1))
+			Expect(uploader.Summary().Completed()).To(BeFalse())
+		})
+	})
+
+	Context("Upload to local object store", func() {
+		var (
+			fakeClient *apifakes.FakeAccessClient
+			uploader   Uploader
+			tmpDir     string
+			storeDir   string
+		)
+
+		const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "uploader-test")
+			Expect(err).NotTo(HaveOccurred())
+			localFile := filepath.Join(tmpDir, "file.txt")
+			Expect(ioutil.WriteFile(localFile, []byte("hello"), 0644)).To(Succeed())
+			storeDir = filepath.Join(tmpDir, "store")
+
+			fakeClient = &apifakes.FakeAccessClient{}
+			fakeClient.GetReleaseByVersionReturns(pivnet.Release{}, api.NotFoundError{Message: "not found"})
+			fakeClient.CreateReleaseReturns(pivnet.Release{ID: 10, Version: "6.6.0"}, nil)
+			fakeClient.CreateFederationTokenReturns(pivnet.FederationToken{Bucket: "fakebucket"}, nil)
+			fakeClient.CreateFileGroupReturns(pivnet.FileGroup{ID: 20}, nil)
+			fakeClient.CreateProductFileReturns(pivnet.ProductFile{ID: 30}, nil)
+
+			metadata, err := config.MetadataFrom(strings.NewReader(metadataYaml), "6.6.0")
+			Expect(err).NotTo(HaveOccurred())
+			metadata.AwsObjectKey = "${GPDB_VERSION}/${FILE_NAME}"
+
+			uploader = Uploader{
+				GpdbVersion: "6.6.0",
+				Metadata:    metadata,
+				Client:      fakeClient,
+				Resolver:    newFakeResolver(localFile),
+				ObjectStore: NewLocalObjectStore(storeDir),
+			}
+		})
+
+		AfterEach(func() {
+			_ = os.RemoveAll(tmpDir)
+		})
+
+		It("uploads the product files to the object store", func() {
+			err := uploader.Run(ctx)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, name := range []string{"server-rhel6.txt", "server-rhel7.txt", "gpdb-osl.txt", "pl-extensions-osl.txt"} {
+				content, err := ioutil.ReadFile(filepath.Join(storeDir, "fakebucket", "6.6.0", name))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(Equal("hello"))
+			}
+
+			Expect(fakeClient.CreateProductFileCallCount()).To(Equal(4))
+			_, cpfc := fakeClient.CreateProductFileArgsForCall(0)
+			Expect(cpfc.AWSObjectKey).To(Equal("6.6.0/server-rhel6.txt"))
+			Expect(cpfc.SHA256).To(Equal(helloSHA256))
+			Expect(fakeClient.AddProductFileToFileGroupCallCount()).To(Equal(2))
+			Expect(fakeClient.AddProductFileToReleaseCallCount()).To(Equal(2))
+		})
+
+		It("removes the uploaded objects when the upload failed", func() {
+			fakeClient.CreateProductFileReturns(pivnet.ProductFile{}, errors.New("server error"))
+
+			err := uploader.Run(ctx)
+			Expect(err).To(MatchError("server error"))
+
+			_, err = os.Stat(filepath.Join(storeDir, "fakebucket", "6.6.0", "server-rhel6.txt"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			Expect(fakeClient.DeleteFileGroupCallCount()).To(Equal(1))
+			Expect(fakeClient.DeleteReleaseCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("Reuse existing objects", func() {
+		var (
+			fakeClient   *apifakes.FakeAccessClient
+			fakeResolver *servicefakes.FakeResolver
+			uploader     Uploader
+			tmpDir       string
+		)
+
+		const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+		BeforeEach(func() {
+			var err error
+			tmpDir, err = ioutil.TempDir("", "uploader-test")
+			Expect(err).NotTo(HaveOccurred())
+			localFile := filepath.Join(tmpDir, "file.txt")
+			Expect(ioutil.WriteFile(localFile, []byte("hello"), 0644)).To(Succeed())
+
+			fakeResolver = newFakeResolver(localFile)
+
+			fakeClient = &apifakes.FakeAccessClient{}
+			fakeClient.GetReleaseByVersionReturns(pivnet.Release{ID: 10, Version: "6.6.0"}, nil)
+
+			metadata, err := config.MetadataFrom(strings.NewReader(metadataYaml), "6.6.0")
+			Expect(err).NotTo(HaveOccurred())
+
+			uploader = Uploader{
+				GpdbVersion: "6.6.0",
+				Metadata:    metadata,
+				Client:      fakeClient,
+				Resolver:    fakeResolver,
+			}
+		})
+
+		AfterEach(func() {
+			_ = os.RemoveAll(tmpDir)
+		})
+
+		It("does not create anything when the release is already uploaded", func() {
+			fakeClient.GetFileGroupsForReleaseReturns([]pivnet.FileGroup{
+				{
+					ID:   20,
+					Name: "Greenplum Database Server",
+					ProductFiles: []pivnet.ProductFile{
+						{ID: 30, AWSObjectKey: "server-rhel6.txt", SHA256: helloSHA256},
+						{ID: 31, AWSObjectKey: "server-rhel7.txt", SHA256: helloSHA256},
+					},
+				},
+			}, nil)
+			fakeClient.GetProductFilesForReleaseReturns([]pivnet.ProductFile{
+				{ID: 40, AWSObjectKey: "gpdb-osl.txt"},
+				{ID: 41, AWSObjectKey: "pl-extensions-osl.txt"},
+			}, nil)
+			fakeClient.GetProductFileStub = func(_ context.Context, productFileId int) (pivnet.ProductFile, error) {
+				return pivnet.ProductFile{ID: productFileId, SHA256: helloSHA256}, nil
+			}
+
+			err := uploader.Run(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			_, releaseId := fakeClient.GetFileGroupsForReleaseArgsForCall(0)
+			Expect(releaseId).To(Equal(10))
+			_, productFileId := fakeClient.GetProductFileArgsForCall(0)
+			Expect(productFileId).To(Equal(40))
+			Expect(fakeClient.CreateReleaseCallCount()).To(Equal(0))
+			Expect(fakeClient.CreateFileGroupCallCount()).To(Equal(0))
+			Expect(fakeClient.AddFileGroupToReleaseCallCount()).To(Equal(0))
+			Expect(fakeClient.CreateProductFileCallCount()).To(Equal(0))
+			Expect(fakeClient.AddProductFileToFileGroupCallCount()).To(Equal(0))
+			Expect(fakeClient.AddProductFileToReleaseCallCount()).To(Equal(0))
+		})
+
+		It("existing product file has a different sha256", func() {
+			fakeClient.GetFileGroupsForReleaseReturns([]pivnet.FileGroup{
+				{
+					ID:   20,
+					Name: "Greenplum Database Server",
+					ProductFiles: []pivnet.ProductFile{
+						{ID: 30, Name: "rhel6", AWSObjectKey: "server-rhel6.txt", SHA256: "abc"},
+					},
+				},
+			}, nil)
+
+			err := uploader.Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("product file rhel6 (id=30) already exists with aws object key server-rhel6.txt, but its sha256 is different"))
+			Expect(fakeClient.DeleteReleaseCallCount()).To(Equal(0))
+			Expect(fakeClient.DeleteFileGroupCallCount()).To(Equal(0))
+		})
+
+		It("local file has a different sha256 from metadata", func() {
+			fakeClient.GetFileGroupsForReleaseReturns([]pivnet.FileGroup{
+				{
+					ID:   20,
+					Name: "Greenplum Database Server",
+					ProductFiles: []pivnet.ProductFile{
+						{ID: 30, Name: "rhel6", AWSObjectKey: "server-rhel6.txt", SHA256: helloSHA256},
+					},
+				},
+			}, nil)
+			uploader.Metadata.FileGroups[0].ProductFiles[0].SHA256 = "abc"
+
+			err := uploader.Run(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(MatchRegexp("sha256 of .*file.txt is %s, but it is abc in metadata", helloSHA256))
+		})
+
+		It("creates the product files with checksums of the local files", func() {
+			state := NewUploadState("6.6.0", "")
+			Expect(state.SetRelease(10)).To(Succeed())
+			for _, group := range uploader.Metadata.FileGroups {
+				for _, pf := range group.ProductFiles {
+					Expect(state.UpdateProductFile(group.Name+"/"+pf.UploadAs, func(pfs *ProductFileState) {
+						pfs.AwsObjectKey = "file.txt"
+						pfs.Uploaded = true
+					})).To(Succeed())
+				}
+			}
+			for _, pf := range uploader.Metadata.ProductFiles {
+				Expect(state.UpdateProductFile("/"+pf.UploadAs, func(pfs *ProductFileState) {
+					pfs.AwsObjectKey = "file.txt"
+					pfs.SHA256 = "sha256-in-state"
+					pfs.MD5 = "md5-in-state"
+					pfs.Uploaded = true
+				})).To(Succeed())
+			}
+			uploader.State = state
+			fakeClient.CreateFileGroupReturns(pivnet.FileGroup{ID: 20}, nil)
+			fakeClient.CreateProductFileReturns(pivnet.ProductFile{ID: 30}, nil)
+
+			err := uploader.Run(ctx)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeClient.CreateProductFileCallCount()).To(Equal(4))
+
+			_, cpfc := fakeClient.CreateProductFileArgsForCall(0)
+			Expect(cpfc.SHA256).To(Equal(helloSHA256))
+			Expect(cpfc.MD5).To(Equal("5d41402abc4b2a76b9719d911017c592"))
+
+			_, cpfc = fakeClient.CreateProductFileArgsForCall(3)
+			Expect(cpfc.SHA256).To(Equal("sha256-in-state"))
+			Expect(cpfc.MD5).To(Equal("md5-in-state"))
+		})
+	})
 })
@@ -24,72 +24,95 @@ func (v AbstractValidator) GetErrorMessages() []string {
 
 type MetaDataValidator struct {
 	AbstractValidator
-	metadata config.Metadata
+	metadata    config.Metadata
+	fieldErrors config.FieldErrors
 }
 
 func NewMetaDataValidator(metadata config.Metadata) *MetaDataValidator {
 	return &MetaDataValidator{metadata: metadata}
 }
 
+// FieldErrors returns the errors found by Validate with the paths and positions
+// of the fields in the metadata file
+func (mv *MetaDataValidator) FieldErrors() config.FieldErrors {
+	return mv.fieldErrors
+}
+
+func (mv *MetaDataValidator) fail(path string, message string) {
+	mv.fieldErrors = append(mv.fieldErrors, config.FieldError{
+		Path:     path,
+		Position: mv.metadata.Position(path),
+		Message:  message,
+	})
+}
+
 func (mv *MetaDataValidator) Validate() bool {
-	var messages []string
+	mv.fieldErrors = nil
 
 	r := mv.metadata.Release
 
 	vlog.Debug("validating date settings in metadata file...")
 	if !Empty(r.EndOfAvailabilityDate) && !Empty(r.EndOfAvailabilityDateOffset) {
-		messages = append(messages,
+		mv.fail("release.end_of_availability_date_offset",
 			"can not specify both end_of_availability_date and end_of_availability_date_offset")
 	}
 	if !Empty(r.EndOfSupportDate) && !IsDate(r.EndOfSupportDate) {
-		messages = append(messages,
+		mv.fail("release.end_of_support_date",
 			`end_of_support_date must be a valid date of the format "YYYY-MM-DD"`)
 	}
 
 	if !Empty(r.EndOfGuidanceDate) && !IsDate(r.EndOfGuidanceDate) {
-		messages = append(messages,
+		mv.fail("release.end_of_guidance_date",
 			`end_of_guidance_date must be a valid date of the format "YYYY-MM-DD"`)
 	}
 
 	if !Empty(r.EndOfAvailabilityDate) && !IsDate(r.EndOfAvailabilityDate) {
-		messages = append(messages,
+		mv.fail("release.end_of_availability_date",
 			`end_of_availability_date must be a valid date of the format "YYYY-MM-DD"`)
 	}
 
 	vlog.Debug("validating offset settings in metadata file...")
 	if !Empty(r.EndOfAvailabilityDateOffset) &&
 		!NewOffsetValidator(r.EndOfAvailabilityDateOffset).Validate() {
-		messages = append(messages,
+		mv.fail("release.end_of_availability_date_offset",
 			`end_of_availability_date_offset must be a valid offset of the form "(+\d+[mdyMDY])+"`)
 	}
 
 	vlog.Debug("validating file groups in metadata file...")
 	fileGroups := mv.metadata.FileGroups
-	for _, fileGroup := range fileGroups {
+	for i, fileGroup := range fileGroups {
 		vlog.Debug("\tvalidating file group: %s", fileGroup.Name)
-		for _, productFile := range fileGroup.ProductFiles {
+		for j, productFile := range fileGroup.ProductFiles {
 			vlog.Debug("\t\tvalidating product file:%s", productFile.Name)
-			pfValidator := NewProductFileValidator(productFile)
-			if !pfValidator.Validate() {
-				messages = append(messages, pfValidator.GetErrorMessages()...)
-			}
+			mv.validateProductFile(fmt.Sprintf("file_groups[%d].product_files[%d]", i, j), productFile)
 		}
 	}
 
 	vlog.Debug("validating product files in metadata file...")
 	productFiles := mv.metadata.ProductFiles
-	for _, productFile := range productFiles {
+	for i, productFile := range productFiles {
 		vlog.Debug("\tvalidating product file:%s", productFile.Name)
-		pfValidator := NewProductFileValidator(productFile)
-		if !pfValidator.Validate() {
-			messages = append(messages, pfValidator.GetErrorMessages()...)
-		}
+		mv.validateProductFile(fmt.Sprintf("product_files[%d]", i), productFile)
 	}
 
-	mv.errorMessages = messages
+	mv.errorMessages = nil
+	for _, err := range mv.fieldErrors {
+		mv.errorMessages = append(mv.errorMessages, err.Error())
+	}
 	return len(mv.errorMessages) == 0
 }
 
+// validateProductFile checks the required settings of the product file at the path
+func (mv *MetaDataValidator) validateProductFile(path string, productFile config.ProductFile) {
+	pv := NewProductFileValidator(productFile)
+	if pv.Validate() {
+		return
+	}
+	for _, key := range pv.MissingSettings() {
+		mv.fail(path+"."+key, key+" is required")
+	}
+}
+
 type OffsetValidator struct {
 	AbstractValidator
 	value string
@@ -102,7 +125,7 @@ func NewOffsetValidator(value string) *OffsetValidator {
 func (ov *OffsetValidator) Validate() bool {
 	var messages []string
 
-	offsetRegexp, _ := regexp.Compile(`(\+\d+[mdyMDY])+`)
+	offsetRegexp, _ := regexp.Compile(config.OffsetPattern)
 	if !offsetRegexp.Match([]byte(ov.value)) {
 		messages = append(messages,
 			fmt.Sprintf(`"%s" is not a valid offset value`, ov.value))
@@ -156,11 +179,29 @@ func NewProductFileValidator(productFile config.ProductFile) ProductFileValidato
 	return ProductFileValidator{pf: productFile}
 }
 
+type requiredSetting struct {
+	key   string
+	value string
+}
+
+// requiredSettings are the keys and values of the settings which the product file must have
+func (pv *ProductFileValidator) requiredSettings() []requiredSetting {
+	return []requiredSetting{
+		{key: "file", value: pv.pf.File},
+		{key: "upload_as", value: pv.pf.UploadAs},
+		{key: "file_type", value: pv.pf.FileType},
+		{key: "file_version", value: pv.pf.FileVersion},
+	}
+}
+
 func (pv *ProductFileValidator) Validate() bool {
 	var messages []string
 
-	productFile := pv.pf
-	requiredValidator := NewRequiredValidator(productFile.File, productFile.UploadAs, productFile.FileType, productFile.FileVersion)
+	var values []interface{}
+	for _, setting := range pv.requiredSettings() {
+		values = append(values, setting.value)
+	}
+	requiredValidator := NewRequiredValidator(values...)
 
 	if !requiredValidator.Validate() {
 		messages = append(messages,
@@ -172,3 +213,14 @@ func (pv *ProductFileValidator) Validate() bool {
 	pv.errorMessages = messages
 	return len(pv.errorMessages) == 0
 }
+
+// MissingSettings returns the keys of the required settings which are empty
+func (pv *ProductFileValidator) MissingSettings() []string {
+	var keys []string
+	for _, setting := range pv.requiredSettings() {
+		if !NewRequiredValidator(setting.value).Validate() {
+			keys = append(keys, setting.key)
+		}
+	}
+	return keys
+}
@@ -85,7 +85,8 @@ var _ = Describe("Validator", func() {
 				mv := NewMetaDataValidator(metadata)
 				Expect(mv.Validate()).To(BeFalse())
 				Expect(mv.GetErrorMessages()).To(Equal([]string{
-					"can not specify both end_of_availability_date and end_of_availability_date_offset",
+					"release.end_of_availability_date_offset (line 4, column 3): " +
+						"can not specify both end_of_availability_date and end_of_availability_date_offset",
 				}))
 			})
 
@@ -96,9 +97,9 @@ var _ = Describe("Validator", func() {
 			mv := NewMetaDataValidator(metadata)
 			Expect(mv.Validate()).To(BeFalse())
 			Expect(mv.GetErrorMessages()).To(Equal([]string{
-				"end_of_support_date must be a valid date of the format \"YYYY-MM-DD\"",
-				"end_of_guidance_date must be a valid date of the format \"YYYY-MM-DD\"",
-				"end_of_availability_date must be a valid date of the format \"YYYY-MM-DD\"",
+				"release.end_of_support_date (line 14, column 24): end_of_support_date must be a valid date of the format \"YYYY-MM-DD\"",
+				"release.end_of_guidance_date (line 15, column 25): end_of_guidance_date must be a valid date of the format \"YYYY-MM-DD\"",
+				"release.end_of_availability_date (line 16, column 29): end_of_availability_date must be a valid date of the format \"YYYY-MM-DD\"",
 			}))
 		})
 
@@ -109,7 +110,8 @@ var _ = Describe("Validator", func() {
 			mv := NewMetaDataValidator(metadata)
 			Expect(mv.Validate()).To(BeFalse())
 			Expect(mv.GetErrorMessages()).To(Equal([]string{
-				"end_of_availability_date_offset must be a valid offset of the form \"(+\\d+[mdyMDY])+\"",
+				"release.end_of_availability_date_offset (line 4, column 3): " +
+					"end_of_availability_date_offset must be a valid offset of the form \"(+\\d+[mdyMDY])+\"",
 			}))
 		})
 
@@ -118,8 +120,7 @@ var _ = Describe("Validator", func() {
 			mv := NewMetaDataValidator(metadata)
 			Expect(mv.Validate()).To(BeFalse())
 			Expect(mv.GetErrorMessages()).To(Equal([]string{
-				"One of settings is empty.[file, upload_as, file_type, file_version]",
-				"value is empty, index=0 (|  | Greenplum Database ${VERSION_REGEX} Installer for RHEL 6 | Software | ${VERSION_REGEX} |)",
+				"file_groups[0].product_files[0].file (line 22, column 11): file is required",
 			}))
 		})
 
@@ -128,8 +129,37 @@ var _ = Describe("Validator", func() {
 			mv := NewMetaDataValidator(metadata)
 			Expect(mv.Validate()).To(BeFalse())
 			Expect(mv.GetErrorMessages()).To(Equal([]string{
-				"One of settings is empty.[file, upload_as, file_type, file_version]",
-				"value is empty, index=0 (|  | Open Source Licenses for GPDB 6.x | Open Source License | ${VERSION_REGEX} |)",
+				"product_files[0].file (line 41, column 9): file is required",
+			}))
+		})
+
+		It("MetaDataValidator: field errors of product file", func() {
+			metadata.FileGroups[0].ProductFiles[1].FileType = ""
+			metadata.FileGroups[0].ProductFiles[1].FileVersion = ""
+			mv := NewMetaDataValidator(metadata)
+			Expect(mv.Validate()).To(BeFalse())
+			Expect(mv.FieldErrors()).To(Equal(config.FieldErrors{
+				{
+					Path:     "file_groups[0].product_files[1].file_type",
+					Position: config.Position{Line: 34, Column: 16},
+					Message:  "file_type is required",
+				},
+				{
+					Path:     "file_groups[0].product_files[1].file_version",
+					Position: config.Position{Line: 39, Column: 19},
+					Message:  "file_version is required",
+				},
+			}))
+		})
+
+		It("MetaDataValidator: metadata which is not from file", func() {
+			mv := NewMetaDataValidator(config.Metadata{ProductFiles: []config.ProductFile{{}}})
+			Expect(mv.Validate()).To(BeFalse())
+			Expect(mv.GetErrorMessages()).To(Equal([]string{
+				"product_files[0].file: file is required",
+				"product_files[0].upload_as: upload_as is required",
+				"product_files[0].file_type: file_type is required",
+				"product_files[0].file_version: file_version is required",
 			}))
 		})
 	})
@@ -252,6 +282,7 @@ var _ = Describe("Validator", func() {
 				"value is empty, index=1 (| file://path/to/file |  |  | 3.4.2 |)",
 				"value is empty, index=2 (| file://path/to/file |  |  | 3.4.2 |)",
 			}))
+			Expect(pv.MissingSettings()).To(Equal([]string{"upload_as", "file_type"}))
 		})
 	})
 })
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// runParallel runs the jobs with at most parallel workers. Once a job failed or ctx
+// is done, the jobs which are not started yet are skipped, and the errors of all started
+// jobs are returned together. The jobs run one by one when parallel is less than 2.
+func runParallel(ctx context.Context, parallel int, jobs []func() error) error {
+	if parallel < 1 {
+		parallel = 1
+	}
+	if parallel > len(jobs) {
+		parallel = len(jobs)
+	}
+
+	var (
+		mu      sync.Mutex
+		errs    []error
+		skipped bool
+		wg      sync.WaitGroup
+		jobsCh  = make(chan func() error)
+	)
+
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(errs) > 0 {
+			return true
+		}
+		if ctx.Err() != nil {
+			skipped = true
+			return true
+		}
+		return false
+	}
+
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobsCh {
+				if failed() {
+					continue
+				}
+				if err := job(); err != nil {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, job := range jobs {
+		if failed() {
+			break
+		}
+		jobsCh <- job
+	}
+	close(jobsCh)
+	wg.Wait()
+
+	switch len(errs) {
+	case 0:
+		if skipped {
+			return ctx.Err()
+		}
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return parallelError{errs: errs}
+	}
+}
+
+// parallelError is the errors of the jobs which failed together, it unwraps to
+// the first error, so that the kind of the failure can still be told.
+type parallelError struct {
+	errs []error
+}
+
+func (e parallelError) Error() string {
+	messages := make([]string, 0, len(e.errs))
+	for _, err := range e.errs {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d product files failed:\n%s", len(e.errs), strings.Join(messages, "\n"))
+}
+
+func (e parallelError) Unwrap() error {
+	return e.errs[0]
+}
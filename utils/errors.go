@@ -0,0 +1,126 @@
+package utils
+
+import "fmt"
+
+// ValidationError is returned when the metadata, the options or the version
+// given by the user are not valid
+type ValidationError struct {
+	Message string
+	Err     error
+}
+
+func NewValidationError(format string, args ...interface{}) ValidationError {
+	return ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e ValidationError) Error() string {
+	return errorMessage(e.Message, e.Err)
+}
+
+func (e ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// NotFoundError is returned when a release, a file or any other resource can
+// not be found in pivnet or on the local disk
+type NotFoundError struct {
+	Message string
+	Err     error
+}
+
+func NewNotFoundError(format string, args ...interface{}) NotFoundError {
+	return NotFoundError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e NotFoundError) Error() string {
+	return errorMessage(e.Message, e.Err)
+}
+
+func (e NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// ConflictError is returned when the request conflicts with the current state,
+// e.g. the release already exists or the checksums of a file do not match
+type ConflictError struct {
+	Message string
+	Err     error
+}
+
+func NewConflictError(format string, args ...interface{}) ConflictError {
+	return ConflictError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e ConflictError) Error() string {
+	return errorMessage(e.Message, e.Err)
+}
+
+func (e ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// TimeoutError is returned when pivnet does not complete an operation in time,
+// e.g. the file transfer of a product file is still in progress
+type TimeoutError struct {
+	Message string
+	Err     error
+}
+
+func NewTimeoutError(format string, args ...interface{}) TimeoutError {
+	return TimeoutError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e TimeoutError) Error() string {
+	return errorMessage(e.Message, e.Err)
+}
+
+func (e TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// RemoteApiError is returned when a call to the pivnet api failed
+type RemoteApiError struct {
+	Message string
+	Err     error
+}
+
+func NewRemoteApiError(err error, format string, args ...interface{}) RemoteApiError {
+	return RemoteApiError{Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+func (e RemoteApiError) Error() string {
+	return errorMessage(e.Message, e.Err)
+}
+
+func (e RemoteApiError) Unwrap() error {
+	return e.Err
+}
+
+// StorageError is returned when a file can not be read from the local disk or
+// can not be written to the object store
+type StorageError struct {
+	Message string
+	Err     error
+}
+
+func NewStorageError(err error, format string, args ...interface{}) StorageError {
+	return StorageError{Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+func (e StorageError) Error() string {
+	return errorMessage(e.Message, e.Err)
+}
+
+func (e StorageError) Unwrap() error {
+	return e.Err
+}
+
+func errorMessage(message string, err error) string {
+	if err == nil {
+		return message
+	}
+	if message == "" {
+		return err.Error()
+	}
+	return message + ": " + err.Error()
+}
@@ -0,0 +1,33 @@
+package utils_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/baotingfang/go-pivnet-client/utils"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Errors", func() {
+	It("Test error message", func() {
+		cause := errors.New("permission denied")
+
+		Expect(NewValidationError("invalid version: %s", "abc").Error()).To(Equal("invalid version: abc"))
+		Expect(NewStorageError(cause, "can not write %s", "file.txt").Error()).
+			To(Equal("can not write file.txt: permission denied"))
+		Expect(ValidationError{Err: cause}.Error()).To(Equal("permission denied"))
+	})
+
+	It("Test unwrap the cause", func() {
+		cause := errors.New("permission denied")
+		err := fmt.Errorf("upload failed: %w", NewStorageError(cause, "can not write %s", "file.txt"))
+
+		var storageErr StorageError
+		Expect(errors.As(err, &storageErr)).To(BeTrue())
+		Expect(errors.Is(err, cause)).To(BeTrue())
+
+		var notFoundErr NotFoundError
+		Expect(errors.As(err, &notFoundErr)).To(BeFalse())
+	})
+})
@@ -1,11 +1,16 @@
 package utils
 
 import (
-	"fmt"
-	"github.com/baotingfang/go-pivnet-client/vlog"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
 	"github.com/pivotal-cf/go-pivnet/v4"
+	"hash"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -50,18 +55,70 @@ func GetAllFiles(dirPath string) []string {
 	return files
 }
 
+type Checksums struct {
+	SHA256 string
+	MD5    string
+}
+
+// ChecksumReader computes the sha256 and md5 of everything read through it,
+// so that a file only need to be read once when it is uploaded.
+type ChecksumReader struct {
+	reader io.Reader
+	sha256 hash.Hash
+	md5    hash.Hash
+}
+
+func NewChecksumReader(r io.Reader) *ChecksumReader {
+	c := &ChecksumReader{
+		sha256: sha256.New(),
+		md5:    md5.New(),
+	}
+	c.reader = io.TeeReader(r, io.MultiWriter(c.sha256, c.md5))
+	return c
+}
+
+func (c *ChecksumReader) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
+
+// Checksums returns the checksums of the data which has been read
+func (c *ChecksumReader) Checksums() Checksums {
+	return Checksums{
+		SHA256: hex.EncodeToString(c.sha256.Sum(nil)),
+		MD5:    hex.EncodeToString(c.md5.Sum(nil)),
+	}
+}
+
+func FileChecksums(path string) (Checksums, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Checksums{}, err
+	}
+	defer f.Close()
+
+	r := NewChecksumReader(f)
+	if _, err := io.Copy(ioutil.Discard, r); err != nil {
+		return Checksums{}, err
+	}
+	return r.Checksums(), nil
+}
+
+// Empty reports whether the value is blank, the strings which only contain
+// whitespaces are blank, and the values of other types are blank when they are
+// the zero value of their type.
 func Empty(s interface{}) bool {
 	switch v := s.(type) {
 	case string:
-		return len(strings.TrimSpace(s.(string))) == 0
+		return len(strings.TrimSpace(v)) == 0
 	case pivnet.Release:
-		return s.(pivnet.Release) == pivnet.Release{}
+		return v == pivnet.Release{}
 	case pivnet.ReleaseType:
-		return strings.TrimSpace(string(s.(pivnet.ReleaseType))) == ""
+		return strings.TrimSpace(string(v)) == ""
+	case nil:
+		return true
 	default:
-		vlog.Fatal("Empty() doesn't support this type: %T", v)
+		return reflect.ValueOf(v).IsZero()
 	}
-	return false
 }
 
 type Date struct {
@@ -81,10 +138,11 @@ func (d Date) Offset(offsetExpression string) Date {
 	if Empty(offsetExpression) {
 		return d
 	}
-	dayOffset := 0
-	monthOffset := 0
-	yearOffset := 0
+	yearOffset, monthOffset, dayOffset := parseOffset(offsetExpression)
+	return Date{d.Time.AddDate(yearOffset, monthOffset, dayOffset)}
+}
 
+func parseOffset(offsetExpression string) (yearOffset, monthOffset, dayOffset int) {
 	matcher := func(patter string) int {
 		dayOffsetMatcher := regexp.MustCompile(patter)
 		r := dayOffsetMatcher.FindStringSubmatch(strings.ToLower(offsetExpression))
@@ -98,28 +156,17 @@ func (d Date) Offset(offsetExpression string) Date {
 	dayOffset = matcher(`\+(\d*)d`)
 	monthOffset = matcher(`\+(\d*)m`)
 	yearOffset = matcher(`\+(\d*)y`)
-
-	return Date{d.Time.AddDate(yearOffset, monthOffset, dayOffset)}
-}
-
-func MustParseDateFrom(value string) Date {
-	t, err := time.Parse("2006-01-02", value)
-	if err != nil {
-		errMessage := fmt.Sprintf("can not parse %s to Date. err: %s", value, err.Error())
-		vlog.Error(errMessage)
-		vlog.Fatal(errMessage)
-	}
-	return Date{t}
+	return
 }
 
 func ParseDateFrom(value string) (Date, error) {
 	if Empty(value) {
-		return Date{}, fmt.Errorf("can not parse empty string")
+		return Date{}, NewValidationError("can not parse empty string")
 	}
 
 	t, err := time.Parse("2006-01-02", value)
 	if err != nil {
-		return Date{}, fmt.Errorf("parse Date failed: %s, err: %s", value, err.Error())
+		return Date{}, NewValidationError("parse Date failed: %s, err: %s", value, err.Error())
 	}
 
 	return Date{t}, nil
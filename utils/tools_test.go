@@ -1,6 +1,10 @@
 package utils_test
 
 import (
+	"io/ioutil"
+	"os"
+	"strings"
+
 	. "github.com/baotingfang/go-pivnet-client/utils"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -69,6 +73,13 @@ var _ = Describe("Tools", func() {
 			Version: "4.6.0",
 		}
 		Expect(Empty(r)).To(BeFalse())
+
+		By("Test other types")
+		Expect(Empty(nil)).To(BeTrue())
+		Expect(Empty(0)).To(BeTrue())
+		Expect(Empty(12)).To(BeFalse())
+		Expect(Empty([]string(nil))).To(BeTrue())
+		Expect(Empty(Date{})).To(BeTrue())
 	})
 
 	It("Test ParseDateFrom", func() {
@@ -90,8 +101,35 @@ var _ = Describe("Tools", func() {
 		Expect(d.IsZero()).To(BeTrue())
 	})
 
-	It("Test MustParseDateFrom", func() {
-		d := MustParseDateFrom("2013-05-19")
-		Expect(d.String()).To(Equal("2013-05-19"))
+	It("Test ParseDateFrom returns validation error", func() {
+		_, err := ParseDateFrom("2013/05/19")
+		Expect(err).To(BeAssignableToTypeOf(ValidationError{}))
+	})
+
+	It("Test FileChecksums", func() {
+		f, err := ioutil.TempFile("", "checksums")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(f.Name())
+		_, _ = f.WriteString("hello")
+		_ = f.Close()
+
+		sums, err := FileChecksums(f.Name())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sums.SHA256).To(Equal("2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"))
+		Expect(sums.MD5).To(Equal("5d41402abc4b2a76b9719d911017c592"))
+
+		_, err = FileChecksums(f.Name() + ".missing")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Test ChecksumReader", func() {
+		r := NewChecksumReader(strings.NewReader("hello"))
+		content, err := ioutil.ReadAll(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("hello"))
+		Expect(r.Checksums()).To(Equal(Checksums{
+			SHA256: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			MD5:    "5d41402abc4b2a76b9719d911017c592",
+		}))
 	})
 })
@@ -0,0 +1,283 @@
+package wrapper
+
+import (
+	"context"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the latency histogram,
+// the latencies greater than the last one are counted in the +Inf bucket.
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+}
+
+const InfBucket = "+Inf"
+
+type LatencyBucket struct {
+	UpperBound string `json:"le"`
+	Count      int    `json:"count"`
+}
+
+// MethodMetrics are the calls of a method of PivnetClient
+type MethodMetrics struct {
+	Method       string          `json:"method"`
+	Calls        int             `json:"calls"`
+	Errors       int             `json:"errors"`
+	TotalLatency time.Duration   `json:"-"`
+	MaxLatency   time.Duration   `json:"-"`
+	TotalSeconds float64         `json:"total_seconds"`
+	MaxSeconds   float64         `json:"max_seconds"`
+	Histogram    []LatencyBucket `json:"histogram"`
+}
+
+func (m MethodMetrics) MeanLatency() time.Duration {
+	if m.Calls == 0 {
+		return 0
+	}
+	return m.TotalLatency / time.Duration(m.Calls)
+}
+
+type methodMetrics struct {
+	calls        int
+	errors       int
+	totalLatency time.Duration
+	maxLatency   time.Duration
+	buckets      []int
+}
+
+// Metrics records the calls, errors and latencies of every method of PivnetClient
+type Metrics struct {
+	mu      sync.Mutex
+	methods map[string]*methodMetrics
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		methods: make(map[string]*methodMetrics),
+	}
+}
+
+func (m *Metrics) Record(method string, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	mm, ok := m.methods[method]
+	if !ok {
+		mm = &methodMetrics{buckets: make([]int, len(LatencyBuckets)+1)}
+		m.methods[method] = mm
+	}
+
+	mm.calls++
+	if err != nil {
+		mm.errors++
+	}
+	mm.totalLatency += latency
+	if latency > mm.maxLatency {
+		mm.maxLatency = latency
+	}
+	mm.buckets[sort.Search(len(LatencyBuckets), func(i int) bool {
+		return latency <= LatencyBuckets[i]
+	})]++
+}
+
+// Snapshot returns the metrics of the called methods, sorted by the method name
+func (m *Metrics) Snapshot() []MethodMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make([]MethodMetrics, 0, len(m.methods))
+	for method, mm := range m.methods {
+		histogram := make([]LatencyBucket, 0, len(mm.buckets))
+		for i, count := range mm.buckets {
+			upperBound := InfBucket
+			if i < len(LatencyBuckets) {
+				upperBound = LatencyBuckets[i].String()
+			}
+			histogram = append(histogram, LatencyBucket{UpperBound: upperBound, Count: count})
+		}
+
+		snapshot = append(snapshot, MethodMetrics{
+			Method:       method,
+			Calls:        mm.calls,
+			Errors:       mm.errors,
+			TotalLatency: mm.totalLatency,
+			MaxLatency:   mm.maxLatency,
+			TotalSeconds: mm.totalLatency.Seconds(),
+			MaxSeconds:   mm.maxLatency.Seconds(),
+			Histogram:    histogram,
+		})
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].Method < snapshot[j].Method
+	})
+	return snapshot
+}
+
+// InstrumentedClient records the metrics of every pivnet call
+type InstrumentedClient struct {
+	Client  PivnetClient
+	Metrics *Metrics
+}
+
+func NewInstrumentedClient(client PivnetClient, metrics *Metrics) PivnetClient {
+	return &InstrumentedClient{
+		Client:  client,
+		Metrics: metrics,
+	}
+}
+
+func (c InstrumentedClient) record(method string, start time.Time, err error) {
+	c.Metrics.Record(method, time.Since(start), err)
+}
+
+func (c InstrumentedClient) GetAllReleases(ctx context.Context, productSlug string) ([]pivnet.Release, error) {
+	start := time.Now()
+	result, err := c.Client.GetAllReleases(ctx, productSlug)
+	c.record("GetAllReleases", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) CreateRelease(ctx context.Context, releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error) {
+	start := time.Now()
+	result, err := c.Client.CreateRelease(ctx, releaseConfig)
+	c.record("CreateRelease", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) DeleteRelease(ctx context.Context, productSlug string, release pivnet.Release) error {
+	start := time.Now()
+	err := c.Client.DeleteRelease(ctx, productSlug, release)
+	c.record("DeleteRelease", start, err)
+	return err
+}
+
+func (c InstrumentedClient) GetAllFileGroups(ctx context.Context, productSlug string) ([]pivnet.FileGroup, error) {
+	start := time.Now()
+	result, err := c.Client.GetAllFileGroups(ctx, productSlug)
+	c.record("GetAllFileGroups", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) GetFileGroupsForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.FileGroup, error) {
+	start := time.Now()
+	result, err := c.Client.GetFileGroupsForRelease(ctx, productSlug, releaseId)
+	c.record("GetFileGroupsForRelease", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) CreateFileGroup(ctx context.Context, productSlug, groupName string) (pivnet.FileGroup, error) {
+	start := time.Now()
+	result, err := c.Client.CreateFileGroup(ctx, productSlug, groupName)
+	c.record("CreateFileGroup", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) DeleteFileGroup(ctx context.Context, productSlug string, fileGroupId int) (pivnet.FileGroup, error) {
+	start := time.Now()
+	result, err := c.Client.DeleteFileGroup(ctx, productSlug, fileGroupId)
+	c.record("DeleteFileGroup", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) CreateFederationToken(ctx context.Context, productSlug string) (pivnet.FederationToken, error) {
+	start := time.Now()
+	result, err := c.Client.CreateFederationToken(ctx, productSlug)
+	c.record("CreateFederationToken", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) GetAllProductFiles(ctx context.Context, productSlug string) ([]pivnet.ProductFile, error) {
+	start := time.Now()
+	result, err := c.Client.GetAllProductFiles(ctx, productSlug)
+	c.record("GetAllProductFiles", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) GetProductFile(ctx context.Context, productSlug string, productFileId int) (pivnet.ProductFile, error) {
+	start := time.Now()
+	result, err := c.Client.GetProductFile(ctx, productSlug, productFileId)
+	c.record("GetProductFile", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) GetProductFilesForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.ProductFile, error) {
+	start := time.Now()
+	result, err := c.Client.GetProductFilesForRelease(ctx, productSlug, releaseId)
+	c.record("GetProductFilesForRelease", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) CreateProductFile(ctx context.Context, productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+	start := time.Now()
+	result, err := c.Client.CreateProductFile(ctx, productFileConfig)
+	c.record("CreateProductFile", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) DeleteProductFile(ctx context.Context, productSlug string, productFileId int) (pivnet.ProductFile, error) {
+	start := time.Now()
+	result, err := c.Client.DeleteProductFile(ctx, productSlug, productFileId)
+	c.record("DeleteProductFile", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) AddProductFileToFileGroup(ctx context.Context, productSlug string, productFileId, fileGroupId int) error {
+	start := time.Now()
+	err := c.Client.AddProductFileToFileGroup(ctx, productSlug, productFileId, fileGroupId)
+	c.record("AddProductFileToFileGroup", start, err)
+	return err
+}
+
+func (c InstrumentedClient) AddProductFileToRelease(ctx context.Context, productSlug string, productFileId, releaseId int) error {
+	start := time.Now()
+	err := c.Client.AddProductFileToRelease(ctx, productSlug, productFileId, releaseId)
+	c.record("AddProductFileToRelease", start, err)
+	return err
+}
+
+func (c InstrumentedClient) AddFileGroupToRelease(ctx context.Context, productSlug string, fileGroupId, releaseId int) error {
+	start := time.Now()
+	err := c.Client.AddFileGroupToRelease(ctx, productSlug, fileGroupId, releaseId)
+	c.record("AddFileGroupToRelease", start, err)
+	return err
+}
+
+func (c InstrumentedClient) UpdateRelease(ctx context.Context, productSlug string, release pivnet.Release) (pivnet.Release, error) {
+	start := time.Now()
+	result, err := c.Client.UpdateRelease(ctx, productSlug, release)
+	c.record("UpdateRelease", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) GetAllUserGroups(ctx context.Context) ([]pivnet.UserGroup, error) {
+	start := time.Now()
+	result, err := c.Client.GetAllUserGroups(ctx)
+	c.record("GetAllUserGroups", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) GetUserGroupsForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.UserGroup, error) {
+	start := time.Now()
+	result, err := c.Client.GetUserGroupsForRelease(ctx, productSlug, releaseId)
+	c.record("GetUserGroupsForRelease", start, err)
+	return result, err
+}
+
+func (c InstrumentedClient) AddUserGroupToRelease(ctx context.Context, productSlug string, userGroupId, releaseId int) error {
+	start := time.Now()
+	err := c.Client.AddUserGroupToRelease(ctx, productSlug, userGroupId, releaseId)
+	c.record("AddUserGroupToRelease", start, err)
+	return err
+}
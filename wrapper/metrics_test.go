@@ -0,0 +1,64 @@
+package wrapper_test
+
+import (
+	"errors"
+	"github.com/baotingfang/go-pivnet-client/wrapper/wrapperfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"time"
+
+	. "github.com/baotingfang/go-pivnet-client/wrapper"
+)
+
+var _ = Describe("Metrics", func() {
+	It("records the calls in the latency histogram", func() {
+		metrics := NewMetrics()
+		metrics.Record("GetProductFile", 10*time.Millisecond, nil)
+		metrics.Record("GetProductFile", 300*time.Millisecond, errors.New("server error"))
+		metrics.Record("GetProductFile", time.Minute, nil)
+		metrics.Record("CreateRelease", time.Second, nil)
+
+		snapshot := metrics.Snapshot()
+		Expect(snapshot).To(HaveLen(2))
+		Expect(snapshot[0].Method).To(Equal("CreateRelease"))
+
+		m := snapshot[1]
+		Expect(m.Method).To(Equal("GetProductFile"))
+		Expect(m.Calls).To(Equal(3))
+		Expect(m.Errors).To(Equal(1))
+		Expect(m.MaxLatency).To(Equal(time.Minute))
+		Expect(m.MaxSeconds).To(Equal(60.0))
+		Expect(m.MeanLatency()).To(Equal((time.Minute + 310*time.Millisecond) / 3))
+		Expect(m.Histogram[0]).To(Equal(LatencyBucket{UpperBound: "50ms", Count: 1}))
+		Expect(m.Histogram[3]).To(Equal(LatencyBucket{UpperBound: "500ms", Count: 1}))
+		Expect(m.Histogram[len(LatencyBuckets)]).To(Equal(LatencyBucket{UpperBound: InfBucket, Count: 1}))
+	})
+})
+
+var _ = Describe("InstrumentedClient", func() {
+	It("records every call of the client", func() {
+		fakeClient := &wrapperfakes.FakePivnetClient{}
+		fakeClient.GetProductFileReturnsOnCall(0, pivnet.ProductFile{ID: 1}, nil)
+		fakeClient.GetProductFileReturnsOnCall(1, pivnet.ProductFile{}, errors.New("server error"))
+		fakeClient.AddProductFileToReleaseReturns(nil)
+
+		metrics := NewMetrics()
+		client := NewInstrumentedClient(fakeClient, metrics)
+
+		pf, err := client.GetProductFile(ctx, "fakeslug", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pf.ID).To(Equal(1))
+		_, err = client.GetProductFile(ctx, "fakeslug", 2)
+		Expect(err).To(MatchError("server error"))
+		Expect(client.AddProductFileToRelease(ctx, "fakeslug", 1, 10)).To(Succeed())
+
+		snapshot := metrics.Snapshot()
+		Expect(snapshot).To(HaveLen(2))
+		Expect(snapshot[0].Method).To(Equal("AddProductFileToRelease"))
+		Expect(snapshot[0].Calls).To(Equal(1))
+		Expect(snapshot[1].Method).To(Equal("GetProductFile"))
+		Expect(snapshot[1].Calls).To(Equal(2))
+		Expect(snapshot[1].Errors).To(Equal(1))
+	})
+})
@@ -1,8 +1,10 @@
 package wrapper
 
 import (
+	"context"
 	"github.com/pivotal-cf/go-pivnet/v4"
 	"github.com/pivotal-cf/go-pivnet/v4/logger"
+	"net/http"
 )
 
 //go:generate counterfeiter . AccessTokenService
@@ -14,69 +16,296 @@ type AccessTokenService interface {
 //go:generate counterfeiter . PivnetClient
 
 type PivnetClient interface {
-	GetAllReleases(productSlug string) ([]pivnet.Release, error)
-	CreateRelease(releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error)
-	CreateFileGroup(productSlug, groupName string) (pivnet.FileGroup, error)
-	CreateFederationToken(productSlug string) (pivnet.FederationToken, error)
-	GetProductFile(productSlug string, productFileId int) (pivnet.ProductFile, error)
-	CreateProductFile(productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)
-	DeleteProductFile(productSlug string, productFileId int) (pivnet.ProductFile, error)
-	AddProductFileToFileGroup(productSlug string, productFileId, fileGroupId int) error
-	AddProductFileToRelease(productSlug string, productFileId, releaseId int) error
-	AddFileGroupToRelease(productSlug string, fileGroupId, releaseId int) error
-	UpdateRelease(productSlug string, release pivnet.Release) (pivnet.Release, error)
+	GetAllReleases(ctx context.Context, productSlug string) ([]pivnet.Release, error)
+	CreateRelease(ctx context.Context, releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error)
+	DeleteRelease(ctx context.Context, productSlug string, release pivnet.Release) error
+	GetAllFileGroups(ctx context.Context, productSlug string) ([]pivnet.FileGroup, error)
+	GetFileGroupsForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.FileGroup, error)
+	CreateFileGroup(ctx context.Context, productSlug, groupName string) (pivnet.FileGroup, error)
+	DeleteFileGroup(ctx context.Context, productSlug string, fileGroupId int) (pivnet.FileGroup, error)
+	CreateFederationToken(ctx context.Context, productSlug string) (pivnet.FederationToken, error)
+	GetAllProductFiles(ctx context.Context, productSlug string) ([]pivnet.ProductFile, error)
+	GetProductFile(ctx context.Context, productSlug string, productFileId int) (pivnet.ProductFile, error)
+	GetProductFilesForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.ProductFile, error)
+	CreateProductFile(ctx context.Context, productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)
+	DeleteProductFile(ctx context.Context, productSlug string, productFileId int) (pivnet.ProductFile, error)
+	AddProductFileToFileGroup(ctx context.Context, productSlug string, productFileId, fileGroupId int) error
+	AddProductFileToRelease(ctx context.Context, productSlug string, productFileId, releaseId int) error
+	AddFileGroupToRelease(ctx context.Context, productSlug string, fileGroupId, releaseId int) error
+	UpdateRelease(ctx context.Context, productSlug string, release pivnet.Release) (pivnet.Release, error)
+	GetAllUserGroups(ctx context.Context) ([]pivnet.UserGroup, error)
+	GetUserGroupsForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.UserGroup, error)
+	AddUserGroupToRelease(ctx context.Context, productSlug string, userGroupId, releaseId int) error
 }
 
 type Client struct {
-	client pivnet.Client
+	token     AccessTokenService
+	config    pivnet.ClientConfig
+	logger    logger.Logger
+	transport http.RoundTripper
 }
 
 func NewClient(token AccessTokenService, config pivnet.ClientConfig, logger logger.Logger) PivnetClient {
 	return &Client{
-		client: pivnet.NewClient(token, config, logger),
+		token:     token,
+		config:    config,
+		logger:    logger,
+		transport: pivnet.NewClient(token, config, logger).HTTP.Transport,
 	}
 }
 
-func (c Client) GetAllReleases(productSlug string) ([]pivnet.Release, error) {
-	return c.client.Releases.List(productSlug)
+// call runs the pivnet call which only reads objects, and returns without waiting
+// for it when ctx is done, because go-pivnet does not accept a context. The
+// abandoned call is not retried.
+func (c Client) call(ctx context.Context, f func(client pivnet.Client) error) error {
+	return c.run(ctx, false, f)
 }
 
-func (c Client) CreateRelease(releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error) {
-	return c.client.Releases.Create(releaseConfig)
+// mutate runs the pivnet call which changes objects on pivnet. Once it is sent, it
+// is waited for even when ctx is done, since pivnet may still apply the change,
+// and the caller has to record the created objects to roll back or resume them.
+// The wait is bounded by the timeout of the http client.
+func (c Client) mutate(ctx context.Context, f func(client pivnet.Client) error) error {
+	return c.run(ctx, true, f)
 }
 
-func (c Client) CreateFileGroup(productSlug, groupName string) (pivnet.FileGroup, error) {
-	return c.client.FileGroups.Create(pivnet.CreateFileGroupConfig{ProductSlug: productSlug, Name: groupName})
+// run runs the pivnet call in a goroutine. go-pivnet drops the status and headers of
+// the responses, so every call has its own go-pivnet client, whose transport records
+// the responses into the CallResponse in ctx, the connections are still shared by
+// the transport of the client.
+func (c Client) run(ctx context.Context, wait bool, f func(client pivnet.Client) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	client := pivnet.NewClient(c.token, c.config, c.logger)
+	client.HTTP.Transport = c.transport
+	if response := callResponseFrom(ctx); response != nil {
+		client.HTTP.Transport = responseTransport{base: c.transport, response: response}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- f(client)
+	}()
+
+	if wait {
+		return <-done
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (c Client) GetAllReleases(ctx context.Context, productSlug string) ([]pivnet.Release, error) {
+	var result []pivnet.Release
+	err := c.call(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.Releases.List(productSlug)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Client) CreateRelease(ctx context.Context, releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error) {
+	var result pivnet.Release
+	err := c.mutate(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.Releases.Create(releaseConfig)
+		return err
+	})
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+	return result, nil
+}
+
+func (c Client) DeleteRelease(ctx context.Context, productSlug string, release pivnet.Release) error {
+	return c.mutate(ctx, func(client pivnet.Client) error {
+		return client.Releases.Delete(productSlug, release)
+	})
+}
+
+func (c Client) GetAllFileGroups(ctx context.Context, productSlug string) ([]pivnet.FileGroup, error) {
+	var result []pivnet.FileGroup
+	err := c.call(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.FileGroups.List(productSlug)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Client) GetFileGroupsForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.FileGroup, error) {
+	var result []pivnet.FileGroup
+	err := c.call(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.FileGroups.ListForRelease(productSlug, releaseId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Client) CreateFileGroup(ctx context.Context, productSlug, groupName string) (pivnet.FileGroup, error) {
+	var result pivnet.FileGroup
+	err := c.mutate(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.FileGroups.Create(pivnet.CreateFileGroupConfig{ProductSlug: productSlug, Name: groupName})
+		return err
+	})
+	if err != nil {
+		return pivnet.FileGroup{}, err
+	}
+	return result, nil
+}
+
+func (c Client) DeleteFileGroup(ctx context.Context, productSlug string, fileGroupId int) (pivnet.FileGroup, error) {
+	var result pivnet.FileGroup
+	err := c.mutate(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.FileGroups.Delete(productSlug, fileGroupId)
+		return err
+	})
+	if err != nil {
+		return pivnet.FileGroup{}, err
+	}
+	return result, nil
+}
+
+func (c Client) CreateFederationToken(ctx context.Context, productSlug string) (pivnet.FederationToken, error) {
+	var result pivnet.FederationToken
+	err := c.call(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.FederationToken.GenerateFederationToken(productSlug)
+		return err
+	})
+	if err != nil {
+		return pivnet.FederationToken{}, err
+	}
+	return result, nil
 }
 
-func (c Client) CreateFederationToken(productSlug string) (pivnet.FederationToken, error) {
-	return c.client.FederationToken.GenerateFederationToken(productSlug)
+func (c Client) GetAllProductFiles(ctx context.Context, productSlug string) ([]pivnet.ProductFile, error) {
+	var result []pivnet.ProductFile
+	err := c.call(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.ProductFiles.List(productSlug)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (c Client) GetProductFile(productSlug string, productFileId int) (pivnet.ProductFile, error) {
-	return c.client.ProductFiles.Get(productSlug, productFileId)
+func (c Client) GetProductFile(ctx context.Context, productSlug string, productFileId int) (pivnet.ProductFile, error) {
+	var result pivnet.ProductFile
+	err := c.call(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.ProductFiles.Get(productSlug, productFileId)
+		return err
+	})
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+	return result, nil
 }
 
-func (c Client) CreateProductFile(productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
-	return c.client.ProductFiles.Create(productFileConfig)
+func (c Client) GetProductFilesForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.ProductFile, error) {
+	var result []pivnet.ProductFile
+	err := c.call(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.ProductFiles.ListForRelease(productSlug, releaseId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (c Client) DeleteProductFile(productSlug string, productFileId int) (pivnet.ProductFile, error) {
-	return c.client.ProductFiles.Delete(productSlug, productFileId)
+func (c Client) CreateProductFile(ctx context.Context, productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+	var result pivnet.ProductFile
+	err := c.mutate(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.ProductFiles.Create(productFileConfig)
+		return err
+	})
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+	return result, nil
 }
 
-func (c Client) AddProductFileToFileGroup(productSlug string, productFileId, fileGroupId int) error {
-	return c.client.ProductFiles.AddToFileGroup(productSlug, fileGroupId, productFileId)
+func (c Client) DeleteProductFile(ctx context.Context, productSlug string, productFileId int) (pivnet.ProductFile, error) {
+	var result pivnet.ProductFile
+	err := c.mutate(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.ProductFiles.Delete(productSlug, productFileId)
+		return err
+	})
+	if err != nil {
+		return pivnet.ProductFile{}, err
+	}
+	return result, nil
 }
 
-func (c Client) AddProductFileToRelease(productSlug string, productFileId, releaseId int) error {
-	return c.client.ProductFiles.AddToRelease(productSlug, releaseId, productFileId)
+func (c Client) AddProductFileToFileGroup(ctx context.Context, productSlug string, productFileId, fileGroupId int) error {
+	return c.mutate(ctx, func(client pivnet.Client) error {
+		return client.ProductFiles.AddToFileGroup(productSlug, fileGroupId, productFileId)
+	})
 }
 
-func (c Client) AddFileGroupToRelease(productSlug string, fileGroupId, releaseId int) error {
-	return c.client.FileGroups.AddToRelease(productSlug, releaseId, fileGroupId)
+func (c Client) AddProductFileToRelease(ctx context.Context, productSlug string, productFileId, releaseId int) error {
+	return c.mutate(ctx, func(client pivnet.Client) error {
+		return client.ProductFiles.AddToRelease(productSlug, releaseId, productFileId)
+	})
+}
+
+func (c Client) AddFileGroupToRelease(ctx context.Context, productSlug string, fileGroupId, releaseId int) error {
+	return c.mutate(ctx, func(client pivnet.Client) error {
+		return client.FileGroups.AddToRelease(productSlug, releaseId, fileGroupId)
+	})
+}
+
+func (c Client) UpdateRelease(ctx context.Context, productSlug string, release pivnet.Release) (pivnet.Release, error) {
+	var result pivnet.Release
+	err := c.mutate(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.Releases.Update(productSlug, release)
+		return err
+	})
+	if err != nil {
+		return pivnet.Release{}, err
+	}
+	return result, nil
+}
+
+func (c Client) GetAllUserGroups(ctx context.Context) ([]pivnet.UserGroup, error) {
+	var result []pivnet.UserGroup
+	err := c.call(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.UserGroups.List()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c Client) GetUserGroupsForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.UserGroup, error) {
+	var result []pivnet.UserGroup
+	err := c.call(ctx, func(client pivnet.Client) (err error) {
+		result, err = client.UserGroups.ListForRelease(productSlug, releaseId)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (c Client) UpdateRelease(productSlug string, release pivnet.Release) (pivnet.Release, error) {
-	return c.client.Releases.Update(productSlug, release)
+func (c Client) AddUserGroupToRelease(ctx context.Context, productSlug string, userGroupId, releaseId int) error {
+	return c.mutate(ctx, func(client pivnet.Client) error {
+		return client.UserGroups.AddToRelease(productSlug, releaseId, userGroupId)
+	})
 }
@@ -0,0 +1,200 @@
+package wrapper
+
+import (
+	"context"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"sync"
+	"time"
+)
+
+// DefaultRequestsPerSecond is low enough not to be throttled by pivnet,
+// even when the product files are uploaded in parallel.
+const DefaultRequestsPerSecond = 5
+
+// RateLimiter spaces the requests evenly, so that there are at most
+// the requests per second. It does not limit when the rate is not positive.
+type RateLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func NewRateLimiter(requestsPerSecond float64) *RateLimiter {
+	var interval time.Duration
+	if requestsPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / requestsPerSecond)
+	}
+	return &RateLimiter{interval: interval}
+}
+
+// Wait blocks until the next request is allowed, or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return ctx.Err()
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, wait)
+}
+
+// RateLimitClient waits for the rate limiter before every pivnet call
+type RateLimitClient struct {
+	Client  PivnetClient
+	Limiter *RateLimiter
+}
+
+func NewRateLimitClient(client PivnetClient, requestsPerSecond float64) PivnetClient {
+	return &RateLimitClient{
+		Client:  client,
+		Limiter: NewRateLimiter(requestsPerSecond),
+	}
+}
+
+func (r RateLimitClient) GetAllReleases(ctx context.Context, productSlug string) ([]pivnet.Release, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.Client.GetAllReleases(ctx, productSlug)
+}
+
+func (r RateLimitClient) CreateRelease(ctx context.Context, releaseConfig pivnet.CreateReleaseConfig) (pivnet.Release, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return pivnet.Release{}, err
+	}
+	return r.Client.CreateRelease(ctx, releaseConfig)
+}
+
+func (r RateLimitClient) DeleteRelease(ctx context.Context, productSlug string, release pivnet.Release) error {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return r.Client.DeleteRelease(ctx, productSlug, release)
+}
+
+func (r RateLimitClient) GetAllFileGroups(ctx context.Context, productSlug string) ([]pivnet.FileGroup, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.Client.GetAllFileGroups(ctx, productSlug)
+}
+
+func (r RateLimitClient) GetFileGroupsForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.FileGroup, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.Client.GetFileGroupsForRelease(ctx, productSlug, releaseId)
+}
+
+func (r RateLimitClient) CreateFileGroup(ctx context.Context, productSlug, groupName string) (pivnet.FileGroup, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return pivnet.FileGroup{}, err
+	}
+	return r.Client.CreateFileGroup(ctx, productSlug, groupName)
+}
+
+func (r RateLimitClient) DeleteFileGroup(ctx context.Context, productSlug string, fileGroupId int) (pivnet.FileGroup, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return pivnet.FileGroup{}, err
+	}
+	return r.Client.DeleteFileGroup(ctx, productSlug, fileGroupId)
+}
+
+func (r RateLimitClient) CreateFederationToken(ctx context.Context, productSlug string) (pivnet.FederationToken, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return pivnet.FederationToken{}, err
+	}
+	return r.Client.CreateFederationToken(ctx, productSlug)
+}
+
+func (r RateLimitClient) GetAllProductFiles(ctx context.Context, productSlug string) ([]pivnet.ProductFile, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.Client.GetAllProductFiles(ctx, productSlug)
+}
+
+func (r RateLimitClient) GetProductFile(ctx context.Context, productSlug string, productFileId int) (pivnet.ProductFile, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return pivnet.ProductFile{}, err
+	}
+	return r.Client.GetProductFile(ctx, productSlug, productFileId)
+}
+
+func (r RateLimitClient) GetProductFilesForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.ProductFile, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.Client.GetProductFilesForRelease(ctx, productSlug, releaseId)
+}
+
+func (r RateLimitClient) CreateProductFile(ctx context.Context, productFileConfig pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return pivnet.ProductFile{}, err
+	}
+	return r.Client.CreateProductFile(ctx, productFileConfig)
+}
+
+func (r RateLimitClient) DeleteProductFile(ctx context.Context, productSlug string, productFileId int) (pivnet.ProductFile, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return pivnet.ProductFile{}, err
+	}
+	return r.Client.DeleteProductFile(ctx, productSlug, productFileId)
+}
+
+func (r RateLimitClient) AddProductFileToFileGroup(ctx context.Context, productSlug string, productFileId, fileGroupId int) error {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return r.Client.AddProductFileToFileGroup(ctx, productSlug, productFileId, fileGroupId)
+}
+
+func (r RateLimitClient) AddProductFileToRelease(ctx context.Context, productSlug string, productFileId, releaseId int) error {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return r.Client.AddProductFileToRelease(ctx, productSlug, productFileId, releaseId)
+}
+
+func (r RateLimitClient) AddFileGroupToRelease(ctx context.Context, productSlug string, fileGroupId, releaseId int) error {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return r.Client.AddFileGroupToRelease(ctx, productSlug, fileGroupId, releaseId)
+}
+
+func (r RateLimitClient) UpdateRelease(ctx context.Context, productSlug string, release pivnet.Release) (pivnet.Release, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return pivnet.Release{}, err
+	}
+	return r.Client.UpdateRelease(ctx, productSlug, release)
+}
+
+func (r RateLimitClient) GetAllUserGroups(ctx context.Context) ([]pivnet.UserGroup, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.Client.GetAllUserGroups(ctx)
+}
+
+func (r RateLimitClient) GetUserGroupsForRelease(ctx context.Context, productSlug string, releaseId int) ([]pivnet.UserGroup, error) {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return r.Client.GetUserGroupsForRelease(ctx, productSlug, releaseId)
+}
+
+func (r RateLimitClient) AddUserGroupToRelease(ctx context.Context, productSlug string, userGroupId, releaseId int) error {
+	if err := r.Limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return r.Client.AddUserGroupToRelease(ctx, productSlug, userGroupId, releaseId)
+}
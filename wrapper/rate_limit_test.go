@@ -0,0 +1,35 @@
+package wrapper_test
+
+import (
+	"github.com/baotingfang/go-pivnet-client/wrapper/wrapperfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"time"
+
+	. "github.com/baotingfang/go-pivnet-client/wrapper"
+)
+
+var _ = Describe("RateLimitClient", func() {
+	It("spaces the calls by the rate", func() {
+		fakeClient := &wrapperfakes.FakePivnetClient{}
+		client := NewRateLimitClient(fakeClient, 50)
+
+		start := time.Now()
+		for i := 0; i < 5; i++ {
+			_, err := client.GetAllReleases(ctx, "fakeslug")
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(time.Since(start)).To(BeNumerically(">=", 80*time.Millisecond))
+		Expect(fakeClient.GetAllReleasesCallCount()).To(Equal(5))
+	})
+
+	It("does not limit when the rate is 0", func() {
+		limiter := NewRateLimiter(0)
+
+		start := time.Now()
+		for i := 0; i < 100; i++ {
+			Expect(limiter.Wait(ctx)).To(Succeed())
+		}
+		Expect(time.Since(start)).To(BeNumerically("<", 10*time.Millisecond))
+	})
+})
@@ -0,0 +1,100 @@
+package wrapper
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// CallResponse is the last http response of a pivnet call. go-pivnet only returns
+// the typed errors for the json bodies, the status and Retry-After of the response
+// are kept here, so that the errors with other bodies can be classified too.
+type CallResponse struct {
+	mu            sync.Mutex
+	statusCode    int
+	retryAfter    time.Duration
+	hasRetryAfter bool
+}
+
+type callResponseKey struct{}
+
+// WithCallResponse returns the context in which the responses of a pivnet call are
+// recorded into the returned CallResponse
+func WithCallResponse(ctx context.Context) (context.Context, *CallResponse) {
+	response := &CallResponse{}
+	return context.WithValue(ctx, callResponseKey{}, response), response
+}
+
+func callResponseFrom(ctx context.Context) *CallResponse {
+	response, _ := ctx.Value(callResponseKey{}).(*CallResponse)
+	return response
+}
+
+// StatusCode returns the status of the last response, or 0 when there is no response
+func (r *CallResponse) StatusCode() int {
+	if r == nil {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.statusCode
+}
+
+// RetryAfter returns the delay in the Retry-After header of the last response,
+// which is only set when the call is rate limited or pivnet is unavailable.
+func (r *CallResponse) RetryAfter() (time.Duration, bool) {
+	if r == nil {
+		return 0, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.retryAfter, r.hasRetryAfter
+}
+
+func (r *CallResponse) record(resp *http.Response) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statusCode = resp.StatusCode
+	r.retryAfter, r.hasRetryAfter = 0, false
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		r.retryAfter, r.hasRetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+}
+
+// responseTransport records the responses of a pivnet call
+type responseTransport struct {
+	base     http.RoundTripper
+	response *CallResponse
+}
+
+func (t responseTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	t.response.record(resp)
+	return resp, nil
+}
+
+// parseRetryAfter parses the Retry-After header, which is either seconds or a http date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
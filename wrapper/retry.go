@@ -0,0 +1,310 @@
+package wrapper
+
+import (
+	"context"
+	"github.com/baotingfang/go-pivnet-client/vlog"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = time.Second
+	DefaultMaxBackoff = 30 * time.Second
+)
+
+// RetryPolicy is how the failed pivnet calls are retried, the calls are
+// not retried when MaxRetries is 0. MaxBackoff also caps the Retry-After of pivnet.
+type RetryPolicy struct {
+	MaxRetries int
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		MinBackoff: DefaultMinBackoff,
+		MaxBackoff: DefaultMaxBackoff,
+	}
+}
+
+// backoff returns the exponential backoff of the attempt with jitter,
+// it is between the half and the whole of the backoff.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.MinBackoff
+	for i := 0; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// RetryClient retries the idempotent calls when pivnet is unavailable, rate limited,
+// or the network fails. The calls creating objects are only retried when they are
+// known to be rejected before they take effect.
+type RetryClient struct {
+	Client PivnetClient
+	Policy RetryPolicy
+}
+
+func NewRetryClient(client PivnetClient, policy RetryPolicy) PivnetClient {
+	return &RetryClient{
+		Client: client,
+		Policy: policy,
+	}
+}
+
+// retryable returns whether the call failed with err can be retried, and whether
+// the call is rejected by pivnet or the network before it takes effect. The status
+// of the response is checked when go-pivnet fails to parse the body of the error,
+// like the html pages of 502 from the proxies.
+func retryable(err error, response *CallResponse) (retry bool, rejected bool) {
+	switch e := err.(type) {
+	case pivnet.ErrTooManyRequests:
+		return true, true
+	case pivnet.ErrPivnetOther:
+		return e.ResponseCode >= http.StatusInternalServerError, false
+	case *url.Error:
+		if opErr, ok := e.Err.(*net.OpError); ok && opErr.Op == "dial" {
+			return true, true
+		}
+		return true, false
+	case net.Error:
+		return true, false
+	}
+
+	switch statusCode := response.StatusCode(); {
+	case statusCode == http.StatusTooManyRequests:
+		return true, true
+	case statusCode >= http.StatusInternalServerError:
+		return true, false
+	}
+	return false, false
+}
+
+// do calls the call until it succeeds, or fails with an error which can not be retried.
+// When the call is not idempotent, it is only retried when it is rejected, or
+// notCreated proves that it does not take effect. Every attempt has its own
+// response in the context, so the Retry-After of an attempt only delays the
+// retry of the same call.
+func (r RetryClient) do(ctx context.Context, name string, idempotent bool, notCreated func() bool, call func(ctx context.Context) error) error {
+	attemptCtx, response := WithCallResponse(ctx)
+	err := call(attemptCtx)
+	for attempt := 0; err != nil && attempt < r.Policy.MaxRetries; attempt++ {
+		if ctx.Err() != nil {
+			return err
+		}
+		retry, rejected := retryable(err, response)
+		if !retry {
+			return err
+		}
+		if !idempotent && !rejected && (notCreated == nil || !notCreated()) {
+			return err
+		}
+
+		wait := r.Policy.backoff(attempt)
+		if retryAfter, found := response.RetryAfter(); found {
+			wait = retryAfter
+			if wait > r.Policy.MaxBackoff {
+				wait = r.Policy.MaxBackoff
+			}
+		}
+		vlog.Warn("pivnet call %s failed, retry in %s (%d/%d): %s", name, wait, attempt+1, r.Policy.MaxRetries, err.Error())
+		if sleepErr := sleep(ctx, wait); sleepErr != nil {
+			return err
+		}
+
+		attemptCtx, response = WithCallResponse(ctx)
+		err = call(attemptCtx)
+	}
+	return err
+}
+
+func (r RetryClient) GetAllReleases(ctx context.Context, productSlug string) (releases []pivnet.Release, err error) {
+	err = r.do(ctx, "GetAllReleases", true, nil, func(ctx context.Context) error {
+		releases, err = r.Client.GetAllReleases(ctx, productSlug)
+		return err
+	})
+	return releases, err
+}
+
+func (r RetryClient) CreateRelease(ctx context.Context, releaseConfig pivnet.CreateReleaseConfig) (release pivnet.Release, err error) {
+	// the version of a release is unique in the product, so it can be checked whether the release is created
+	notCreated := func() bool {
+		releases, err := r.Client.GetAllReleases(ctx, releaseConfig.ProductSlug)
+		if err != nil {
+			return false
+		}
+		for _, existing := range releases {
+			if existing.Version == releaseConfig.Version {
+				return false
+			}
+		}
+		return true
+	}
+	err = r.do(ctx, "CreateRelease", false, notCreated, func(ctx context.Context) error {
+		release, err = r.Client.CreateRelease(ctx, releaseConfig)
+		return err
+	})
+	return release, err
+}
+
+func (r RetryClient) DeleteRelease(ctx context.Context, productSlug string, release pivnet.Release) error {
+	return r.do(ctx, "DeleteRelease", true, nil, func(ctx context.Context) error {
+		return r.Client.DeleteRelease(ctx, productSlug, release)
+	})
+}
+
+func (r RetryClient) GetAllFileGroups(ctx context.Context, productSlug string) (fileGroups []pivnet.FileGroup, err error) {
+	err = r.do(ctx, "GetAllFileGroups", true, nil, func(ctx context.Context) error {
+		fileGroups, err = r.Client.GetAllFileGroups(ctx, productSlug)
+		return err
+	})
+	return fileGroups, err
+}
+
+func (r RetryClient) GetFileGroupsForRelease(ctx context.Context, productSlug string, releaseId int) (fileGroups []pivnet.FileGroup, err error) {
+	err = r.do(ctx, "GetFileGroupsForRelease", true, nil, func(ctx context.Context) error {
+		fileGroups, err = r.Client.GetFileGroupsForRelease(ctx, productSlug, releaseId)
+		return err
+	})
+	return fileGroups, err
+}
+
+func (r RetryClient) CreateFileGroup(ctx context.Context, productSlug, groupName string) (fileGroup pivnet.FileGroup, err error) {
+	err = r.do(ctx, "CreateFileGroup", false, nil, func(ctx context.Context) error {
+		fileGroup, err = r.Client.CreateFileGroup(ctx, productSlug, groupName)
+		return err
+	})
+	return fileGroup, err
+}
+
+func (r RetryClient) DeleteFileGroup(ctx context.Context, productSlug string, fileGroupId int) (fileGroup pivnet.FileGroup, err error) {
+	err = r.do(ctx, "DeleteFileGroup", true, nil, func(ctx context.Context) error {
+		fileGroup, err = r.Client.DeleteFileGroup(ctx, productSlug, fileGroupId)
+		return err
+	})
+	return fileGroup, err
+}
+
+func (r RetryClient) CreateFederationToken(ctx context.Context, productSlug string) (token pivnet.FederationToken, err error) {
+	// a federation token is not stored on pivnet, creating it again has no side effect
+	err = r.do(ctx, "CreateFederationToken", true, nil, func(ctx context.Context) error {
+		token, err = r.Client.CreateFederationToken(ctx, productSlug)
+		return err
+	})
+	return token, err
+}
+
+func (r RetryClient) GetAllProductFiles(ctx context.Context, productSlug string) (productFiles []pivnet.ProductFile, err error) {
+	err = r.do(ctx, "GetAllProductFiles", true, nil, func(ctx context.Context) error {
+		productFiles, err = r.Client.GetAllProductFiles(ctx, productSlug)
+		return err
+	})
+	return productFiles, err
+}
+
+func (r RetryClient) GetProductFile(ctx context.Context, productSlug string, productFileId int) (productFile pivnet.ProductFile, err error) {
+	err = r.do(ctx, "GetProductFile", true, nil, func(ctx context.Context) error {
+		productFile, err = r.Client.GetProductFile(ctx, productSlug, productFileId)
+		return err
+	})
+	return productFile, err
+}
+
+func (r RetryClient) GetProductFilesForRelease(ctx context.Context, productSlug string, releaseId int) (productFiles []pivnet.ProductFile, err error) {
+	err = r.do(ctx, "GetProductFilesForRelease", true, nil, func(ctx context.Context) error {
+		productFiles, err = r.Client.GetProductFilesForRelease(ctx, productSlug, releaseId)
+		return err
+	})
+	return productFiles, err
+}
+
+func (r RetryClient) CreateProductFile(ctx context.Context, productFileConfig pivnet.CreateProductFileConfig) (productFile pivnet.ProductFile, err error) {
+	err = r.do(ctx, "CreateProductFile", false, nil, func(ctx context.Context) error {
+		productFile, err = r.Client.CreateProductFile(ctx, productFileConfig)
+		return err
+	})
+	return productFile, err
+}
+
+func (r RetryClient) DeleteProductFile(ctx context.Context, productSlug string, productFileId int) (productFile pivnet.ProductFile, err error) {
+	err = r.do(ctx, "DeleteProductFile", true, nil, func(ctx context.Context) error {
+		productFile, err = r.Client.DeleteProductFile(ctx, productSlug, productFileId)
+		return err
+	})
+	return productFile, err
+}
+
+func (r RetryClient) AddProductFileToFileGroup(ctx context.Context, productSlug string, productFileId, fileGroupId int) error {
+	return r.do(ctx, "AddProductFileToFileGroup", true, nil, func(ctx context.Context) error {
+		return r.Client.AddProductFileToFileGroup(ctx, productSlug, productFileId, fileGroupId)
+	})
+}
+
+func (r RetryClient) AddProductFileToRelease(ctx context.Context, productSlug string, productFileId, releaseId int) error {
+	return r.do(ctx, "AddProductFileToRelease", true, nil, func(ctx context.Context) error {
+		return r.Client.AddProductFileToRelease(ctx, productSlug, productFileId, releaseId)
+	})
+}
+
+func (r RetryClient) AddFileGroupToRelease(ctx context.Context, productSlug string, fileGroupId, releaseId int) error {
+	return r.do(ctx, "AddFileGroupToRelease", true, nil, func(ctx context.Context) error {
+		return r.Client.AddFileGroupToRelease(ctx, productSlug, fileGroupId, releaseId)
+	})
+}
+
+func (r RetryClient) UpdateRelease(ctx context.Context, productSlug string, release pivnet.Release) (updated pivnet.Release, err error) {
+	err = r.do(ctx, "UpdateRelease", true, nil, func(ctx context.Context) error {
+		updated, err = r.Client.UpdateRelease(ctx, productSlug, release)
+		return err
+	})
+	return updated, err
+}
+
+func (r RetryClient) GetAllUserGroups(ctx context.Context) (userGroups []pivnet.UserGroup, err error) {
+	err = r.do(ctx, "GetAllUserGroups", true, nil, func(ctx context.Context) error {
+		userGroups, err = r.Client.GetAllUserGroups(ctx)
+		return err
+	})
+	return userGroups, err
+}
+
+func (r RetryClient) GetUserGroupsForRelease(ctx context.Context, productSlug string, releaseId int) (userGroups []pivnet.UserGroup, err error) {
+	err = r.do(ctx, "GetUserGroupsForRelease", true, nil, func(ctx context.Context) error {
+		userGroups, err = r.Client.GetUserGroupsForRelease(ctx, productSlug, releaseId)
+		return err
+	})
+	return userGroups, err
+}
+
+func (r RetryClient) AddUserGroupToRelease(ctx context.Context, productSlug string, userGroupId, releaseId int) error {
+	return r.do(ctx, "AddUserGroupToRelease", true, nil, func(ctx context.Context) error {
+		return r.Client.AddUserGroupToRelease(ctx, productSlug, userGroupId, releaseId)
+	})
+}
+
+// sleep returns after the duration, or returns the error of ctx when it is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
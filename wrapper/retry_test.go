@@ -0,0 +1,272 @@
+package wrapper_test
+
+import (
+	"context"
+	"errors"
+	"github.com/baotingfang/go-pivnet-client/wrapper/wrapperfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+	"github.com/pivotal-cf/go-pivnet/v4"
+	"github.com/pivotal-cf/go-pivnet/v4/logshim"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	. "github.com/baotingfang/go-pivnet-client/wrapper"
+)
+
+var _ = Describe("RetryClient", func() {
+	var (
+		fakeClient  *wrapperfakes.FakePivnetClient
+		retryClient PivnetClient
+
+		serverError   = pivnet.ErrPivnetOther{ResponseCode: http.StatusBadGateway, Message: "bad gateway"}
+		rateLimited   = pivnet.ErrTooManyRequests{ResponseCode: http.StatusTooManyRequests}
+		notFoundErr   = pivnet.ErrNotFound{ResponseCode: http.StatusNotFound, Message: "not found"}
+		productFile   = pivnet.ProductFile{ID: 1, Name: "file"}
+		releaseSixSix = pivnet.CreateReleaseConfig{ProductSlug: "fakeslug", Version: "6.6.0"}
+	)
+
+	BeforeEach(func() {
+		fakeClient = &wrapperfakes.FakePivnetClient{}
+		retryClient = NewRetryClient(fakeClient, RetryPolicy{
+			MaxRetries: 2,
+			MinBackoff: time.Millisecond,
+			MaxBackoff: 4 * time.Millisecond,
+		})
+	})
+
+	It("retries the idempotent call when pivnet fails", func() {
+		fakeClient.GetProductFileReturnsOnCall(0, pivnet.ProductFile{}, serverError)
+		fakeClient.GetProductFileReturnsOnCall(1, pivnet.ProductFile{}, rateLimited)
+		fakeClient.GetProductFileReturnsOnCall(2, productFile, nil)
+
+		pf, err := retryClient.GetProductFile(ctx, "fakeslug", 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pf).To(Equal(productFile))
+		Expect(fakeClient.GetProductFileCallCount()).To(Equal(3))
+	})
+
+	It("returns the last error when all the retries failed", func() {
+		fakeClient.AddProductFileToReleaseReturns(serverError)
+
+		err := retryClient.AddProductFileToRelease(ctx, "fakeslug", 1, 10)
+		Expect(err).To(Equal(serverError))
+		Expect(fakeClient.AddProductFileToReleaseCallCount()).To(Equal(3))
+	})
+
+	It("does not retry the error of the request", func() {
+		fakeClient.GetProductFileReturns(pivnet.ProductFile{}, notFoundErr)
+
+		_, err := retryClient.GetProductFile(ctx, "fakeslug", 1)
+		Expect(err).To(Equal(notFoundErr))
+		Expect(fakeClient.GetProductFileCallCount()).To(Equal(1))
+	})
+
+	It("does not retry when max retries is 0", func() {
+		retryClient = NewRetryClient(fakeClient, RetryPolicy{})
+		fakeClient.GetAllReleasesReturns(nil, serverError)
+
+		_, err := retryClient.GetAllReleases(ctx, "fakeslug")
+		Expect(err).To(Equal(serverError))
+		Expect(fakeClient.GetAllReleasesCallCount()).To(Equal(1))
+	})
+
+	It("does not retry the errors without a response", func() {
+		fakeClient.DeleteReleaseReturns(errors.New("invalid release"))
+
+		err := retryClient.DeleteRelease(ctx, "fakeslug", pivnet.Release{ID: 10})
+		Expect(err).To(HaveOccurred())
+		Expect(fakeClient.DeleteReleaseCallCount()).To(Equal(1))
+	})
+
+	Context("create", func() {
+		It("does not retry when the product file may be created", func() {
+			fakeClient.CreateProductFileReturns(pivnet.ProductFile{}, serverError)
+
+			_, err := retryClient.CreateProductFile(ctx, pivnet.CreateProductFileConfig{})
+			Expect(err).To(Equal(serverError))
+			Expect(fakeClient.CreateProductFileCallCount()).To(Equal(1))
+		})
+
+		It("retries when the call is rate limited", func() {
+			fakeClient.CreateFileGroupReturnsOnCall(0, pivnet.FileGroup{}, rateLimited)
+			fakeClient.CreateFileGroupReturnsOnCall(1, pivnet.FileGroup{ID: 20}, nil)
+
+			group, err := retryClient.CreateFileGroup(ctx, "fakeslug", "group")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(group.ID).To(Equal(20))
+			Expect(fakeClient.CreateFileGroupCallCount()).To(Equal(2))
+		})
+
+		It("retries when the release is not created", func() {
+			fakeClient.CreateReleaseReturnsOnCall(0, pivnet.Release{}, serverError)
+			fakeClient.CreateReleaseReturnsOnCall(1, pivnet.Release{ID: 10, Version: "6.6.0"}, nil)
+			fakeClient.GetAllReleasesReturns([]pivnet.Release{{ID: 9, Version: "6.5.0"}}, nil)
+
+			release, err := retryClient.CreateRelease(ctx, releaseSixSix)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(release.ID).To(Equal(10))
+			Expect(fakeClient.CreateReleaseCallCount()).To(Equal(2))
+			_, productSlug := fakeClient.GetAllReleasesArgsForCall(0)
+			Expect(productSlug).To(Equal("fakeslug"))
+		})
+
+		It("does not retry when the release may be created", func() {
+			fakeClient.CreateReleaseReturns(pivnet.Release{}, serverError)
+			fakeClient.GetAllReleasesReturns([]pivnet.Release{{ID: 10, Version: "6.6.0"}}, nil)
+
+			_, err := retryClient.CreateRelease(ctx, releaseSixSix)
+			Expect(err).To(Equal(serverError))
+			Expect(fakeClient.CreateReleaseCallCount()).To(Equal(1))
+		})
+
+		It("does not retry when the releases can not be listed", func() {
+			fakeClient.CreateReleaseReturns(pivnet.Release{}, serverError)
+			fakeClient.GetAllReleasesReturns(nil, serverError)
+
+			_, err := retryClient.CreateRelease(ctx, releaseSixSix)
+			Expect(err).To(Equal(serverError))
+			Expect(fakeClient.CreateReleaseCallCount()).To(Equal(1))
+		})
+	})
+
+	Context("Retry-After", func() {
+		var (
+			server *ghttp.Server
+			client PivnetClient
+		)
+
+		BeforeEach(func() {
+			server = ghttp.NewServer()
+
+			logger := logshim.NewLogShim(log.New(os.Stdout, "", 0), log.New(os.Stderr, "", 0), false)
+			client = NewClient(&wrapperfakes.FakeAccessTokenService{}, pivnet.ClientConfig{Host: server.URL()}, logger)
+			retryClient = NewRetryClient(client, RetryPolicy{
+				MaxRetries: 1,
+				MinBackoff: time.Millisecond,
+				MaxBackoff: time.Millisecond,
+			})
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("waits for the delay in Retry-After", func() {
+			retryClient = NewRetryClient(client, RetryPolicy{
+				MaxRetries: 1,
+				MinBackoff: time.Millisecond,
+				MaxBackoff: 2 * time.Second,
+			})
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/product_files/1"),
+					ghttp.RespondWith(http.StatusTooManyRequests, "", http.Header{"Retry-After": []string{"1"}}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v2/products/fakeslug/product_files/1"),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFileResponse{ProductFile: productFile}),
+				),
+			)
+
+			start := time.Now()
+			pf, err := retryClient.GetProductFile(ctx, "fakeslug", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pf.ID).To(Equal(1))
+			Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("does not wait longer than the max backoff for the Retry-After", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, "", http.Header{"Retry-After": []string{"30"}}),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFileResponse{ProductFile: productFile}),
+			)
+
+			start := time.Now()
+			pf, err := retryClient.GetProductFile(ctx, "fakeslug", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pf.ID).To(Equal(1))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("retries the 5xx whose body is not json", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusBadGateway, "<html><body>502 Bad Gateway</body></html>"),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFileResponse{ProductFile: productFile}),
+			)
+
+			pf, err := retryClient.GetProductFile(ctx, "fakeslug", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pf.ID).To(Equal(1))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("does not retry the 4xx whose body is not json", func() {
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusBadRequest, "<html><body>400 Bad Request</body></html>"),
+			)
+
+			_, err := retryClient.GetProductFile(ctx, "fakeslug", 1)
+			Expect(err).To(HaveOccurred())
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+
+		It("retries the product file creation which is rate limited", func() {
+			// go-pivnet replaces the rate limit error of the creation with a plain error
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, ""),
+				ghttp.RespondWithJSONEncoded(http.StatusCreated, pivnet.ProductFileResponse{ProductFile: productFile}),
+			)
+
+			pf, err := retryClient.CreateProductFile(ctx, pivnet.CreateProductFileConfig{ProductSlug: "fakeslug", AWSObjectKey: "6.6.0/file"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pf.ID).To(Equal(1))
+			Expect(server.ReceivedRequests()).To(HaveLen(2))
+		})
+
+		It("does not wait for the Retry-After of the other calls", func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", "/api/v2/products/fakeslug/product_files"),
+					ghttp.RespondWith(http.StatusServiceUnavailable, `{"message": "unavailable"}`, http.Header{"Retry-After": []string{"30"}}),
+				),
+				ghttp.RespondWith(http.StatusInternalServerError, `{"message": "internal error"}`),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, pivnet.ProductFileResponse{ProductFile: productFile}),
+			)
+
+			_, err := retryClient.CreateProductFile(ctx, pivnet.CreateProductFileConfig{ProductSlug: "fakeslug", AWSObjectKey: "6.6.0/file"})
+			Expect(err).To(HaveOccurred())
+
+			start := time.Now()
+			pf, err := retryClient.GetProductFile(ctx, "fakeslug", 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pf.ID).To(Equal(1))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			Expect(server.ReceivedRequests()).To(HaveLen(3))
+		})
+
+		It("stops waiting when the context is cancelled", func() {
+			retryClient = NewRetryClient(client, RetryPolicy{
+				MaxRetries: 1,
+				MinBackoff: time.Millisecond,
+				MaxBackoff: time.Minute,
+			})
+			server.AppendHandlers(
+				ghttp.RespondWith(http.StatusTooManyRequests, "", http.Header{"Retry-After": []string{"30"}}),
+			)
+
+			cancelCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			_, err := retryClient.GetProductFile(cancelCtx, "fakeslug", 1)
+			Expect(err).To(BeAssignableToTypeOf(pivnet.ErrTooManyRequests{}))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			Expect(server.ReceivedRequests()).To(HaveLen(1))
+		})
+	})
+})
@@ -0,0 +1,17 @@
+package wrapper_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// ctx is the context of the calls in the tests, which is never cancelled
+var ctx = context.Background()
+
+func TestWrapper(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Wrapper Suite")
+}
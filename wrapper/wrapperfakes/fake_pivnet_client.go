@@ -2,6 +2,7 @@
 package wrapperfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/baotingfang/go-pivnet-client/wrapper"
@@ -9,12 +10,13 @@ import (
 )
 
 type FakePivnetClient struct {
-	AddFileGroupToReleaseStub        func(string, int, int) error
+	AddFileGroupToReleaseStub        func(context.Context, string, int, int) error
 	addFileGroupToReleaseMutex       sync.RWMutex
 	addFileGroupToReleaseArgsForCall []struct {
-		arg1 string
-		arg2 int
+		arg1 context.Context
+		arg2 string
 		arg3 int
+		arg4 int
 	}
 	addFileGroupToReleaseReturns struct {
 		result1 error
@@ -22,12 +24,13 @@ type FakePivnetClient struct {
 	addFileGroupToReleaseReturnsOnCall map[int]struct {
 		result1 error
 	}
-	AddProductFileToFileGroupStub        func(string, int, int) error
+	AddProductFileToFileGroupStub        func(context.Context, string, int, int) error
 	addProductFileToFileGroupMutex       sync.RWMutex
 	addProductFileToFileGroupArgsForCall []struct {
-		arg1 string
-		arg2 int
+		arg1 context.Context
+		arg2 string
 		arg3 int
+		arg4 int
 	}
 	addProductFileToFileGroupReturns struct {
 		result1 error
@@ -35,12 +38,13 @@ type FakePivnetClient struct {
 	addProductFileToFileGroupReturnsOnCall map[int]struct {
 		result1 error
 	}
-	AddProductFileToReleaseStub        func(string, int, int) error
+	AddProductFileToReleaseStub        func(context.Context, string, int, int) error
 	addProductFileToReleaseMutex       sync.RWMutex
 	addProductFileToReleaseArgsForCall []struct {
-		arg1 string
-		arg2 int
+		arg1 context.Context
+		arg2 string
 		arg3 int
+		arg4 int
 	}
 	addProductFileToReleaseReturns struct {
 		result1 error
@@ -48,10 +52,25 @@ type FakePivnetClient struct {
 	addProductFileToReleaseReturnsOnCall map[int]struct {
 		result1 error
 	}
-	CreateFederationTokenStub        func(string) (pivnet.FederationToken, error)
+	AddUserGroupToReleaseStub        func(context.Context, string, int, int) error
+	addUserGroupToReleaseMutex       sync.RWMutex
+	addUserGroupToReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 int
+	}
+	addUserGroupToReleaseReturns struct {
+		result1 error
+	}
+	addUserGroupToReleaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CreateFederationTokenStub        func(context.Context, string) (pivnet.FederationToken, error)
 	createFederationTokenMutex       sync.RWMutex
 	createFederationTokenArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	createFederationTokenReturns struct {
 		result1 pivnet.FederationToken
@@ -61,11 +80,12 @@ type FakePivnetClient struct {
 		result1 pivnet.FederationToken
 		result2 error
 	}
-	CreateFileGroupStub        func(string, string) (pivnet.FileGroup, error)
+	CreateFileGroupStub        func(context.Context, string, string) (pivnet.FileGroup, error)
 	createFileGroupMutex       sync.RWMutex
 	createFileGroupArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
 	}
 	createFileGroupReturns struct {
 		result1 pivnet.FileGroup
@@ -75,10 +95,11 @@ type FakePivnetClient struct {
 		result1 pivnet.FileGroup
 		result2 error
 	}
-	CreateProductFileStub        func(pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)
+	CreateProductFileStub        func(context.Context, pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)
 	createProductFileMutex       sync.RWMutex
 	createProductFileArgsForCall []struct {
-		arg1 pivnet.CreateProductFileConfig
+		arg1 context.Context
+		arg2 pivnet.CreateProductFileConfig
 	}
 	createProductFileReturns struct {
 		result1 pivnet.ProductFile
@@ -88,10 +109,11 @@ type FakePivnetClient struct {
 		result1 pivnet.ProductFile
 		result2 error
 	}
-	CreateReleaseStub        func(pivnet.CreateReleaseConfig) (pivnet.Release, error)
+	CreateReleaseStub        func(context.Context, pivnet.CreateReleaseConfig) (pivnet.Release, error)
 	createReleaseMutex       sync.RWMutex
 	createReleaseArgsForCall []struct {
-		arg1 pivnet.CreateReleaseConfig
+		arg1 context.Context
+		arg2 pivnet.CreateReleaseConfig
 	}
 	createReleaseReturns struct {
 		result1 pivnet.Release
@@ -101,11 +123,27 @@ type FakePivnetClient struct {
 		result1 pivnet.Release
 		result2 error
 	}
-	DeleteProductFileStub        func(string, int) (pivnet.ProductFile, error)
+	DeleteFileGroupStub        func(context.Context, string, int) (pivnet.FileGroup, error)
+	deleteFileGroupMutex       sync.RWMutex
+	deleteFileGroupArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	deleteFileGroupReturns struct {
+		result1 pivnet.FileGroup
+		result2 error
+	}
+	deleteFileGroupReturnsOnCall map[int]struct {
+		result1 pivnet.FileGroup
+		result2 error
+	}
+	DeleteProductFileStub        func(context.Context, string, int) (pivnet.ProductFile, error)
 	deleteProductFileMutex       sync.RWMutex
 	deleteProductFileArgsForCall []struct {
-		arg1 string
-		arg2 int
+		arg1 context.Context
+		arg2 string
+		arg3 int
 	}
 	deleteProductFileReturns struct {
 		result1 pivnet.ProductFile
@@ -115,10 +153,52 @@ type FakePivnetClient struct {
 		result1 pivnet.ProductFile
 		result2 error
 	}
-	GetAllReleasesStub        func(string) ([]pivnet.Release, error)
+	DeleteReleaseStub        func(context.Context, string, pivnet.Release) error
+	deleteReleaseMutex       sync.RWMutex
+	deleteReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 pivnet.Release
+	}
+	deleteReleaseReturns struct {
+		result1 error
+	}
+	deleteReleaseReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetAllFileGroupsStub        func(context.Context, string) ([]pivnet.FileGroup, error)
+	getAllFileGroupsMutex       sync.RWMutex
+	getAllFileGroupsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getAllFileGroupsReturns struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}
+	getAllFileGroupsReturnsOnCall map[int]struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}
+	GetAllProductFilesStub        func(context.Context, string) ([]pivnet.ProductFile, error)
+	getAllProductFilesMutex       sync.RWMutex
+	getAllProductFilesArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getAllProductFilesReturns struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}
+	getAllProductFilesReturnsOnCall map[int]struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}
+	GetAllReleasesStub        func(context.Context, string) ([]pivnet.Release, error)
 	getAllReleasesMutex       sync.RWMutex
 	getAllReleasesArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	getAllReleasesReturns struct {
 		result1 []pivnet.Release
@@ -128,11 +208,40 @@ type FakePivnetClient struct {
 		result1 []pivnet.Release
 		result2 error
 	}
-	GetProductFileStub        func(string, int) (pivnet.ProductFile, error)
+	GetAllUserGroupsStub        func(context.Context) ([]pivnet.UserGroup, error)
+	getAllUserGroupsMutex       sync.RWMutex
+	getAllUserGroupsArgsForCall []struct {
+		arg1 context.Context
+	}
+	getAllUserGroupsReturns struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}
+	getAllUserGroupsReturnsOnCall map[int]struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}
+	GetFileGroupsForReleaseStub        func(context.Context, string, int) ([]pivnet.FileGroup, error)
+	getFileGroupsForReleaseMutex       sync.RWMutex
+	getFileGroupsForReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	getFileGroupsForReleaseReturns struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}
+	getFileGroupsForReleaseReturnsOnCall map[int]struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}
+	GetProductFileStub        func(context.Context, string, int) (pivnet.ProductFile, error)
 	getProductFileMutex       sync.RWMutex
 	getProductFileArgsForCall []struct {
-		arg1 string
-		arg2 int
+		arg1 context.Context
+		arg2 string
+		arg3 int
 	}
 	getProductFileReturns struct {
 		result1 pivnet.ProductFile
@@ -142,11 +251,42 @@ type FakePivnetClient struct {
 		result1 pivnet.ProductFile
 		result2 error
 	}
-	UpdateReleaseStub        func(string, pivnet.Release) (pivnet.Release, error)
+	GetProductFilesForReleaseStub        func(context.Context, string, int) ([]pivnet.ProductFile, error)
+	getProductFilesForReleaseMutex       sync.RWMutex
+	getProductFilesForReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	getProductFilesForReleaseReturns struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}
+	getProductFilesForReleaseReturnsOnCall map[int]struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}
+	GetUserGroupsForReleaseStub        func(context.Context, string, int) ([]pivnet.UserGroup, error)
+	getUserGroupsForReleaseMutex       sync.RWMutex
+	getUserGroupsForReleaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}
+	getUserGroupsForReleaseReturns struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}
+	getUserGroupsForReleaseReturnsOnCall map[int]struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}
+	UpdateReleaseStub        func(context.Context, string, pivnet.Release) (pivnet.Release, error)
 	updateReleaseMutex       sync.RWMutex
 	updateReleaseArgsForCall []struct {
-		arg1 string
-		arg2 pivnet.Release
+		arg1 context.Context
+		arg2 string
+		arg3 pivnet.Release
 	}
 	updateReleaseReturns struct {
 		result1 pivnet.Release
@@ -160,18 +300,19 @@ type FakePivnetClient struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakePivnetClient) AddFileGroupToRelease(arg1 string, arg2 int, arg3 int) error {
+func (fake *FakePivnetClient) AddFileGroupToRelease(arg1 context.Context, arg2 string, arg3 int, arg4 int) error {
 	fake.addFileGroupToReleaseMutex.Lock()
 	ret, specificReturn := fake.addFileGroupToReleaseReturnsOnCall[len(fake.addFileGroupToReleaseArgsForCall)]
 	fake.addFileGroupToReleaseArgsForCall = append(fake.addFileGroupToReleaseArgsForCall, struct {
-		arg1 string
-		arg2 int
+		arg1 context.Context
+		arg2 string
 		arg3 int
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("AddFileGroupToRelease", []interface{}{arg1, arg2, arg3})
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("AddFileGroupToRelease", []interface{}{arg1, arg2, arg3, arg4})
 	fake.addFileGroupToReleaseMutex.Unlock()
 	if fake.AddFileGroupToReleaseStub != nil {
-		return fake.AddFileGroupToReleaseStub(arg1, arg2, arg3)
+		return fake.AddFileGroupToReleaseStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -186,17 +327,17 @@ func (fake *FakePivnetClient) AddFileGroupToReleaseCallCount() int {
 	return len(fake.addFileGroupToReleaseArgsForCall)
 }
 
-func (fake *FakePivnetClient) AddFileGroupToReleaseCalls(stub func(string, int, int) error) {
+func (fake *FakePivnetClient) AddFileGroupToReleaseCalls(stub func(context.Context, string, int, int) error) {
 	fake.addFileGroupToReleaseMutex.Lock()
 	defer fake.addFileGroupToReleaseMutex.Unlock()
 	fake.AddFileGroupToReleaseStub = stub
 }
 
-func (fake *FakePivnetClient) AddFileGroupToReleaseArgsForCall(i int) (string, int, int) {
+func (fake *FakePivnetClient) AddFileGroupToReleaseArgsForCall(i int) (context.Context, string, int, int) {
 	fake.addFileGroupToReleaseMutex.RLock()
 	defer fake.addFileGroupToReleaseMutex.RUnlock()
 	argsForCall := fake.addFileGroupToReleaseArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakePivnetClient) AddFileGroupToReleaseReturns(result1 error) {
@@ -222,18 +363,19 @@ func (fake *FakePivnetClient) AddFileGroupToReleaseReturnsOnCall(i int, result1
 	}{result1}
 }
 
-func (fake *FakePivnetClient) AddProductFileToFileGroup(arg1 string, arg2 int, arg3 int) error {
+func (fake *FakePivnetClient) AddProductFileToFileGroup(arg1 context.Context, arg2 string, arg3 int, arg4 int) error {
 	fake.addProductFileToFileGroupMutex.Lock()
 	ret, specificReturn := fake.addProductFileToFileGroupReturnsOnCall[len(fake.addProductFileToFileGroupArgsForCall)]
 	fake.addProductFileToFileGroupArgsForCall = append(fake.addProductFileToFileGroupArgsForCall, struct {
-		arg1 string
-		arg2 int
+		arg1 context.Context
+		arg2 string
 		arg3 int
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("AddProductFileToFileGroup", []interface{}{arg1, arg2, arg3})
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("AddProductFileToFileGroup", []interface{}{arg1, arg2, arg3, arg4})
 	fake.addProductFileToFileGroupMutex.Unlock()
 	if fake.AddProductFileToFileGroupStub != nil {
-		return fake.AddProductFileToFileGroupStub(arg1, arg2, arg3)
+		return fake.AddProductFileToFileGroupStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -248,17 +390,17 @@ func (fake *FakePivnetClient) AddProductFileToFileGroupCallCount() int {
 	return len(fake.addProductFileToFileGroupArgsForCall)
 }
 
-func (fake *FakePivnetClient) AddProductFileToFileGroupCalls(stub func(string, int, int) error) {
+func (fake *FakePivnetClient) AddProductFileToFileGroupCalls(stub func(context.Context, string, int, int) error) {
 	fake.addProductFileToFileGroupMutex.Lock()
 	defer fake.addProductFileToFileGroupMutex.Unlock()
 	fake.AddProductFileToFileGroupStub = stub
 }
 
-func (fake *FakePivnetClient) AddProductFileToFileGroupArgsForCall(i int) (string, int, int) {
+func (fake *FakePivnetClient) AddProductFileToFileGroupArgsForCall(i int) (context.Context, string, int, int) {
 	fake.addProductFileToFileGroupMutex.RLock()
 	defer fake.addProductFileToFileGroupMutex.RUnlock()
 	argsForCall := fake.addProductFileToFileGroupArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakePivnetClient) AddProductFileToFileGroupReturns(result1 error) {
@@ -284,18 +426,19 @@ func (fake *FakePivnetClient) AddProductFileToFileGroupReturnsOnCall(i int, resu
 	}{result1}
 }
 
-func (fake *FakePivnetClient) AddProductFileToRelease(arg1 string, arg2 int, arg3 int) error {
+func (fake *FakePivnetClient) AddProductFileToRelease(arg1 context.Context, arg2 string, arg3 int, arg4 int) error {
 	fake.addProductFileToReleaseMutex.Lock()
 	ret, specificReturn := fake.addProductFileToReleaseReturnsOnCall[len(fake.addProductFileToReleaseArgsForCall)]
 	fake.addProductFileToReleaseArgsForCall = append(fake.addProductFileToReleaseArgsForCall, struct {
-		arg1 string
-		arg2 int
+		arg1 context.Context
+		arg2 string
 		arg3 int
-	}{arg1, arg2, arg3})
-	fake.recordInvocation("AddProductFileToRelease", []interface{}{arg1, arg2, arg3})
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("AddProductFileToRelease", []interface{}{arg1, arg2, arg3, arg4})
 	fake.addProductFileToReleaseMutex.Unlock()
 	if fake.AddProductFileToReleaseStub != nil {
-		return fake.AddProductFileToReleaseStub(arg1, arg2, arg3)
+		return fake.AddProductFileToReleaseStub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1
@@ -310,17 +453,17 @@ func (fake *FakePivnetClient) AddProductFileToReleaseCallCount() int {
 	return len(fake.addProductFileToReleaseArgsForCall)
 }
 
-func (fake *FakePivnetClient) AddProductFileToReleaseCalls(stub func(string, int, int) error) {
+func (fake *FakePivnetClient) AddProductFileToReleaseCalls(stub func(context.Context, string, int, int) error) {
 	fake.addProductFileToReleaseMutex.Lock()
 	defer fake.addProductFileToReleaseMutex.Unlock()
 	fake.AddProductFileToReleaseStub = stub
 }
 
-func (fake *FakePivnetClient) AddProductFileToReleaseArgsForCall(i int) (string, int, int) {
+func (fake *FakePivnetClient) AddProductFileToReleaseArgsForCall(i int) (context.Context, string, int, int) {
 	fake.addProductFileToReleaseMutex.RLock()
 	defer fake.addProductFileToReleaseMutex.RUnlock()
 	argsForCall := fake.addProductFileToReleaseArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakePivnetClient) AddProductFileToReleaseReturns(result1 error) {
@@ -346,16 +489,80 @@ func (fake *FakePivnetClient) AddProductFileToReleaseReturnsOnCall(i int, result
 	}{result1}
 }
 
-func (fake *FakePivnetClient) CreateFederationToken(arg1 string) (pivnet.FederationToken, error) {
+func (fake *FakePivnetClient) AddUserGroupToRelease(arg1 context.Context, arg2 string, arg3 int, arg4 int) error {
+	fake.addUserGroupToReleaseMutex.Lock()
+	ret, specificReturn := fake.addUserGroupToReleaseReturnsOnCall[len(fake.addUserGroupToReleaseArgsForCall)]
+	fake.addUserGroupToReleaseArgsForCall = append(fake.addUserGroupToReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+		arg4 int
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("AddUserGroupToRelease", []interface{}{arg1, arg2, arg3, arg4})
+	fake.addUserGroupToReleaseMutex.Unlock()
+	if fake.AddUserGroupToReleaseStub != nil {
+		return fake.AddUserGroupToReleaseStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.addUserGroupToReleaseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePivnetClient) AddUserGroupToReleaseCallCount() int {
+	fake.addUserGroupToReleaseMutex.RLock()
+	defer fake.addUserGroupToReleaseMutex.RUnlock()
+	return len(fake.addUserGroupToReleaseArgsForCall)
+}
+
+func (fake *FakePivnetClient) AddUserGroupToReleaseCalls(stub func(context.Context, string, int, int) error) {
+	fake.addUserGroupToReleaseMutex.Lock()
+	defer fake.addUserGroupToReleaseMutex.Unlock()
+	fake.AddUserGroupToReleaseStub = stub
+}
+
+func (fake *FakePivnetClient) AddUserGroupToReleaseArgsForCall(i int) (context.Context, string, int, int) {
+	fake.addUserGroupToReleaseMutex.RLock()
+	defer fake.addUserGroupToReleaseMutex.RUnlock()
+	argsForCall := fake.addUserGroupToReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakePivnetClient) AddUserGroupToReleaseReturns(result1 error) {
+	fake.addUserGroupToReleaseMutex.Lock()
+	defer fake.addUserGroupToReleaseMutex.Unlock()
+	fake.AddUserGroupToReleaseStub = nil
+	fake.addUserGroupToReleaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePivnetClient) AddUserGroupToReleaseReturnsOnCall(i int, result1 error) {
+	fake.addUserGroupToReleaseMutex.Lock()
+	defer fake.addUserGroupToReleaseMutex.Unlock()
+	fake.AddUserGroupToReleaseStub = nil
+	if fake.addUserGroupToReleaseReturnsOnCall == nil {
+		fake.addUserGroupToReleaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.addUserGroupToReleaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePivnetClient) CreateFederationToken(arg1 context.Context, arg2 string) (pivnet.FederationToken, error) {
 	fake.createFederationTokenMutex.Lock()
 	ret, specificReturn := fake.createFederationTokenReturnsOnCall[len(fake.createFederationTokenArgsForCall)]
 	fake.createFederationTokenArgsForCall = append(fake.createFederationTokenArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("CreateFederationToken", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("CreateFederationToken", []interface{}{arg1, arg2})
 	fake.createFederationTokenMutex.Unlock()
 	if fake.CreateFederationTokenStub != nil {
-		return fake.CreateFederationTokenStub(arg1)
+		return fake.CreateFederationTokenStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -370,17 +577,17 @@ func (fake *FakePivnetClient) CreateFederationTokenCallCount() int {
 	return len(fake.createFederationTokenArgsForCall)
 }
 
-func (fake *FakePivnetClient) CreateFederationTokenCalls(stub func(string) (pivnet.FederationToken, error)) {
+func (fake *FakePivnetClient) CreateFederationTokenCalls(stub func(context.Context, string) (pivnet.FederationToken, error)) {
 	fake.createFederationTokenMutex.Lock()
 	defer fake.createFederationTokenMutex.Unlock()
 	fake.CreateFederationTokenStub = stub
 }
 
-func (fake *FakePivnetClient) CreateFederationTokenArgsForCall(i int) string {
+func (fake *FakePivnetClient) CreateFederationTokenArgsForCall(i int) (context.Context, string) {
 	fake.createFederationTokenMutex.RLock()
 	defer fake.createFederationTokenMutex.RUnlock()
 	argsForCall := fake.createFederationTokenArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakePivnetClient) CreateFederationTokenReturns(result1 pivnet.FederationToken, result2 error) {
@@ -409,17 +616,18 @@ func (fake *FakePivnetClient) CreateFederationTokenReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakePivnetClient) CreateFileGroup(arg1 string, arg2 string) (pivnet.FileGroup, error) {
+func (fake *FakePivnetClient) CreateFileGroup(arg1 context.Context, arg2 string, arg3 string) (pivnet.FileGroup, error) {
 	fake.createFileGroupMutex.Lock()
 	ret, specificReturn := fake.createFileGroupReturnsOnCall[len(fake.createFileGroupArgsForCall)]
 	fake.createFileGroupArgsForCall = append(fake.createFileGroupArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
-	fake.recordInvocation("CreateFileGroup", []interface{}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("CreateFileGroup", []interface{}{arg1, arg2, arg3})
 	fake.createFileGroupMutex.Unlock()
 	if fake.CreateFileGroupStub != nil {
-		return fake.CreateFileGroupStub(arg1, arg2)
+		return fake.CreateFileGroupStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -434,17 +642,17 @@ func (fake *FakePivnetClient) CreateFileGroupCallCount() int {
 	return len(fake.createFileGroupArgsForCall)
 }
 
-func (fake *FakePivnetClient) CreateFileGroupCalls(stub func(string, string) (pivnet.FileGroup, error)) {
+func (fake *FakePivnetClient) CreateFileGroupCalls(stub func(context.Context, string, string) (pivnet.FileGroup, error)) {
 	fake.createFileGroupMutex.Lock()
 	defer fake.createFileGroupMutex.Unlock()
 	fake.CreateFileGroupStub = stub
 }
 
-func (fake *FakePivnetClient) CreateFileGroupArgsForCall(i int) (string, string) {
+func (fake *FakePivnetClient) CreateFileGroupArgsForCall(i int) (context.Context, string, string) {
 	fake.createFileGroupMutex.RLock()
 	defer fake.createFileGroupMutex.RUnlock()
 	argsForCall := fake.createFileGroupArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakePivnetClient) CreateFileGroupReturns(result1 pivnet.FileGroup, result2 error) {
@@ -473,16 +681,17 @@ func (fake *FakePivnetClient) CreateFileGroupReturnsOnCall(i int, result1 pivnet
 	}{result1, result2}
 }
 
-func (fake *FakePivnetClient) CreateProductFile(arg1 pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
+func (fake *FakePivnetClient) CreateProductFile(arg1 context.Context, arg2 pivnet.CreateProductFileConfig) (pivnet.ProductFile, error) {
 	fake.createProductFileMutex.Lock()
 	ret, specificReturn := fake.createProductFileReturnsOnCall[len(fake.createProductFileArgsForCall)]
 	fake.createProductFileArgsForCall = append(fake.createProductFileArgsForCall, struct {
-		arg1 pivnet.CreateProductFileConfig
-	}{arg1})
-	fake.recordInvocation("CreateProductFile", []interface{}{arg1})
+		arg1 context.Context
+		arg2 pivnet.CreateProductFileConfig
+	}{arg1, arg2})
+	fake.recordInvocation("CreateProductFile", []interface{}{arg1, arg2})
 	fake.createProductFileMutex.Unlock()
 	if fake.CreateProductFileStub != nil {
-		return fake.CreateProductFileStub(arg1)
+		return fake.CreateProductFileStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -497,17 +706,17 @@ func (fake *FakePivnetClient) CreateProductFileCallCount() int {
 	return len(fake.createProductFileArgsForCall)
 }
 
-func (fake *FakePivnetClient) CreateProductFileCalls(stub func(pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)) {
+func (fake *FakePivnetClient) CreateProductFileCalls(stub func(context.Context, pivnet.CreateProductFileConfig) (pivnet.ProductFile, error)) {
 	fake.createProductFileMutex.Lock()
 	defer fake.createProductFileMutex.Unlock()
 	fake.CreateProductFileStub = stub
 }
 
-func (fake *FakePivnetClient) CreateProductFileArgsForCall(i int) pivnet.CreateProductFileConfig {
+func (fake *FakePivnetClient) CreateProductFileArgsForCall(i int) (context.Context, pivnet.CreateProductFileConfig) {
 	fake.createProductFileMutex.RLock()
 	defer fake.createProductFileMutex.RUnlock()
 	argsForCall := fake.createProductFileArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakePivnetClient) CreateProductFileReturns(result1 pivnet.ProductFile, result2 error) {
@@ -536,16 +745,17 @@ func (fake *FakePivnetClient) CreateProductFileReturnsOnCall(i int, result1 pivn
 	}{result1, result2}
 }
 
-func (fake *FakePivnetClient) CreateRelease(arg1 pivnet.CreateReleaseConfig) (pivnet.Release, error) {
+func (fake *FakePivnetClient) CreateRelease(arg1 context.Context, arg2 pivnet.CreateReleaseConfig) (pivnet.Release, error) {
 	fake.createReleaseMutex.Lock()
 	ret, specificReturn := fake.createReleaseReturnsOnCall[len(fake.createReleaseArgsForCall)]
 	fake.createReleaseArgsForCall = append(fake.createReleaseArgsForCall, struct {
-		arg1 pivnet.CreateReleaseConfig
-	}{arg1})
-	fake.recordInvocation("CreateRelease", []interface{}{arg1})
+		arg1 context.Context
+		arg2 pivnet.CreateReleaseConfig
+	}{arg1, arg2})
+	fake.recordInvocation("CreateRelease", []interface{}{arg1, arg2})
 	fake.createReleaseMutex.Unlock()
 	if fake.CreateReleaseStub != nil {
-		return fake.CreateReleaseStub(arg1)
+		return fake.CreateReleaseStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -560,17 +770,17 @@ func (fake *FakePivnetClient) CreateReleaseCallCount() int {
 	return len(fake.createReleaseArgsForCall)
 }
 
-func (fake *FakePivnetClient) CreateReleaseCalls(stub func(pivnet.CreateReleaseConfig) (pivnet.Release, error)) {
+func (fake *FakePivnetClient) CreateReleaseCalls(stub func(context.Context, pivnet.CreateReleaseConfig) (pivnet.Release, error)) {
 	fake.createReleaseMutex.Lock()
 	defer fake.createReleaseMutex.Unlock()
 	fake.CreateReleaseStub = stub
 }
 
-func (fake *FakePivnetClient) CreateReleaseArgsForCall(i int) pivnet.CreateReleaseConfig {
+func (fake *FakePivnetClient) CreateReleaseArgsForCall(i int) (context.Context, pivnet.CreateReleaseConfig) {
 	fake.createReleaseMutex.RLock()
 	defer fake.createReleaseMutex.RUnlock()
 	argsForCall := fake.createReleaseArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakePivnetClient) CreateReleaseReturns(result1 pivnet.Release, result2 error) {
@@ -599,17 +809,83 @@ func (fake *FakePivnetClient) CreateReleaseReturnsOnCall(i int, result1 pivnet.R
 	}{result1, result2}
 }
 
-func (fake *FakePivnetClient) DeleteProductFile(arg1 string, arg2 int) (pivnet.ProductFile, error) {
+func (fake *FakePivnetClient) DeleteFileGroup(arg1 context.Context, arg2 string, arg3 int) (pivnet.FileGroup, error) {
+	fake.deleteFileGroupMutex.Lock()
+	ret, specificReturn := fake.deleteFileGroupReturnsOnCall[len(fake.deleteFileGroupArgsForCall)]
+	fake.deleteFileGroupArgsForCall = append(fake.deleteFileGroupArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("DeleteFileGroup", []interface{}{arg1, arg2, arg3})
+	fake.deleteFileGroupMutex.Unlock()
+	if fake.DeleteFileGroupStub != nil {
+		return fake.DeleteFileGroupStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.deleteFileGroupReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePivnetClient) DeleteFileGroupCallCount() int {
+	fake.deleteFileGroupMutex.RLock()
+	defer fake.deleteFileGroupMutex.RUnlock()
+	return len(fake.deleteFileGroupArgsForCall)
+}
+
+func (fake *FakePivnetClient) DeleteFileGroupCalls(stub func(context.Context, string, int) (pivnet.FileGroup, error)) {
+	fake.deleteFileGroupMutex.Lock()
+	defer fake.deleteFileGroupMutex.Unlock()
+	fake.DeleteFileGroupStub = stub
+}
+
+func (fake *FakePivnetClient) DeleteFileGroupArgsForCall(i int) (context.Context, string, int) {
+	fake.deleteFileGroupMutex.RLock()
+	defer fake.deleteFileGroupMutex.RUnlock()
+	argsForCall := fake.deleteFileGroupArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePivnetClient) DeleteFileGroupReturns(result1 pivnet.FileGroup, result2 error) {
+	fake.deleteFileGroupMutex.Lock()
+	defer fake.deleteFileGroupMutex.Unlock()
+	fake.DeleteFileGroupStub = nil
+	fake.deleteFileGroupReturns = struct {
+		result1 pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) DeleteFileGroupReturnsOnCall(i int, result1 pivnet.FileGroup, result2 error) {
+	fake.deleteFileGroupMutex.Lock()
+	defer fake.deleteFileGroupMutex.Unlock()
+	fake.DeleteFileGroupStub = nil
+	if fake.deleteFileGroupReturnsOnCall == nil {
+		fake.deleteFileGroupReturnsOnCall = make(map[int]struct {
+			result1 pivnet.FileGroup
+			result2 error
+		})
+	}
+	fake.deleteFileGroupReturnsOnCall[i] = struct {
+		result1 pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) DeleteProductFile(arg1 context.Context, arg2 string, arg3 int) (pivnet.ProductFile, error) {
 	fake.deleteProductFileMutex.Lock()
 	ret, specificReturn := fake.deleteProductFileReturnsOnCall[len(fake.deleteProductFileArgsForCall)]
 	fake.deleteProductFileArgsForCall = append(fake.deleteProductFileArgsForCall, struct {
-		arg1 string
-		arg2 int
-	}{arg1, arg2})
-	fake.recordInvocation("DeleteProductFile", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("DeleteProductFile", []interface{}{arg1, arg2, arg3})
 	fake.deleteProductFileMutex.Unlock()
 	if fake.DeleteProductFileStub != nil {
-		return fake.DeleteProductFileStub(arg1, arg2)
+		return fake.DeleteProductFileStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -624,17 +900,17 @@ func (fake *FakePivnetClient) DeleteProductFileCallCount() int {
 	return len(fake.deleteProductFileArgsForCall)
 }
 
-func (fake *FakePivnetClient) DeleteProductFileCalls(stub func(string, int) (pivnet.ProductFile, error)) {
+func (fake *FakePivnetClient) DeleteProductFileCalls(stub func(context.Context, string, int) (pivnet.ProductFile, error)) {
 	fake.deleteProductFileMutex.Lock()
 	defer fake.deleteProductFileMutex.Unlock()
 	fake.DeleteProductFileStub = stub
 }
 
-func (fake *FakePivnetClient) DeleteProductFileArgsForCall(i int) (string, int) {
+func (fake *FakePivnetClient) DeleteProductFileArgsForCall(i int) (context.Context, string, int) {
 	fake.deleteProductFileMutex.RLock()
 	defer fake.deleteProductFileMutex.RUnlock()
 	argsForCall := fake.deleteProductFileArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakePivnetClient) DeleteProductFileReturns(result1 pivnet.ProductFile, result2 error) {
@@ -663,16 +939,207 @@ func (fake *FakePivnetClient) DeleteProductFileReturnsOnCall(i int, result1 pivn
 	}{result1, result2}
 }
 
-func (fake *FakePivnetClient) GetAllReleases(arg1 string) ([]pivnet.Release, error) {
+func (fake *FakePivnetClient) DeleteRelease(arg1 context.Context, arg2 string, arg3 pivnet.Release) error {
+	fake.deleteReleaseMutex.Lock()
+	ret, specificReturn := fake.deleteReleaseReturnsOnCall[len(fake.deleteReleaseArgsForCall)]
+	fake.deleteReleaseArgsForCall = append(fake.deleteReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 pivnet.Release
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("DeleteRelease", []interface{}{arg1, arg2, arg3})
+	fake.deleteReleaseMutex.Unlock()
+	if fake.DeleteReleaseStub != nil {
+		return fake.DeleteReleaseStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.deleteReleaseReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakePivnetClient) DeleteReleaseCallCount() int {
+	fake.deleteReleaseMutex.RLock()
+	defer fake.deleteReleaseMutex.RUnlock()
+	return len(fake.deleteReleaseArgsForCall)
+}
+
+func (fake *FakePivnetClient) DeleteReleaseCalls(stub func(context.Context, string, pivnet.Release) error) {
+	fake.deleteReleaseMutex.Lock()
+	defer fake.deleteReleaseMutex.Unlock()
+	fake.DeleteReleaseStub = stub
+}
+
+func (fake *FakePivnetClient) DeleteReleaseArgsForCall(i int) (context.Context, string, pivnet.Release) {
+	fake.deleteReleaseMutex.RLock()
+	defer fake.deleteReleaseMutex.RUnlock()
+	argsForCall := fake.deleteReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePivnetClient) DeleteReleaseReturns(result1 error) {
+	fake.deleteReleaseMutex.Lock()
+	defer fake.deleteReleaseMutex.Unlock()
+	fake.DeleteReleaseStub = nil
+	fake.deleteReleaseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePivnetClient) DeleteReleaseReturnsOnCall(i int, result1 error) {
+	fake.deleteReleaseMutex.Lock()
+	defer fake.deleteReleaseMutex.Unlock()
+	fake.DeleteReleaseStub = nil
+	if fake.deleteReleaseReturnsOnCall == nil {
+		fake.deleteReleaseReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.deleteReleaseReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakePivnetClient) GetAllFileGroups(arg1 context.Context, arg2 string) ([]pivnet.FileGroup, error) {
+	fake.getAllFileGroupsMutex.Lock()
+	ret, specificReturn := fake.getAllFileGroupsReturnsOnCall[len(fake.getAllFileGroupsArgsForCall)]
+	fake.getAllFileGroupsArgsForCall = append(fake.getAllFileGroupsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetAllFileGroups", []interface{}{arg1, arg2})
+	fake.getAllFileGroupsMutex.Unlock()
+	if fake.GetAllFileGroupsStub != nil {
+		return fake.GetAllFileGroupsStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getAllFileGroupsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePivnetClient) GetAllFileGroupsCallCount() int {
+	fake.getAllFileGroupsMutex.RLock()
+	defer fake.getAllFileGroupsMutex.RUnlock()
+	return len(fake.getAllFileGroupsArgsForCall)
+}
+
+func (fake *FakePivnetClient) GetAllFileGroupsCalls(stub func(context.Context, string) ([]pivnet.FileGroup, error)) {
+	fake.getAllFileGroupsMutex.Lock()
+	defer fake.getAllFileGroupsMutex.Unlock()
+	fake.GetAllFileGroupsStub = stub
+}
+
+func (fake *FakePivnetClient) GetAllFileGroupsArgsForCall(i int) (context.Context, string) {
+	fake.getAllFileGroupsMutex.RLock()
+	defer fake.getAllFileGroupsMutex.RUnlock()
+	argsForCall := fake.getAllFileGroupsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePivnetClient) GetAllFileGroupsReturns(result1 []pivnet.FileGroup, result2 error) {
+	fake.getAllFileGroupsMutex.Lock()
+	defer fake.getAllFileGroupsMutex.Unlock()
+	fake.GetAllFileGroupsStub = nil
+	fake.getAllFileGroupsReturns = struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetAllFileGroupsReturnsOnCall(i int, result1 []pivnet.FileGroup, result2 error) {
+	fake.getAllFileGroupsMutex.Lock()
+	defer fake.getAllFileGroupsMutex.Unlock()
+	fake.GetAllFileGroupsStub = nil
+	if fake.getAllFileGroupsReturnsOnCall == nil {
+		fake.getAllFileGroupsReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.FileGroup
+			result2 error
+		})
+	}
+	fake.getAllFileGroupsReturnsOnCall[i] = struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetAllProductFiles(arg1 context.Context, arg2 string) ([]pivnet.ProductFile, error) {
+	fake.getAllProductFilesMutex.Lock()
+	ret, specificReturn := fake.getAllProductFilesReturnsOnCall[len(fake.getAllProductFilesArgsForCall)]
+	fake.getAllProductFilesArgsForCall = append(fake.getAllProductFilesArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetAllProductFiles", []interface{}{arg1, arg2})
+	fake.getAllProductFilesMutex.Unlock()
+	if fake.GetAllProductFilesStub != nil {
+		return fake.GetAllProductFilesStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getAllProductFilesReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePivnetClient) GetAllProductFilesCallCount() int {
+	fake.getAllProductFilesMutex.RLock()
+	defer fake.getAllProductFilesMutex.RUnlock()
+	return len(fake.getAllProductFilesArgsForCall)
+}
+
+func (fake *FakePivnetClient) GetAllProductFilesCalls(stub func(context.Context, string) ([]pivnet.ProductFile, error)) {
+	fake.getAllProductFilesMutex.Lock()
+	defer fake.getAllProductFilesMutex.Unlock()
+	fake.GetAllProductFilesStub = stub
+}
+
+func (fake *FakePivnetClient) GetAllProductFilesArgsForCall(i int) (context.Context, string) {
+	fake.getAllProductFilesMutex.RLock()
+	defer fake.getAllProductFilesMutex.RUnlock()
+	argsForCall := fake.getAllProductFilesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakePivnetClient) GetAllProductFilesReturns(result1 []pivnet.ProductFile, result2 error) {
+	fake.getAllProductFilesMutex.Lock()
+	defer fake.getAllProductFilesMutex.Unlock()
+	fake.GetAllProductFilesStub = nil
+	fake.getAllProductFilesReturns = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetAllProductFilesReturnsOnCall(i int, result1 []pivnet.ProductFile, result2 error) {
+	fake.getAllProductFilesMutex.Lock()
+	defer fake.getAllProductFilesMutex.Unlock()
+	fake.GetAllProductFilesStub = nil
+	if fake.getAllProductFilesReturnsOnCall == nil {
+		fake.getAllProductFilesReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.ProductFile
+			result2 error
+		})
+	}
+	fake.getAllProductFilesReturnsOnCall[i] = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetAllReleases(arg1 context.Context, arg2 string) ([]pivnet.Release, error) {
 	fake.getAllReleasesMutex.Lock()
 	ret, specificReturn := fake.getAllReleasesReturnsOnCall[len(fake.getAllReleasesArgsForCall)]
 	fake.getAllReleasesArgsForCall = append(fake.getAllReleasesArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("GetAllReleases", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetAllReleases", []interface{}{arg1, arg2})
 	fake.getAllReleasesMutex.Unlock()
 	if fake.GetAllReleasesStub != nil {
-		return fake.GetAllReleasesStub(arg1)
+		return fake.GetAllReleasesStub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -687,17 +1154,17 @@ func (fake *FakePivnetClient) GetAllReleasesCallCount() int {
 	return len(fake.getAllReleasesArgsForCall)
 }
 
-func (fake *FakePivnetClient) GetAllReleasesCalls(stub func(string) ([]pivnet.Release, error)) {
+func (fake *FakePivnetClient) GetAllReleasesCalls(stub func(context.Context, string) ([]pivnet.Release, error)) {
 	fake.getAllReleasesMutex.Lock()
 	defer fake.getAllReleasesMutex.Unlock()
 	fake.GetAllReleasesStub = stub
 }
 
-func (fake *FakePivnetClient) GetAllReleasesArgsForCall(i int) string {
+func (fake *FakePivnetClient) GetAllReleasesArgsForCall(i int) (context.Context, string) {
 	fake.getAllReleasesMutex.RLock()
 	defer fake.getAllReleasesMutex.RUnlock()
 	argsForCall := fake.getAllReleasesArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakePivnetClient) GetAllReleasesReturns(result1 []pivnet.Release, result2 error) {
@@ -726,17 +1193,146 @@ func (fake *FakePivnetClient) GetAllReleasesReturnsOnCall(i int, result1 []pivne
 	}{result1, result2}
 }
 
-func (fake *FakePivnetClient) GetProductFile(arg1 string, arg2 int) (pivnet.ProductFile, error) {
+func (fake *FakePivnetClient) GetAllUserGroups(arg1 context.Context) ([]pivnet.UserGroup, error) {
+	fake.getAllUserGroupsMutex.Lock()
+	ret, specificReturn := fake.getAllUserGroupsReturnsOnCall[len(fake.getAllUserGroupsArgsForCall)]
+	fake.getAllUserGroupsArgsForCall = append(fake.getAllUserGroupsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("GetAllUserGroups", []interface{}{arg1})
+	fake.getAllUserGroupsMutex.Unlock()
+	if fake.GetAllUserGroupsStub != nil {
+		return fake.GetAllUserGroupsStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getAllUserGroupsReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePivnetClient) GetAllUserGroupsCallCount() int {
+	fake.getAllUserGroupsMutex.RLock()
+	defer fake.getAllUserGroupsMutex.RUnlock()
+	return len(fake.getAllUserGroupsArgsForCall)
+}
+
+func (fake *FakePivnetClient) GetAllUserGroupsCalls(stub func(context.Context) ([]pivnet.UserGroup, error)) {
+	fake.getAllUserGroupsMutex.Lock()
+	defer fake.getAllUserGroupsMutex.Unlock()
+	fake.GetAllUserGroupsStub = stub
+}
+
+func (fake *FakePivnetClient) GetAllUserGroupsArgsForCall(i int) context.Context {
+	fake.getAllUserGroupsMutex.RLock()
+	defer fake.getAllUserGroupsMutex.RUnlock()
+	argsForCall := fake.getAllUserGroupsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakePivnetClient) GetAllUserGroupsReturns(result1 []pivnet.UserGroup, result2 error) {
+	fake.getAllUserGroupsMutex.Lock()
+	defer fake.getAllUserGroupsMutex.Unlock()
+	fake.GetAllUserGroupsStub = nil
+	fake.getAllUserGroupsReturns = struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetAllUserGroupsReturnsOnCall(i int, result1 []pivnet.UserGroup, result2 error) {
+	fake.getAllUserGroupsMutex.Lock()
+	defer fake.getAllUserGroupsMutex.Unlock()
+	fake.GetAllUserGroupsStub = nil
+	if fake.getAllUserGroupsReturnsOnCall == nil {
+		fake.getAllUserGroupsReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.UserGroup
+			result2 error
+		})
+	}
+	fake.getAllUserGroupsReturnsOnCall[i] = struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetFileGroupsForRelease(arg1 context.Context, arg2 string, arg3 int) ([]pivnet.FileGroup, error) {
+	fake.getFileGroupsForReleaseMutex.Lock()
+	ret, specificReturn := fake.getFileGroupsForReleaseReturnsOnCall[len(fake.getFileGroupsForReleaseArgsForCall)]
+	fake.getFileGroupsForReleaseArgsForCall = append(fake.getFileGroupsForReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetFileGroupsForRelease", []interface{}{arg1, arg2, arg3})
+	fake.getFileGroupsForReleaseMutex.Unlock()
+	if fake.GetFileGroupsForReleaseStub != nil {
+		return fake.GetFileGroupsForReleaseStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getFileGroupsForReleaseReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePivnetClient) GetFileGroupsForReleaseCallCount() int {
+	fake.getFileGroupsForReleaseMutex.RLock()
+	defer fake.getFileGroupsForReleaseMutex.RUnlock()
+	return len(fake.getFileGroupsForReleaseArgsForCall)
+}
+
+func (fake *FakePivnetClient) GetFileGroupsForReleaseCalls(stub func(context.Context, string, int) ([]pivnet.FileGroup, error)) {
+	fake.getFileGroupsForReleaseMutex.Lock()
+	defer fake.getFileGroupsForReleaseMutex.Unlock()
+	fake.GetFileGroupsForReleaseStub = stub
+}
+
+func (fake *FakePivnetClient) GetFileGroupsForReleaseArgsForCall(i int) (context.Context, string, int) {
+	fake.getFileGroupsForReleaseMutex.RLock()
+	defer fake.getFileGroupsForReleaseMutex.RUnlock()
+	argsForCall := fake.getFileGroupsForReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePivnetClient) GetFileGroupsForReleaseReturns(result1 []pivnet.FileGroup, result2 error) {
+	fake.getFileGroupsForReleaseMutex.Lock()
+	defer fake.getFileGroupsForReleaseMutex.Unlock()
+	fake.GetFileGroupsForReleaseStub = nil
+	fake.getFileGroupsForReleaseReturns = struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetFileGroupsForReleaseReturnsOnCall(i int, result1 []pivnet.FileGroup, result2 error) {
+	fake.getFileGroupsForReleaseMutex.Lock()
+	defer fake.getFileGroupsForReleaseMutex.Unlock()
+	fake.GetFileGroupsForReleaseStub = nil
+	if fake.getFileGroupsForReleaseReturnsOnCall == nil {
+		fake.getFileGroupsForReleaseReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.FileGroup
+			result2 error
+		})
+	}
+	fake.getFileGroupsForReleaseReturnsOnCall[i] = struct {
+		result1 []pivnet.FileGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetProductFile(arg1 context.Context, arg2 string, arg3 int) (pivnet.ProductFile, error) {
 	fake.getProductFileMutex.Lock()
 	ret, specificReturn := fake.getProductFileReturnsOnCall[len(fake.getProductFileArgsForCall)]
 	fake.getProductFileArgsForCall = append(fake.getProductFileArgsForCall, struct {
-		arg1 string
-		arg2 int
-	}{arg1, arg2})
-	fake.recordInvocation("GetProductFile", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetProductFile", []interface{}{arg1, arg2, arg3})
 	fake.getProductFileMutex.Unlock()
 	if fake.GetProductFileStub != nil {
-		return fake.GetProductFileStub(arg1, arg2)
+		return fake.GetProductFileStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -751,17 +1347,17 @@ func (fake *FakePivnetClient) GetProductFileCallCount() int {
 	return len(fake.getProductFileArgsForCall)
 }
 
-func (fake *FakePivnetClient) GetProductFileCalls(stub func(string, int) (pivnet.ProductFile, error)) {
+func (fake *FakePivnetClient) GetProductFileCalls(stub func(context.Context, string, int) (pivnet.ProductFile, error)) {
 	fake.getProductFileMutex.Lock()
 	defer fake.getProductFileMutex.Unlock()
 	fake.GetProductFileStub = stub
 }
 
-func (fake *FakePivnetClient) GetProductFileArgsForCall(i int) (string, int) {
+func (fake *FakePivnetClient) GetProductFileArgsForCall(i int) (context.Context, string, int) {
 	fake.getProductFileMutex.RLock()
 	defer fake.getProductFileMutex.RUnlock()
 	argsForCall := fake.getProductFileArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakePivnetClient) GetProductFileReturns(result1 pivnet.ProductFile, result2 error) {
@@ -790,17 +1386,148 @@ func (fake *FakePivnetClient) GetProductFileReturnsOnCall(i int, result1 pivnet.
 	}{result1, result2}
 }
 
-func (fake *FakePivnetClient) UpdateRelease(arg1 string, arg2 pivnet.Release) (pivnet.Release, error) {
+func (fake *FakePivnetClient) GetProductFilesForRelease(arg1 context.Context, arg2 string, arg3 int) ([]pivnet.ProductFile, error) {
+	fake.getProductFilesForReleaseMutex.Lock()
+	ret, specificReturn := fake.getProductFilesForReleaseReturnsOnCall[len(fake.getProductFilesForReleaseArgsForCall)]
+	fake.getProductFilesForReleaseArgsForCall = append(fake.getProductFilesForReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetProductFilesForRelease", []interface{}{arg1, arg2, arg3})
+	fake.getProductFilesForReleaseMutex.Unlock()
+	if fake.GetProductFilesForReleaseStub != nil {
+		return fake.GetProductFilesForReleaseStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getProductFilesForReleaseReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePivnetClient) GetProductFilesForReleaseCallCount() int {
+	fake.getProductFilesForReleaseMutex.RLock()
+	defer fake.getProductFilesForReleaseMutex.RUnlock()
+	return len(fake.getProductFilesForReleaseArgsForCall)
+}
+
+func (fake *FakePivnetClient) GetProductFilesForReleaseCalls(stub func(context.Context, string, int) ([]pivnet.ProductFile, error)) {
+	fake.getProductFilesForReleaseMutex.Lock()
+	defer fake.getProductFilesForReleaseMutex.Unlock()
+	fake.GetProductFilesForReleaseStub = stub
+}
+
+func (fake *FakePivnetClient) GetProductFilesForReleaseArgsForCall(i int) (context.Context, string, int) {
+	fake.getProductFilesForReleaseMutex.RLock()
+	defer fake.getProductFilesForReleaseMutex.RUnlock()
+	argsForCall := fake.getProductFilesForReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePivnetClient) GetProductFilesForReleaseReturns(result1 []pivnet.ProductFile, result2 error) {
+	fake.getProductFilesForReleaseMutex.Lock()
+	defer fake.getProductFilesForReleaseMutex.Unlock()
+	fake.GetProductFilesForReleaseStub = nil
+	fake.getProductFilesForReleaseReturns = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetProductFilesForReleaseReturnsOnCall(i int, result1 []pivnet.ProductFile, result2 error) {
+	fake.getProductFilesForReleaseMutex.Lock()
+	defer fake.getProductFilesForReleaseMutex.Unlock()
+	fake.GetProductFilesForReleaseStub = nil
+	if fake.getProductFilesForReleaseReturnsOnCall == nil {
+		fake.getProductFilesForReleaseReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.ProductFile
+			result2 error
+		})
+	}
+	fake.getProductFilesForReleaseReturnsOnCall[i] = struct {
+		result1 []pivnet.ProductFile
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetUserGroupsForRelease(arg1 context.Context, arg2 string, arg3 int) ([]pivnet.UserGroup, error) {
+	fake.getUserGroupsForReleaseMutex.Lock()
+	ret, specificReturn := fake.getUserGroupsForReleaseReturnsOnCall[len(fake.getUserGroupsForReleaseArgsForCall)]
+	fake.getUserGroupsForReleaseArgsForCall = append(fake.getUserGroupsForReleaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+		arg3 int
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetUserGroupsForRelease", []interface{}{arg1, arg2, arg3})
+	fake.getUserGroupsForReleaseMutex.Unlock()
+	if fake.GetUserGroupsForReleaseStub != nil {
+		return fake.GetUserGroupsForReleaseStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getUserGroupsForReleaseReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakePivnetClient) GetUserGroupsForReleaseCallCount() int {
+	fake.getUserGroupsForReleaseMutex.RLock()
+	defer fake.getUserGroupsForReleaseMutex.RUnlock()
+	return len(fake.getUserGroupsForReleaseArgsForCall)
+}
+
+func (fake *FakePivnetClient) GetUserGroupsForReleaseCalls(stub func(context.Context, string, int) ([]pivnet.UserGroup, error)) {
+	fake.getUserGroupsForReleaseMutex.Lock()
+	defer fake.getUserGroupsForReleaseMutex.Unlock()
+	fake.GetUserGroupsForReleaseStub = stub
+}
+
+func (fake *FakePivnetClient) GetUserGroupsForReleaseArgsForCall(i int) (context.Context, string, int) {
+	fake.getUserGroupsForReleaseMutex.RLock()
+	defer fake.getUserGroupsForReleaseMutex.RUnlock()
+	argsForCall := fake.getUserGroupsForReleaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakePivnetClient) GetUserGroupsForReleaseReturns(result1 []pivnet.UserGroup, result2 error) {
+	fake.getUserGroupsForReleaseMutex.Lock()
+	defer fake.getUserGroupsForReleaseMutex.Unlock()
+	fake.GetUserGroupsForReleaseStub = nil
+	fake.getUserGroupsForReleaseReturns = struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) GetUserGroupsForReleaseReturnsOnCall(i int, result1 []pivnet.UserGroup, result2 error) {
+	fake.getUserGroupsForReleaseMutex.Lock()
+	defer fake.getUserGroupsForReleaseMutex.Unlock()
+	fake.GetUserGroupsForReleaseStub = nil
+	if fake.getUserGroupsForReleaseReturnsOnCall == nil {
+		fake.getUserGroupsForReleaseReturnsOnCall = make(map[int]struct {
+			result1 []pivnet.UserGroup
+			result2 error
+		})
+	}
+	fake.getUserGroupsForReleaseReturnsOnCall[i] = struct {
+		result1 []pivnet.UserGroup
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakePivnetClient) UpdateRelease(arg1 context.Context, arg2 string, arg3 pivnet.Release) (pivnet.Release, error) {
 	fake.updateReleaseMutex.Lock()
 	ret, specificReturn := fake.updateReleaseReturnsOnCall[len(fake.updateReleaseArgsForCall)]
 	fake.updateReleaseArgsForCall = append(fake.updateReleaseArgsForCall, struct {
-		arg1 string
-		arg2 pivnet.Release
-	}{arg1, arg2})
-	fake.recordInvocation("UpdateRelease", []interface{}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 pivnet.Release
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("UpdateRelease", []interface{}{arg1, arg2, arg3})
 	fake.updateReleaseMutex.Unlock()
 	if fake.UpdateReleaseStub != nil {
-		return fake.UpdateReleaseStub(arg1, arg2)
+		return fake.UpdateReleaseStub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -815,17 +1542,17 @@ func (fake *FakePivnetClient) UpdateReleaseCallCount() int {
 	return len(fake.updateReleaseArgsForCall)
 }
 
-func (fake *FakePivnetClient) UpdateReleaseCalls(stub func(string, pivnet.Release) (pivnet.Release, error)) {
+func (fake *FakePivnetClient) UpdateReleaseCalls(stub func(context.Context, string, pivnet.Release) (pivnet.Release, error)) {
 	fake.updateReleaseMutex.Lock()
 	defer fake.updateReleaseMutex.Unlock()
 	fake.UpdateReleaseStub = stub
 }
 
-func (fake *FakePivnetClient) UpdateReleaseArgsForCall(i int) (string, pivnet.Release) {
+func (fake *FakePivnetClient) UpdateReleaseArgsForCall(i int) (context.Context, string, pivnet.Release) {
 	fake.updateReleaseMutex.RLock()
 	defer fake.updateReleaseMutex.RUnlock()
 	argsForCall := fake.updateReleaseArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakePivnetClient) UpdateReleaseReturns(result1 pivnet.Release, result2 error) {
@@ -863,6 +1590,8 @@ func (fake *FakePivnetClient) Invocations() map[string][][]interface{} {
 	defer fake.addProductFileToFileGroupMutex.RUnlock()
 	fake.addProductFileToReleaseMutex.RLock()
 	defer fake.addProductFileToReleaseMutex.RUnlock()
+	fake.addUserGroupToReleaseMutex.RLock()
+	defer fake.addUserGroupToReleaseMutex.RUnlock()
 	fake.createFederationTokenMutex.RLock()
 	defer fake.createFederationTokenMutex.RUnlock()
 	fake.createFileGroupMutex.RLock()
@@ -871,12 +1600,28 @@ func (fake *FakePivnetClient) Invocations() map[string][][]interface{} {
 	defer fake.createProductFileMutex.RUnlock()
 	fake.createReleaseMutex.RLock()
 	defer fake.createReleaseMutex.RUnlock()
+	fake.deleteFileGroupMutex.RLock()
+	defer fake.deleteFileGroupMutex.RUnlock()
 	fake.deleteProductFileMutex.RLock()
 	defer fake.deleteProductFileMutex.RUnlock()
+	fake.deleteReleaseMutex.RLock()
+	defer fake.deleteReleaseMutex.RUnlock()
+	fake.getAllFileGroupsMutex.RLock()
+	defer fake.getAllFileGroupsMutex.RUnlock()
+	fake.getAllProductFilesMutex.RLock()
+	defer fake.getAllProductFilesMutex.RUnlock()
 	fake.getAllReleasesMutex.RLock()
 	defer fake.getAllReleasesMutex.RUnlock()
+	fake.getAllUserGroupsMutex.RLock()
+	defer fake.getAllUserGroupsMutex.RUnlock()
+	fake.getFileGroupsForReleaseMutex.RLock()
+	defer fake.getFileGroupsForReleaseMutex.RUnlock()
 	fake.getProductFileMutex.RLock()
 	defer fake.getProductFileMutex.RUnlock()
+	fake.getProductFilesForReleaseMutex.RLock()
+	defer fake.getProductFilesForReleaseMutex.RUnlock()
+	fake.getUserGroupsForReleaseMutex.RLock()
+	defer fake.getUserGroupsForReleaseMutex.RUnlock()
 	fake.updateReleaseMutex.RLock()
 	defer fake.updateReleaseMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}